@@ -15,9 +15,11 @@
 package api
 
 import (
+	"context"
 	"sync"
 
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/leak"
 )
 
 var entryOptsPool = sync.Pool{
@@ -43,6 +45,7 @@ type EntryOptions struct {
 	slotChain    *base.SlotChain
 	args         []interface{}
 	attachments  map[interface{}]interface{}
+	ctx          context.Context
 }
 
 func (o *EntryOptions) Reset() {
@@ -53,6 +56,7 @@ func (o *EntryOptions) Reset() {
 	o.slotChain = nil
 	o.args = o.args[:0]
 	o.attachments = nil
+	o.ctx = nil
 }
 
 type EntryOption func(*EntryOptions)
@@ -129,6 +133,14 @@ func WithAttachments(data map[interface{}]interface{}) EntryOption {
 	}
 }
 
+// WithContext sets the resource entry with the given context.Context,
+// which could be retrieved from base.EntryContext.Context in slots.
+func WithContext(ctx context.Context) EntryOption {
+	return func(opts *EntryOptions) {
+		opts.ctx = ctx
+	}
+}
+
 // Entry is the basic API of Sentinel.
 func Entry(resource string, opts ...EntryOption) (*base.SentinelEntry, *base.BlockError) {
 	options := entryOptsPool.Get().(*EntryOptions)
@@ -164,6 +176,9 @@ func entry(resource string, options *EntryOptions) (*base.SentinelEntry, *base.B
 	if len(options.attachments) != 0 {
 		ctx.Input.Attachments = options.attachments
 	}
+	if options.ctx != nil {
+		ctx.SetContext(options.ctx)
+	}
 	e := base.NewSentinelEntry(ctx, rw, sc)
 	ctx.SetEntry(e)
 	r := sc.Entry(ctx)
@@ -178,6 +193,8 @@ func entry(resource string, options *EntryOptions) (*base.SentinelEntry, *base.B
 		e.Exit()
 		return nil, blockErr
 	}
-
+	if leak.Enabled() {
+		leak.Track(e)
+	}
 	return e, nil
 }
@@ -60,4 +60,12 @@
 //	    }()
 //	}
 //	<-ch
+//
+// Instead of pairing Entry with Exit and TraceError by hand, users could guard the business logic
+// with Execute, which exits the entry, records the returned error and recovers the panic automatically:
+//
+//	err := sentinel.Execute(ctx, "some-test", func(ctx context.Context) error {
+//	    // The entry of "some-test" could be retrieved by sentinel.EntryFromContext(ctx).
+//	    return doSomething(ctx)
+//	})
 package api
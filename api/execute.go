@@ -0,0 +1,72 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+)
+
+type entryContextKey struct{}
+
+// ContextWithEntry returns a copy of ctx which carries the given SentinelEntry.
+// The nil ctx is treated as context.Background().
+func ContextWithEntry(ctx context.Context, entry *base.SentinelEntry) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, entryContextKey{}, entry)
+}
+
+// EntryFromContext returns the SentinelEntry carried by ctx, or nil if absent.
+// Within the function guarded by Execute, it returns the entry of the guarded resource,
+// so that nested invocations could find their parent entry.
+func EntryFromContext(ctx context.Context) *base.SentinelEntry {
+	if ctx == nil {
+		return nil
+	}
+	entry, _ := ctx.Value(entryContextKey{}).(*base.SentinelEntry)
+	return entry
+}
+
+// Execute guards fn with the Sentinel entry of the given resource and manages the whole lifecycle of the entry:
+// the *base.BlockError is returned directly if the entry is blocked, otherwise fn is invoked with
+// a context carrying the entry (see EntryFromContext). The error returned by fn is recorded to the entry,
+// and the panic in fn is recovered, recorded as an error and returned. The entry is always exited when fn finishes.
+// The given ctx is also passed to the entry by WithContext, so that slots could access it.
+// The nil ctx is treated as context.Background().
+func Execute(ctx context.Context, resource string, fn func(ctx context.Context) error, opts ...EntryOption) (err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	e, blockErr := Entry(resource, append([]EntryOption{WithContext(ctx)}, opts...)...)
+	if blockErr != nil {
+		return blockErr
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("panic in the function guarded by resource %s: %+v", resource, r)
+		}
+		if err != nil {
+			TraceError(e, err)
+		}
+		e.Exit()
+	}()
+
+	return fn(ContextWithEntry(ctx, e))
+}
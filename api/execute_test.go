@@ -0,0 +1,133 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExecute(t *testing.T) {
+	t.Run("Pass", func(t *testing.T) {
+		sc := base.NewSlotChain()
+		ssm := &statisticSlotMock{}
+		sc.AddStatSlot(ssm)
+		ssm.On("OnEntryPassed", mock.Anything).Return()
+		ssm.On("OnCompleted", mock.Anything).Return()
+
+		err := Execute(context.Background(), "abc", func(ctx context.Context) error {
+			parent := EntryFromContext(ctx)
+			assert.NotNil(t, parent)
+			assert.Equal(t, "abc", parent.Resource().Name())
+			return Execute(ctx, "def", func(ctx context.Context) error {
+				assert.Equal(t, "def", EntryFromContext(ctx).Resource().Name())
+				return nil
+			}, WithSlotChain(sc))
+		}, WithSlotChain(sc))
+		assert.Nil(t, err)
+		ssm.AssertNumberOfCalls(t, "OnCompleted", 2)
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		type ctxKey struct{}
+		sc := base.NewSlotChain()
+		ssm := &statisticSlotMock{}
+		sc.AddStatSlot(ssm)
+		ssm.On("OnEntryPassed", mock.MatchedBy(func(ctx *base.EntryContext) bool {
+			return ctx.Context().Value(ctxKey{}) == "v"
+		})).Return()
+		ssm.On("OnCompleted", mock.Anything).Return()
+
+		err := Execute(context.WithValue(context.Background(), ctxKey{}, "v"), "abc", func(ctx context.Context) error {
+			return nil
+		}, WithSlotChain(sc))
+		assert.Nil(t, err)
+		ssm.AssertNumberOfCalls(t, "OnEntryPassed", 1)
+	})
+
+	t.Run("BizError", func(t *testing.T) {
+		sc := base.NewSlotChain()
+		ssm := &statisticSlotMock{}
+		sc.AddStatSlot(ssm)
+		ssm.On("OnEntryPassed", mock.Anything).Return()
+		ssm.On("OnCompleted", mock.MatchedBy(func(ctx *base.EntryContext) bool {
+			return ctx.Err() != nil
+		})).Return()
+
+		bizErr := errors.New("biz error")
+		err := Execute(context.Background(), "abc", func(ctx context.Context) error {
+			return bizErr
+		}, WithSlotChain(sc))
+		assert.Equal(t, bizErr, err)
+		ssm.AssertNumberOfCalls(t, "OnCompleted", 1)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		sc := base.NewSlotChain()
+		ssm := &statisticSlotMock{}
+		sc.AddStatSlot(ssm)
+		ssm.On("OnEntryPassed", mock.Anything).Return()
+		ssm.On("OnCompleted", mock.MatchedBy(func(ctx *base.EntryContext) bool {
+			return ctx.Err() != nil
+		})).Return()
+
+		err := Execute(context.Background(), "abc", func(ctx context.Context) error {
+			panic("biz panic")
+		}, WithSlotChain(sc))
+		assert.NotNil(t, err)
+		ssm.AssertNumberOfCalls(t, "OnCompleted", 1)
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		sc := base.NewSlotChain()
+		rcs := &mockRuleCheckSlot1{}
+		sc.AddRuleCheckSlot(rcs)
+		rcs.On("Check", mock.Anything).Return(base.NewTokenResultBlocked(base.BlockTypeFlow))
+
+		invoked := false
+		err := Execute(context.Background(), "abc", func(ctx context.Context) error {
+			invoked = true
+			return nil
+		}, WithSlotChain(sc))
+		assert.False(t, invoked)
+		assert.IsType(t, &base.BlockError{}, err)
+	})
+
+	t.Run("NilContext", func(t *testing.T) {
+		sc := base.NewSlotChain()
+		ssm := &statisticSlotMock{}
+		sc.AddStatSlot(ssm)
+		ssm.On("OnEntryPassed", mock.Anything).Return()
+		ssm.On("OnCompleted", mock.Anything).Return()
+
+		err := Execute(nil, "abc", func(ctx context.Context) error {
+			assert.NotNil(t, ctx)
+			assert.Equal(t, "abc", EntryFromContext(ctx).Resource().Name())
+			return nil
+		}, WithSlotChain(sc))
+		assert.Nil(t, err)
+		ssm.AssertNumberOfCalls(t, "OnCompleted", 1)
+		assert.NotNil(t, ContextWithEntry(nil, nil))
+	})
+
+	t.Run("EntryFromContext", func(t *testing.T) {
+		assert.Nil(t, EntryFromContext(context.Background()))
+	})
+}
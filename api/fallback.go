@@ -0,0 +1,52 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/fallback"
+)
+
+// EntryWithFallback guards fn with the Sentinel entry of the given resource.
+// If the entry is blocked, the fallback handler registered for the resource (see package fallback)
+// is invoked instead of fn. Otherwise fn is invoked with a context carrying the entry (see EntryFromContext),
+// and if fn returns an error, the error is recorded to the entry and
+// the fallback handler is invoked with the error. If there is no matched fallback handler,
+// the *base.BlockError or the error of fn is returned as it is. The entry is always exited when fn finishes,
+// while the panic in fn is not recovered.
+func EntryWithFallback(ctx context.Context, resource string, fn func(ctx context.Context) (interface{}, error), opts ...EntryOption) (interface{}, error) {
+	e, blockErr := Entry(resource, append([]EntryOption{WithContext(ctx)}, opts...)...)
+	if blockErr != nil {
+		return fallback.Invoke(ctx, resource, blockErr)
+	}
+	result, err := invokeWithEntry(ctx, e, fn)
+	if err != nil {
+		return fallback.Invoke(ctx, resource, err)
+	}
+	return result, nil
+}
+
+// invokeWithEntry invokes fn with the passed entry, the entry is always exited when fn finishes, even if fn panics.
+func invokeWithEntry(ctx context.Context, e *base.SentinelEntry, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	defer e.Exit()
+
+	result, err := fn(ContextWithEntry(ctx, e))
+	if err != nil {
+		TraceError(e, err)
+	}
+	return result, err
+}
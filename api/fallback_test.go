@@ -0,0 +1,103 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/fallback"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEntryWithFallback(t *testing.T) {
+	defer fallback.ClearHandlers()
+	_ = fallback.RegisterHandler("abc", func(ctx context.Context, resource string, err error) (interface{}, error) {
+		return "fallback", nil
+	})
+	_ = fallback.RegisterBlockTypeHandler("abc", base.BlockTypeFlow, func(ctx context.Context, resource string, err error) (interface{}, error) {
+		return "flow fallback", nil
+	})
+
+	t.Run("Pass", func(t *testing.T) {
+		sc := base.NewSlotChain()
+		result, err := EntryWithFallback(context.Background(), "abc", func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}, WithSlotChain(sc))
+		assert.Nil(t, err)
+		assert.Equal(t, "ok", result)
+	})
+
+	t.Run("BizError", func(t *testing.T) {
+		sc := base.NewSlotChain()
+		ssm := &statisticSlotMock{}
+		sc.AddStatSlot(ssm)
+		ssm.On("OnEntryPassed", mock.Anything).Return()
+		ssm.On("OnCompleted", mock.Anything).Return()
+
+		result, err := EntryWithFallback(context.Background(), "abc", func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("biz error")
+		}, WithSlotChain(sc))
+		assert.Nil(t, err)
+		assert.Equal(t, "fallback", result)
+		ssm.AssertNumberOfCalls(t, "OnCompleted", 1)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		sc := base.NewSlotChain()
+		ssm := &statisticSlotMock{}
+		sc.AddStatSlot(ssm)
+		ssm.On("OnEntryPassed", mock.Anything).Return()
+		ssm.On("OnCompleted", mock.Anything).Return()
+
+		assert.Panics(t, func() {
+			_, _ = EntryWithFallback(context.Background(), "abc", func(ctx context.Context) (interface{}, error) {
+				panic("oops")
+			}, WithSlotChain(sc))
+		})
+		ssm.AssertNumberOfCalls(t, "OnCompleted", 1)
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		sc := base.NewSlotChain()
+		rcs := &mockRuleCheckSlot1{}
+		sc.AddRuleCheckSlot(rcs)
+		rcs.On("Check", mock.Anything).Return(base.NewTokenResultBlocked(base.BlockTypeFlow))
+
+		result, err := EntryWithFallback(context.Background(), "abc", func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}, WithSlotChain(sc))
+		assert.Nil(t, err)
+		assert.Equal(t, "flow fallback", result)
+	})
+
+	t.Run("NoFallback", func(t *testing.T) {
+		sc := base.NewSlotChain()
+		rcs := &mockRuleCheckSlot1{}
+		sc.AddRuleCheckSlot(rcs)
+		rcs.On("Check", mock.Anything).Return(base.NewTokenResultBlocked(base.BlockTypeIsolation))
+
+		result, err := EntryWithFallback(context.Background(), "def", func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}, WithSlotChain(sc))
+		assert.Nil(t, result)
+		blockErr, ok := err.(*base.BlockError)
+		assert.True(t, ok)
+		assert.Equal(t, base.BlockTypeIsolation, blockErr.BlockType())
+	})
+}
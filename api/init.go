@@ -20,6 +20,8 @@ import (
 	"net/http"
 
 	"github.com/Danceiny/sentinel-golang/core/config"
+	"github.com/Danceiny/sentinel-golang/core/leak"
+	"github.com/Danceiny/sentinel-golang/core/log/block"
 	"github.com/Danceiny/sentinel-golang/core/log/metric"
 	"github.com/Danceiny/sentinel-golang/core/system_metric"
 	metric_exporter "github.com/Danceiny/sentinel-golang/exporter/metric"
@@ -82,11 +84,20 @@ func InitWithConfigFile(configPath string) error {
 
 // initCoreComponents init core components with global config
 func initCoreComponents() error {
+	// Bind the metrics created before (e.g. in package init) to the configured exporter.
+	if err := metric_exporter.InitExporter(); err != nil {
+		return err
+	}
 	if config.MetricLogFlushIntervalSec() > 0 {
 		if err := metric.InitTask(); err != nil {
 			return err
 		}
 	}
+	if config.BlockLogFlushIntervalSec() > 0 {
+		if err := block.InitTask(); err != nil {
+			return err
+		}
+	}
 
 	systemStatInterval := config.SystemStatCollectIntervalMs()
 	loadStatInterval := systemStatInterval
@@ -117,6 +128,10 @@ func initCoreComponents() error {
 		util.StartTimeTicker()
 	}
 
+	if config.EntryLeakDetectionEnabled() {
+		leak.InitDetector(config.EntryLeakCheckIntervalMs(), config.EntryLeakThresholdMs(), config.EntryMaxLifetimeMs())
+	}
+
 	if config.MetricExportHTTPAddr() != "" {
 		httpAddr := config.MetricExportHTTPAddr()
 		httpPath := config.MetricExportHTTPPath()
@@ -0,0 +1,59 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/config"
+	"github.com/Danceiny/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func initWithStatConfig(t *testing.T, setStat func(c *config.StatConfig)) {
+	conf := config.NewDefaultConfig()
+	conf.Sentinel.Log.Metric.FlushIntervalSec = 0
+	conf.Sentinel.Log.Block.FlushIntervalSec = 0
+	setStat(&conf.Sentinel.Stat)
+	assert.NoError(t, InitWithConfig(conf))
+	t.Cleanup(func() {
+		config.ResetGlobalConfig(config.NewDefaultConfig())
+	})
+}
+
+func TestInitWithConfigRtPercentile(t *testing.T) {
+	initWithStatConfig(t, func(c *config.StatConfig) {
+		c.RtPercentileEnabled = true
+	})
+
+	// The inbound node is created before the initialization.
+	stat.InboundNode().AddCount(base.MetricEventRt, 21)
+	assert.Equal(t, 21.0, stat.InboundNode().Percentile(0.99))
+}
+
+func TestInitWithConfigSlowRtThreshold(t *testing.T) {
+	initWithStatConfig(t, func(c *config.StatConfig) {
+		c.SlowRtThresholdMs = 10
+	})
+
+	// The inbound node is created before the initialization.
+	stat.InboundNode().AddCount(base.MetricEventRt, 21)
+	slowCount := uint64(0)
+	for _, item := range stat.InboundNode().MetricsOnCondition(func(_ uint64) bool { return true }) {
+		slowCount += item.SlowQps
+	}
+	assert.Equal(t, uint64(1), slowCount)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/Danceiny/sentinel-golang/core/hotspot"
 	"github.com/Danceiny/sentinel-golang/core/isolation"
 	"github.com/Danceiny/sentinel-golang/core/log"
+	"github.com/Danceiny/sentinel-golang/core/outlier"
 	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/Danceiny/sentinel-golang/core/system"
 )
@@ -40,11 +41,13 @@ func BuildDefaultSlotChain() *base.SlotChain {
 	sc.AddRuleCheckSlot(isolation.DefaultSlot)
 	sc.AddRuleCheckSlot(hotspot.DefaultSlot)
 	sc.AddRuleCheckSlot(circuitbreaker.DefaultSlot)
+	sc.AddRuleCheckSlot(outlier.DefaultSlot)
 
 	sc.AddStatSlot(stat.DefaultSlot)
 	sc.AddStatSlot(log.DefaultSlot)
 	sc.AddStatSlot(flow.DefaultStandaloneStatSlot)
 	sc.AddStatSlot(hotspot.DefaultConcurrencyStatSlot)
 	sc.AddStatSlot(circuitbreaker.DefaultMetricStatSlot)
+	sc.AddStatSlot(outlier.DefaultMetricStatSlot)
 	return sc
 }
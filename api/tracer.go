@@ -18,6 +18,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/Danceiny/sentinel-golang/logging"
 )
 
@@ -48,3 +49,30 @@ func TraceCallee(entry *base.SentinelEntry, address string) {
 	}
 	entry.SetPair("address", address)
 }
+
+// RecordEvent records n occurrences of the custom MetricEvent (registered by base.RegisterMetricEvent)
+// to the statistic of the resource of the given SentinelEntry, so that the event could be
+// queried by GetQPS/GetSum and used as the metric of flow rules.
+func RecordEvent(entry *base.SentinelEntry, event base.MetricEvent, n int64) {
+	defer func() {
+		if e := recover(); e != nil {
+			logging.Error(errors.Errorf("%+v", e), "Failed to api.RecordEvent()")
+			return
+		}
+	}()
+	if entry == nil || n <= 0 {
+		return
+	}
+	if !event.IsCustom() {
+		logging.Warn("[RecordEvent] Only the custom MetricEvent could be recorded", "event", event)
+		return
+	}
+	ctx := entry.Context()
+	if ctx == nil || ctx.StatNode == nil {
+		return
+	}
+	ctx.StatNode.AddCount(event, n)
+	if entry.Resource().FlowType() == base.Inbound {
+		stat.InboundNode().AddCount(event, n)
+	}
+}
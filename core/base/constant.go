@@ -17,6 +17,9 @@ package base
 // global variable
 const (
 	TotalInBoundResourceName = "__total_inbound_traffic__"
+	// OverflowResourceName is the name of the resource which the statistics of the resources
+	// beyond the max resource amount are collapsed into.
+	OverflowResourceName = "__overflow_resource__"
 
 	DefaultMaxResourceAmount uint32 = 10000
 
@@ -14,7 +14,15 @@
 
 package base
 
-import "github.com/Danceiny/sentinel-golang/util"
+import (
+	"context"
+
+	"github.com/Danceiny/sentinel-golang/util"
+)
+
+// LimitKeyPairKey is the key of the EntryContext pair which carries the limit key
+// (e.g. the hotspot parameter) that the request is blocked by.
+const LimitKeyPairKey = "sentinel.limitKey"
 
 type EntryContext struct {
 	entry *SentinelEntry
@@ -34,6 +42,8 @@ type EntryContext struct {
 	RuleCheckResult *TokenResult
 	// reserve for storing some intermediate data from the Entry execution process
 	Data map[interface{}]interface{}
+	// the context.Context of the caller, nil if absent
+	goCtx context.Context
 }
 
 func (ctx *EntryContext) SetEntry(entry *SentinelEntry) {
@@ -44,6 +54,19 @@ func (ctx *EntryContext) Entry() *SentinelEntry {
 	return ctx.entry
 }
 
+// Context returns the context.Context given by the caller of the entry,
+// context.Background() if absent.
+func (ctx *EntryContext) Context() context.Context {
+	if ctx.goCtx == nil {
+		return context.Background()
+	}
+	return ctx.goCtx
+}
+
+func (ctx *EntryContext) SetContext(goCtx context.Context) {
+	ctx.goCtx = goCtx
+}
+
 func (ctx *EntryContext) Err() error {
 	return ctx.err
 }
@@ -84,6 +107,9 @@ func (ctx *EntryContext) HalfOpenNodes() []string {
 }
 
 func (ctx *EntryContext) SetPair(key, val interface{}) {
+	if ctx.Data == nil {
+		ctx.Data = make(map[interface{}]interface{})
+	}
 	ctx.Data[key] = val
 }
 
@@ -122,6 +148,7 @@ func (ctx *EntryContext) Reset() {
 	ctx.rt = 0
 	ctx.Resource = nil
 	ctx.StatNode = nil
+	ctx.goCtx = nil
 	ctx.Input.reset()
 	if ctx.RuleCheckResult == nil {
 		ctx.RuleCheckResult = NewTokenResultPass()
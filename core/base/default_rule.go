@@ -0,0 +1,147 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package base
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Danceiny/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+// DefaultRuleSet holds the default rules of the resources registered in code and the rules loaded
+// dynamically (e.g. by the datasource) of a rule module. The default rules of a resource take effect
+// only if no rule of the resource is loaded.
+// DefaultRuleSet is not thread-safe, it should be guarded by the rule update mutex of the module.
+type DefaultRuleSet[R SentinelRule] struct {
+	module   string
+	defaults map[string][]R
+	loaded   map[string][]R
+	// overridden records the resources whose default rules are overridden by the loaded rules,
+	// so that the override is logged only once.
+	overridden map[string]struct{}
+}
+
+// NewDefaultRuleSet creates the DefaultRuleSet of the rule module, the module is used as the log prefix.
+func NewDefaultRuleSet[R SentinelRule](module string) *DefaultRuleSet[R] {
+	return &DefaultRuleSet[R]{
+		module:     module,
+		defaults:   make(map[string][]R),
+		loaded:     make(map[string][]R),
+		overridden: make(map[string]struct{}),
+	}
+}
+
+// Register registers the default rules grouped by resource, and returns the default rules which take effect,
+// i.e. the ones of the resources without loaded rules. Registering the same rules of a resource again is a no-op,
+// while the error is returned if the resource already has different default rules, which are kept.
+func (s *DefaultRuleSet[R]) Register(resRulesMap map[string][]R) (map[string][]R, error) {
+	effective := make(map[string][]R, len(resRulesMap))
+	conflicts := make([]string, 0)
+	for res, rules := range resRulesMap {
+		if existing, ok := s.defaults[res]; ok {
+			if !reflect.DeepEqual(existing, rules) {
+				conflicts = append(conflicts, res)
+			}
+			continue
+		}
+		s.defaults[res] = rules
+		if s.checkOverridden(res) {
+			continue
+		}
+		effective[res] = rules
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return effective, errors.Errorf("conflicting default rules of the resources: %s", strings.Join(conflicts, ", "))
+	}
+	return effective, nil
+}
+
+// Unregister removes the default rules of the given resources, and returns the resources whose rules
+// should be cleared, i.e. the ones without loaded rules.
+func (s *DefaultRuleSet[R]) Unregister(resources []string) []string {
+	cleared := make([]string, 0, len(resources))
+	for _, res := range resources {
+		if _, ok := s.defaults[res]; !ok {
+			continue
+		}
+		delete(s.defaults, res)
+		delete(s.overridden, res)
+		if _, ok := s.loaded[res]; !ok {
+			cleared = append(cleared, res)
+		}
+	}
+	return cleared
+}
+
+// Clear removes all the default rules, and returns the resources whose rules should be cleared.
+func (s *DefaultRuleSet[R]) Clear() []string {
+	resources := make([]string, 0, len(s.defaults))
+	for res := range s.defaults {
+		resources = append(resources, res)
+	}
+	return s.Unregister(resources)
+}
+
+// Load records the loaded rules of all the resources, and returns the rules merged with
+// the default rules of the resources absent from the loaded rules.
+func (s *DefaultRuleSet[R]) Load(resRulesMap map[string][]R) map[string][]R {
+	s.loaded = make(map[string][]R, len(resRulesMap))
+	merged := make(map[string][]R, len(resRulesMap)+len(s.defaults))
+	for res, rules := range resRulesMap {
+		s.loaded[res] = rules
+		merged[res] = rules
+	}
+	for res, rules := range s.defaults {
+		if s.checkOverridden(res) {
+			continue
+		}
+		merged[res] = rules
+	}
+	return merged
+}
+
+// LoadOfResource records the loaded rules of the resource, empty rules mean the loaded rules are cleared,
+// and returns the rules which take effect for the resource.
+func (s *DefaultRuleSet[R]) LoadOfResource(res string, rules []R) []R {
+	if len(rules) == 0 {
+		delete(s.loaded, res)
+	} else {
+		s.loaded[res] = rules
+	}
+	if s.checkOverridden(res) || len(rules) > 0 {
+		return rules
+	}
+	return s.defaults[res]
+}
+
+// checkOverridden checks whether the default rules of the resource are overridden by the loaded rules,
+// the override is logged once until the loaded rules are cleared.
+func (s *DefaultRuleSet[R]) checkOverridden(res string) bool {
+	_, hasDefaults := s.defaults[res]
+	_, hasLoaded := s.loaded[res]
+	if !hasDefaults || !hasLoaded {
+		delete(s.overridden, res)
+		return false
+	}
+	if _, ok := s.overridden[res]; !ok {
+		s.overridden[res] = struct{}{}
+		logging.Warn("["+s.module+"] The default rules of the resource are overridden by the loaded rules", "resource", res)
+	}
+	return true
+}
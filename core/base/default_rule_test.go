@@ -0,0 +1,93 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package base
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type defaultTestRule struct {
+	Resource  string
+	Threshold int
+}
+
+func (r *defaultTestRule) String() string {
+	return fmt.Sprintf("%+v", *r)
+}
+
+func (r *defaultTestRule) ResourceName() string {
+	return r.Resource
+}
+
+func TestDefaultRuleSet(t *testing.T) {
+	s := NewDefaultRuleSet[*defaultTestRule]("Test")
+	r1 := []*defaultTestRule{{Resource: "a", Threshold: 1}}
+	r2 := []*defaultTestRule{{Resource: "a", Threshold: 2}}
+	loaded := []*defaultTestRule{{Resource: "a", Threshold: 10}}
+
+	t.Run("Register", func(t *testing.T) {
+		effective, err := s.Register(map[string][]*defaultTestRule{"a": r1})
+		assert.Nil(t, err)
+		assert.Equal(t, r1, effective["a"])
+
+		// registering the same rules again is a no-op
+		effective, err = s.Register(map[string][]*defaultTestRule{"a": r1})
+		assert.Nil(t, err)
+		assert.Empty(t, effective)
+
+		// the conflicting rules are rejected and the existing ones are kept
+		_, err = s.Register(map[string][]*defaultTestRule{"a": r2, "b": r2})
+		assert.EqualError(t, err, "conflicting default rules of the resources: a")
+		assert.Equal(t, r1, s.defaults["a"])
+		assert.Equal(t, r2, s.defaults["b"])
+	})
+
+	t.Run("Load", func(t *testing.T) {
+		merged := s.Load(map[string][]*defaultTestRule{"a": loaded})
+		assert.Equal(t, loaded, merged["a"])
+		assert.Equal(t, r2, merged["b"])
+		assert.Contains(t, s.overridden, "a")
+		// the override is recorded once, until the loaded rules are cleared
+		s.Load(map[string][]*defaultTestRule{"a": loaded})
+		assert.Equal(t, 1, len(s.overridden))
+
+		merged = s.Load(nil)
+		assert.Equal(t, r1, merged["a"])
+		assert.Empty(t, s.overridden)
+	})
+
+	t.Run("LoadOfResource", func(t *testing.T) {
+		assert.Equal(t, loaded, s.LoadOfResource("a", loaded))
+		assert.Contains(t, s.overridden, "a")
+		assert.Equal(t, r1, s.LoadOfResource("a", nil))
+		assert.Empty(t, s.overridden)
+		assert.Empty(t, s.LoadOfResource("c", nil))
+	})
+
+	t.Run("Unregister", func(t *testing.T) {
+		s.LoadOfResource("a", loaded)
+		// the resource with loaded rules is not cleared
+		assert.Equal(t, []string{"b"}, s.Unregister([]string{"a", "b", "c"}))
+		assert.Empty(t, s.defaults)
+		assert.Empty(t, s.overridden)
+		assert.Equal(t, loaded, s.LoadOfResource("a", loaded))
+
+		_, _ = s.Register(map[string][]*defaultTestRule{"d": r1})
+		assert.Equal(t, []string{"d"}, s.Clear())
+		assert.Empty(t, s.defaults)
+	})
+}
@@ -16,6 +16,7 @@ package base
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 
@@ -24,6 +25,14 @@ import (
 
 type ExitHandler func(entry *SentinelEntry, ctx *EntryContext) error
 
+const (
+	entryStateLive int32 = iota
+	// entryStateCompleted indicates the statistic of the entry is released by its own Exit.
+	entryStateCompleted
+	// entryStateLeaked indicates the statistic of the entry is released by the leak detector.
+	entryStateLeaked
+)
+
 type SentinelEntry struct {
 	res *ResourceWrapper
 	// one entry bounds with one context
@@ -35,6 +44,8 @@ type SentinelEntry struct {
 	sc *SlotChain
 
 	exitCtl sync.Once
+	// state is the statistic release state of the entry, which is accessed atomically.
+	state int32
 }
 
 func NewSentinelEntry(ctx *EntryContext, rw *ResourceWrapper, sc *SlotChain) *SentinelEntry {
@@ -62,6 +73,23 @@ func (e *SentinelEntry) SetPair(key, val interface{}) {
 	}
 }
 
+// MarkCompleted marks the statistic of the entry as released by its own Exit,
+// it returns false if the entry has been marked as leaked, whose statistic has been released already.
+func (e *SentinelEntry) MarkCompleted() bool {
+	return atomic.CompareAndSwapInt32(&e.state, entryStateLive, entryStateCompleted)
+}
+
+// MarkLeaked marks the statistic of the entry as released by the leak detector,
+// it returns false if the entry has been completed.
+func (e *SentinelEntry) MarkLeaked() bool {
+	return atomic.CompareAndSwapInt32(&e.state, entryStateLive, entryStateLeaked)
+}
+
+// Leaked checks whether the entry is marked as leaked.
+func (e *SentinelEntry) Leaked() bool {
+	return atomic.LoadInt32(&e.state) == entryStateLeaked
+}
+
 func (e *SentinelEntry) Context() *EntryContext {
 	return e.ctx
 }
@@ -0,0 +1,101 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package base
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// MaxCustomMetricEventAmount is the max amount of the custom MetricEvents.
+const MaxCustomMetricEventAmount = 16
+
+var (
+	builtinMetricEventNames = [MetricEventTotal]string{"pass", "block", "complete", "error", "rt"}
+
+	customMetricEventMux   = new(sync.RWMutex)
+	customMetricEventNames = make([]string, 0, MaxCustomMetricEventAmount)
+	customMetricEventMap   = make(map[string]MetricEvent)
+)
+
+// RegisterMetricEvent registers the custom MetricEvent of the given name, e.g. "cache_miss",
+// the custom MetricEvent could be recorded by api.RecordEvent and queried like the builtin MetricEvents.
+// The event already registered with the same name is returned if the name has been registered.
+// The custom MetricEvents are expected to be registered at init.
+func RegisterMetricEvent(name string) (MetricEvent, error) {
+	if name == "" {
+		return 0, errors.New("empty MetricEvent name")
+	}
+	for i, n := range builtinMetricEventNames {
+		if n == name {
+			return 0, errors.Errorf("MetricEvent name %s conflicts with builtin MetricEvent %d", name, i)
+		}
+	}
+
+	customMetricEventMux.Lock()
+	defer customMetricEventMux.Unlock()
+	if event, ok := customMetricEventMap[name]; ok {
+		return event, nil
+	}
+	if len(customMetricEventNames) >= MaxCustomMetricEventAmount {
+		return 0, errors.Errorf("the amount of custom MetricEvents exceeds %d", MaxCustomMetricEventAmount)
+	}
+	event := MetricEventTotal + MetricEvent(len(customMetricEventNames))
+	customMetricEventNames = append(customMetricEventNames, name)
+	customMetricEventMap[name] = event
+	return event, nil
+}
+
+// MetricEventOf returns the registered custom MetricEvent of the given name.
+func MetricEventOf(name string) (MetricEvent, bool) {
+	customMetricEventMux.RLock()
+	defer customMetricEventMux.RUnlock()
+	event, ok := customMetricEventMap[name]
+	return event, ok
+}
+
+// CustomMetricEventAmount returns the amount of the registered custom MetricEvents.
+func CustomMetricEventAmount() int {
+	customMetricEventMux.RLock()
+	defer customMetricEventMux.RUnlock()
+	return len(customMetricEventNames)
+}
+
+// IsCustom indicates whether the event is a custom MetricEvent, which might not be registered.
+func (e MetricEvent) IsCustom() bool {
+	return e >= MetricEventTotal
+}
+
+func (e MetricEvent) String() string {
+	if e >= 0 && e < MetricEventTotal {
+		return builtinMetricEventNames[e]
+	}
+	customMetricEventMux.RLock()
+	defer customMetricEventMux.RUnlock()
+	if idx := int(e - MetricEventTotal); e > 0 && idx < len(customMetricEventNames) {
+		return customMetricEventNames[idx]
+	}
+	return "Undefined(" + strconv.Itoa(int(e)) + ")"
+}
+
+// resetCustomMetricEvents clears the registered custom MetricEvents, only for testing.
+func resetCustomMetricEvents() {
+	customMetricEventMux.Lock()
+	defer customMetricEventMux.Unlock()
+	customMetricEventNames = make([]string, 0, MaxCustomMetricEventAmount)
+	customMetricEventMap = make(map[string]MetricEvent)
+}
@@ -0,0 +1,64 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package base
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterMetricEvent(t *testing.T) {
+	resetCustomMetricEvents()
+	defer resetCustomMetricEvents()
+
+	_, err := RegisterMetricEvent("")
+	assert.Error(t, err)
+	_, err = RegisterMetricEvent("pass")
+	assert.Error(t, err)
+
+	cacheMiss, err := RegisterMetricEvent("cache_miss")
+	assert.NoError(t, err)
+	assert.Equal(t, MetricEventTotal, cacheMiss)
+	assert.True(t, cacheMiss.IsCustom())
+	assert.Equal(t, "cache_miss", cacheMiss.String())
+	retry, err := RegisterMetricEvent("retry")
+	assert.NoError(t, err)
+	assert.Equal(t, MetricEventTotal+1, retry)
+
+	// Registering the same name again returns the registered event.
+	e, err := RegisterMetricEvent("cache_miss")
+	assert.NoError(t, err)
+	assert.Equal(t, cacheMiss, e)
+	assert.Equal(t, 2, CustomMetricEventAmount())
+
+	e, ok := MetricEventOf("retry")
+	assert.True(t, ok)
+	assert.Equal(t, retry, e)
+	_, ok = MetricEventOf("pass")
+	assert.False(t, ok)
+
+	assert.Equal(t, "complete", MetricEventComplete.String())
+	assert.False(t, MetricEventRt.IsCustom())
+	assert.Equal(t, "Undefined(20)", MetricEvent(20).String())
+
+	for i := CustomMetricEventAmount(); i < MaxCustomMetricEventAmount; i++ {
+		_, err = RegisterMetricEvent("event" + strconv.Itoa(i))
+		assert.NoError(t, err)
+	}
+	_, err = RegisterMetricEvent("overflow")
+	assert.Error(t, err)
+}
@@ -15,6 +15,7 @@
 package base
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -38,6 +39,36 @@ type MetricItem struct {
 	AvgRt           uint64
 	OccupiedPassQps uint64
 	Concurrency     uint32
+	// P50Rt, P90Rt and P99Rt are the RT percentiles, 0 if the RT percentile statistic is disabled.
+	P50Rt uint64
+	P90Rt uint64
+	P99Rt uint64
+	// MinRt and MaxRt are the min and max RT of the completed requests, 0 if no request is completed.
+	MinRt uint64
+	MaxRt uint64
+	// SlowQps is the count of the slow requests (see config.SlowRtThresholdMs).
+	SlowQps uint64
+}
+
+// metricItemJSON is the JSON Lines layout of the MetricItem.
+type metricItemJSON struct {
+	Timestamp       uint64 `json:"timestamp"`
+	Time            string `json:"time"`
+	Resource        string `json:"resource"`
+	PassQps         uint64 `json:"passQps"`
+	BlockQps        uint64 `json:"blockQps"`
+	CompleteQps     uint64 `json:"completeQps"`
+	ErrorQps        uint64 `json:"errorQps"`
+	AvgRt           uint64 `json:"avgRt"`
+	OccupiedPassQps uint64 `json:"occupiedPassQps"`
+	Concurrency     uint32 `json:"concurrency"`
+	Classification  int32  `json:"classification"`
+	P50Rt           uint64 `json:"p50Rt"`
+	P90Rt           uint64 `json:"p90Rt"`
+	P99Rt           uint64 `json:"p99Rt"`
+	MinRt           uint64 `json:"minRt"`
+	MaxRt           uint64 `json:"maxRt"`
+	SlowQps         uint64 `json:"slowQps"`
 }
 
 type MetricItemRetriever interface {
@@ -49,10 +80,11 @@ func (m *MetricItem) ToFatString() (string, error) {
 	timeStr := util.FormatTimeMillis(m.Timestamp)
 	// All "|" in the resource name will be replaced with "_"
 	finalName := strings.ReplaceAll(m.Resource, "|", "_")
-	_, err := fmt.Fprintf(&b, "%d|%s|%s|%d|%d|%d|%d|%d|%d|%d|%d",
+	_, err := fmt.Fprintf(&b, "%d|%s|%s|%d|%d|%d|%d|%d|%d|%d|%d|%d|%d|%d|%d|%d|%d",
 		m.Timestamp, timeStr, finalName, m.PassQps,
 		m.BlockQps, m.CompleteQps, m.ErrorQps, m.AvgRt,
-		m.OccupiedPassQps, m.Concurrency, m.Classification)
+		m.OccupiedPassQps, m.Concurrency, m.Classification,
+		m.P50Rt, m.P90Rt, m.P99Rt, m.MinRt, m.MaxRt, m.SlowQps)
 	if err != nil {
 		return "", err
 	}
@@ -72,6 +104,70 @@ func (m *MetricItem) ToThinString() (string, error) {
 	return b.String(), nil
 }
 
+// ToJSONString formats the MetricItem as a JSON object in a single line.
+func (m *MetricItem) ToJSONString() (string, error) {
+	b, err := json.Marshal(&metricItemJSON{
+		Timestamp:       m.Timestamp,
+		Time:            util.FormatTimeMillis(m.Timestamp),
+		Resource:        m.Resource,
+		PassQps:         m.PassQps,
+		BlockQps:        m.BlockQps,
+		CompleteQps:     m.CompleteQps,
+		ErrorQps:        m.ErrorQps,
+		AvgRt:           m.AvgRt,
+		OccupiedPassQps: m.OccupiedPassQps,
+		Concurrency:     m.Concurrency,
+		Classification:  m.Classification,
+		P50Rt:           m.P50Rt,
+		P90Rt:           m.P90Rt,
+		P99Rt:           m.P99Rt,
+		MinRt:           m.MinRt,
+		MaxRt:           m.MaxRt,
+		SlowQps:         m.SlowQps,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// MetricItemFromString parses the MetricItem from a line of the metric log,
+// which could be either in the fat string format or in the JSON format.
+func MetricItemFromString(line string) (*MetricItem, error) {
+	if strings.HasPrefix(line, "{") {
+		return MetricItemFromJSONString(line)
+	}
+	return MetricItemFromFatString(line)
+}
+
+func MetricItemFromJSONString(line string) (*MetricItem, error) {
+	if len(line) == 0 {
+		return nil, errors.New("invalid metric line: empty string")
+	}
+	j := &metricItemJSON{}
+	if err := json.Unmarshal([]byte(line), j); err != nil {
+		return nil, err
+	}
+	return &MetricItem{
+		Resource:        j.Resource,
+		Classification:  j.Classification,
+		Timestamp:       j.Timestamp,
+		PassQps:         j.PassQps,
+		BlockQps:        j.BlockQps,
+		CompleteQps:     j.CompleteQps,
+		ErrorQps:        j.ErrorQps,
+		AvgRt:           j.AvgRt,
+		OccupiedPassQps: j.OccupiedPassQps,
+		Concurrency:     j.Concurrency,
+		P50Rt:           j.P50Rt,
+		P90Rt:           j.P90Rt,
+		P99Rt:           j.P99Rt,
+		MinRt:           j.MinRt,
+		MaxRt:           j.MaxRt,
+		SlowQps:         j.SlowQps,
+	}, nil
+}
+
 func MetricItemFromFatString(line string) (*MetricItem, error) {
 	if len(line) == 0 {
 		return nil, errors.New("invalid metric line: empty string")
@@ -134,5 +230,29 @@ func MetricItemFromFatString(line string) (*MetricItem, error) {
 		}
 		item.Classification = int32(cl)
 	}
+	// The RT percentiles are absent in the metric logs written by the former versions.
+	if len(arr) >= 14 {
+		percentiles := make([]uint64, 3)
+		for i := range percentiles {
+			p, err := strconv.ParseUint(arr[11+i], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			percentiles[i] = p
+		}
+		item.P50Rt, item.P90Rt, item.P99Rt = percentiles[0], percentiles[1], percentiles[2]
+	}
+	// The min/max RT and the slow count are absent in the metric logs written by the former versions.
+	if len(arr) >= 17 {
+		rts := make([]uint64, 3)
+		for i := range rts {
+			v, err := strconv.ParseUint(arr[14+i], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			rts[i] = v
+		}
+		item.MinRt, item.MaxRt, item.SlowQps = rts[0], rts[1], rts[2]
+	}
 	return item, nil
 }
@@ -34,6 +34,30 @@ func TestMetricItemFromFatStringLegal(t *testing.T) {
 	assert.Equal(t, int32(1), item1.Classification)
 }
 
+func TestMetricItemFatStringWithPercentiles(t *testing.T) {
+	item := &MetricItem{Resource: "abc", Timestamp: 1564382218000, PassQps: 4, AvgRt: 25, P50Rt: 20, P90Rt: 40, P99Rt: 95,
+		MinRt: 3, MaxRt: 120, SlowQps: 2}
+	s, err := item.ToFatString()
+	assert.NoError(t, err)
+	parsed, err := MetricItemFromFatString(s)
+	assert.NoError(t, err)
+	assert.Equal(t, item, parsed)
+}
+
+func TestMetricItemFromFatStringOfFormerLayouts(t *testing.T) {
+	item, err := MetricItemFromFatString("1564382218000|2019-07-29 14:36:58|abc|4|9|3|0|25|0|2|1|20|40|95")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(95), item.P99Rt)
+	assert.Equal(t, uint64(0), item.MaxRt)
+	assert.Equal(t, uint64(0), item.SlowQps)
+
+	item, err = MetricItemFromFatString("1564382218000|2019-07-29 14:36:58|abc|4|9|3|0|25|0|2|1|20|40|95|3|120|2")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), item.MinRt)
+	assert.Equal(t, uint64(120), item.MaxRt)
+	assert.Equal(t, uint64(2), item.SlowQps)
+}
+
 func TestMetricItemFromFatStringIllegal(t *testing.T) {
 	line1 := "1564382218000|2019-07-29 14:36:58|foo|baz|4|9|3|0|25|0|2|1"
 	_, err := MetricItemFromFatString(line1)
@@ -43,3 +67,44 @@ func TestMetricItemFromFatStringIllegal(t *testing.T) {
 	_, err = MetricItemFromFatString(line2)
 	assert.Error(t, err, "Error should occur when parsing malformed line")
 }
+
+func TestMetricItemJSONString(t *testing.T) {
+	item := &MetricItem{
+		Resource:        "/foo/*",
+		Classification:  1,
+		Timestamp:       1564382218000,
+		PassQps:         4,
+		BlockQps:        9,
+		CompleteQps:     3,
+		AvgRt:           25,
+		OccupiedPassQps: 1,
+		Concurrency:     2,
+		P99Rt:           100,
+		MaxRt:           200,
+		SlowQps:         1,
+	}
+	s, err := item.ToJSONString()
+	assert.NoError(t, err)
+	assert.Contains(t, s, `"resource":"/foo/*"`)
+	assert.Contains(t, s, `"passQps":4`)
+	assert.NotContains(t, s, "\n")
+
+	parsed, err := MetricItemFromString(s)
+	assert.NoError(t, err)
+	assert.Equal(t, item, parsed)
+
+	_, err = MetricItemFromJSONString(`{"timestamp":"abc"}`)
+	assert.Error(t, err)
+	_, err = MetricItemFromJSONString("")
+	assert.Error(t, err)
+}
+
+func TestMetricItemFromString(t *testing.T) {
+	item, err := MetricItemFromString("1564382218000|2019-07-29 14:36:58|/foo/*|4|9|3|0|25|0|2|1")
+	assert.NoError(t, err)
+	assert.Equal(t, "/foo/*", item.Resource)
+	assert.Equal(t, uint64(9), item.BlockQps)
+
+	_, err = MetricItemFromString("{invalid")
+	assert.Error(t, err)
+}
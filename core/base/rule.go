@@ -21,3 +21,10 @@ type SentinelRule interface {
 
 	ResourceName() string
 }
+
+// IdentifiedRule is implemented by the rules which carry a rule ID.
+type IdentifiedRule interface {
+	SentinelRule
+
+	RuleID() string
+}
@@ -15,6 +15,7 @@
 package circuitbreaker
 
 import (
+	"math/rand"
 	"reflect"
 	"sync/atomic"
 
@@ -52,10 +53,15 @@ var (
 		"circuit_breaker_state_changed_total",
 		"Circuit breaker total state change count",
 		[]string{"resource", "from_state", "to_state"})
+	stateGauge = metric_exporter.NewGauge(
+		"circuit_breaker_state",
+		"Circuit breaker current state (0: Closed, 1: HalfOpen, 2: Open)",
+		[]string{"resource", "strategy"})
 )
 
 func init() {
 	metric_exporter.Register(stateChangedCounter)
+	metric_exporter.Register(stateGauge)
 }
 
 func newState() *State {
@@ -90,6 +96,10 @@ func (s *State) cas(expect State, update State) bool {
 	return atomic.CompareAndSwapInt32((*int32)(s), int32(expect), int32(update))
 }
 
+func (s *State) swap(update State) State {
+	return State(atomic.SwapInt32((*int32)(s), int32(update)))
+}
+
 // StateChangeListener listens on the circuit breaker state change event
 type StateChangeListener interface {
 	// OnTransformToClosed is triggered when circuit breaker state transformed to Closed.
@@ -141,6 +151,45 @@ type circuitBreakerBase struct {
 	curProbeNumber uint64
 	// state is the state machine of circuit breaker
 	state *State
+
+	// probeStrategy is the probe policy when the circuit breaker is half-open.
+	probeStrategy ProbeStrategy
+	// probeRatio is the ratio of traffic that could pass during the probe window, only for ProbeByRatio.
+	probeRatio float64
+	// probeWindowMs is the duration of the probe window, only for ProbeByRatio.
+	probeWindowMs uint32
+	// probeWindowStartMs is the time the current probe window starts.
+	probeWindowStartMs uint64
+	// probeTotalCount and probeFailedCount are the completed and failed requests in the current probe window.
+	probeTotalCount  uint64
+	probeFailedCount uint64
+
+	// rampRatios and rampStepMs describe the gradual recovery ramp after the circuit breaker is closed.
+	rampRatios []float64
+	rampStepMs uint32
+	// rampStartMs is the time the current recovery ramp starts, 0 means there is no ongoing ramp.
+	rampStartMs uint64
+
+	// pinned indicates whether the state is pinned by manual control (1 means pinned).
+	// All the automatic state transformations are suppressed while the state is pinned.
+	pinned int32
+	// pinExpireMs is the time the pinned state expires, 0 means the state is pinned until it is released.
+	pinExpireMs uint64
+}
+
+func newCircuitBreakerBase(r *Rule) circuitBreakerBase {
+	return circuitBreakerBase{
+		rule:                 r,
+		retryTimeoutMs:       r.RetryTimeoutMs,
+		nextRetryTimestampMs: 0,
+		state:                newState(),
+		probeNumber:          r.ProbeNum,
+		probeStrategy:        r.ProbeStrategy,
+		probeRatio:           r.ProbeRatio,
+		probeWindowMs:        r.ProbeWindowMs,
+		rampRatios:           r.RecoveryRampRatios,
+		rampStepMs:           r.RecoveryRampStepMs,
+	}
 }
 
 func (b *circuitBreakerBase) BoundRule() *Rule {
@@ -167,16 +216,144 @@ func (b *circuitBreakerBase) resetCurProbeNum() {
 	atomic.StoreUint64(&b.curProbeNumber, 0)
 }
 
+// tryPass is the state machine based checking shared by all circuit breakers.
+func (b *circuitBreakerBase) tryPass(ctx *base.EntryContext) bool {
+	curStatus := b.CurrentState()
+	if b.isPinned() {
+		return curStatus != Open
+	}
+	if curStatus == Closed {
+		return b.rampPass()
+	} else if curStatus == Open {
+		// switch state to half-open to probe if retry timeout
+		if b.retryTimeoutArrived() && b.fromOpenToHalfOpen(ctx) {
+			return true
+		}
+	} else if curStatus == HalfOpen {
+		if b.probeStrategy == ProbeByRatio {
+			return rand.Float64() < b.probeRatio
+		}
+		return b.probeNumber > 0
+	}
+	return false
+}
+
+// rampPass checks whether the request could pass during the recovery ramp.
+// Return true if there is no ongoing ramp.
+func (b *circuitBreakerBase) rampPass() bool {
+	startMs := atomic.LoadUint64(&b.rampStartMs)
+	if startMs == 0 || len(b.rampRatios) == 0 {
+		return true
+	}
+	step := (util.CurrentTimeMillis() - startMs) / uint64(b.rampStepMs)
+	if step >= uint64(len(b.rampRatios)) {
+		// the ramp finished
+		atomic.CompareAndSwapUint64(&b.rampStartMs, startMs, 0)
+		return true
+	}
+	return rand.Float64() < b.rampRatios[step]
+}
+
+func (b *circuitBreakerBase) resetProbeWindow() {
+	atomic.StoreUint64(&b.probeTotalCount, 0)
+	atomic.StoreUint64(&b.probeFailedCount, 0)
+	atomic.StoreUint64(&b.probeWindowStartMs, util.CurrentTimeMillis())
+}
+
+// onRatioProbeComplete records a completed request in the probe window of ProbeByRatio probe strategy.
+// Return true along with the statistics of the probe window only if the probe window has elapsed,
+// then the caller should close or re-open the circuit breaker based on the statistics.
+func (b *circuitBreakerBase) onRatioProbeComplete(failed bool) (elapsed bool, totalCount, failedCount uint64) {
+	if failed {
+		atomic.AddUint64(&b.probeFailedCount, 1)
+	}
+	atomic.AddUint64(&b.probeTotalCount, 1)
+	if util.CurrentTimeMillis() < atomic.LoadUint64(&b.probeWindowStartMs)+uint64(b.probeWindowMs) {
+		return false, 0, 0
+	}
+	return true, atomic.LoadUint64(&b.probeTotalCount), atomic.LoadUint64(&b.probeFailedCount)
+}
+
+// isPinned checks whether the state is pinned by manual control, the expired pin would be released.
+func (b *circuitBreakerBase) isPinned() bool {
+	if atomic.LoadInt32(&b.pinned) == 0 {
+		return false
+	}
+	expireMs := atomic.LoadUint64(&b.pinExpireMs)
+	if expireMs > 0 && util.CurrentTimeMillis() >= expireMs {
+		atomic.CompareAndSwapInt32(&b.pinned, 1, 0)
+		return false
+	}
+	return true
+}
+
+// pin forces the circuit breaker into the target state and pins the state for the given ttl (in ms).
+// The state is pinned until unpin is called if ttlMs is 0.
+func (b *circuitBreakerBase) pin(target State, ttlMs uint64) {
+	expireMs := uint64(0)
+	if ttlMs > 0 {
+		expireMs = util.CurrentTimeMillis() + ttlMs
+	}
+	atomic.StoreUint64(&b.pinExpireMs, expireMs)
+	atomic.StoreInt32(&b.pinned, 1)
+
+	prev := b.state.swap(target)
+	b.resetCurProbeNum()
+	atomic.StoreUint64(&b.rampStartMs, 0)
+	if target == Open {
+		b.updateNextRetryTimestamp()
+	}
+	if prev == target {
+		return
+	}
+	for _, listener := range stateChangeListeners {
+		switch target {
+		case Closed:
+			listener.OnTransformToClosed(prev, *b.rule)
+		case Open:
+			listener.OnTransformToOpen(prev, *b.rule, nil)
+		case HalfOpen:
+			listener.OnTransformToHalfOpen(prev, *b.rule)
+		}
+	}
+	b.recordStateChange(prev, target)
+}
+
+// recordStateChange exports the state transformation of the circuit breaker.
+func (b *circuitBreakerBase) recordStateChange(prev, target State) {
+	stateChangedCounter.Add(float64(1), b.rule.Resource, prev.String(), target.String())
+	stateGauge.Set(float64(target), b.rule.Resource, b.rule.Strategy.String())
+}
+
+// stateExporter is implemented by the built-in circuit breakers, which export their current state.
+type stateExporter interface {
+	exportState()
+}
+
+func (b *circuitBreakerBase) exportState() {
+	stateGauge.Set(float64(b.CurrentState()), b.rule.Resource, b.rule.Strategy.String())
+}
+
+// unpin releases the pinned state, the automatic state transformations take effect again.
+func (b *circuitBreakerBase) unpin() {
+	atomic.StoreInt32(&b.pinned, 0)
+	atomic.StoreUint64(&b.pinExpireMs, 0)
+}
+
 // fromClosedToOpen updates circuit breaker state machine from closed to open.
 // Return true only if current goroutine successfully accomplished the transformation.
 func (b *circuitBreakerBase) fromClosedToOpen(snapshot interface{}) bool {
+	if b.isPinned() {
+		return false
+	}
 	if b.state.cas(Closed, Open) {
+		atomic.StoreUint64(&b.rampStartMs, 0)
 		b.updateNextRetryTimestamp()
 		for _, listener := range stateChangeListeners {
 			listener.OnTransformToOpen(Closed, *b.rule, snapshot)
 		}
 
-		stateChangedCounter.Add(float64(1), b.BoundRule().Resource, "Closed", "Open")
+		b.recordStateChange(Closed, Open)
 		return true
 	}
 	return false
@@ -185,7 +362,13 @@ func (b *circuitBreakerBase) fromClosedToOpen(snapshot interface{}) bool {
 // fromOpenToHalfOpen updates circuit breaker state machine from open to half-open.
 // Return true only if current goroutine successfully accomplished the transformation.
 func (b *circuitBreakerBase) fromOpenToHalfOpen(ctx *base.EntryContext) bool {
+	if b.isPinned() {
+		return false
+	}
 	if b.state.cas(Open, HalfOpen) {
+		if b.probeStrategy == ProbeByRatio {
+			b.resetProbeWindow()
+		}
 		for _, listener := range stateChangeListeners {
 			listener.OnTransformToHalfOpen(Open, *b.rule)
 		}
@@ -198,16 +381,17 @@ func (b *circuitBreakerBase) fromOpenToHalfOpen(ctx *base.EntryContext) bool {
 			// if the current circuit breaker performs the probe through this entry, but the entry was blocked,
 			// this hook will guarantee current circuit breaker state machine will rollback to Open from Half-Open
 			entry.WhenExit(func(entry *base.SentinelEntry, ctx *base.EntryContext) error {
-				if ctx.IsBlocked() && b.state.cas(HalfOpen, Open) {
+				if ctx.IsBlocked() && !b.isPinned() && b.state.cas(HalfOpen, Open) {
 					for _, listener := range stateChangeListeners {
 						listener.OnTransformToOpen(HalfOpen, *b.rule, 1.0)
 					}
+					b.recordStateChange(HalfOpen, Open)
 				}
 				return nil
 			})
 		}
 
-		stateChangedCounter.Add(float64(1), b.BoundRule().Resource, "Open", "HalfOpen")
+		b.recordStateChange(Open, HalfOpen)
 		return true
 	}
 	return false
@@ -216,6 +400,9 @@ func (b *circuitBreakerBase) fromOpenToHalfOpen(ctx *base.EntryContext) bool {
 // fromHalfOpenToOpen updates circuit breaker state machine from half-open to open.
 // Return true only if current goroutine successfully accomplished the transformation.
 func (b *circuitBreakerBase) fromHalfOpenToOpen(snapshot interface{}) bool {
+	if b.isPinned() {
+		return false
+	}
 	if b.state.cas(HalfOpen, Open) {
 		b.resetCurProbeNum()
 		b.updateNextRetryTimestamp()
@@ -223,7 +410,7 @@ func (b *circuitBreakerBase) fromHalfOpenToOpen(snapshot interface{}) bool {
 			listener.OnTransformToOpen(HalfOpen, *b.rule, snapshot)
 		}
 
-		stateChangedCounter.Add(float64(1), b.BoundRule().Resource, "HalfOpen", "Open")
+		b.recordStateChange(HalfOpen, Open)
 		return true
 	}
 	return false
@@ -232,13 +419,19 @@ func (b *circuitBreakerBase) fromHalfOpenToOpen(snapshot interface{}) bool {
 // fromHalfOpenToClosed updates circuit breaker state machine from half-open to closed
 // Return true only if current goroutine successfully accomplished the transformation.
 func (b *circuitBreakerBase) fromHalfOpenToClosed() bool {
+	if b.isPinned() {
+		return false
+	}
 	if b.state.cas(HalfOpen, Closed) {
 		b.resetCurProbeNum()
+		if len(b.rampRatios) > 0 {
+			atomic.StoreUint64(&b.rampStartMs, util.CurrentTimeMillis())
+		}
 		for _, listener := range stateChangeListeners {
 			listener.OnTransformToClosed(HalfOpen, *b.rule)
 		}
 
-		stateChangedCounter.Add(float64(1), b.BoundRule().Resource, "HalfOpen", "Closed")
+		b.recordStateChange(HalfOpen, Closed)
 		return true
 	}
 	return false
@@ -255,13 +448,7 @@ type slowRtCircuitBreaker struct {
 
 func newSlowRtCircuitBreakerWithStat(r *Rule, stat *slowRequestLeapArray) *slowRtCircuitBreaker {
 	return &slowRtCircuitBreaker{
-		circuitBreakerBase: circuitBreakerBase{
-			rule:                 r,
-			retryTimeoutMs:       r.RetryTimeoutMs,
-			nextRetryTimestampMs: 0,
-			state:                newState(),
-			probeNumber:          r.ProbeNum,
-		},
+		circuitBreakerBase:  newCircuitBreakerBase(r),
 		stat:                stat,
 		maxAllowedRt:        r.MaxAllowedRtMs,
 		maxSlowRequestRatio: r.Threshold,
@@ -288,18 +475,7 @@ func (b *slowRtCircuitBreaker) BoundStat() interface{} {
 
 // TryPass checks circuit breaker based on state machine of circuit breaker.
 func (b *slowRtCircuitBreaker) TryPass(ctx *base.EntryContext) bool {
-	curStatus := b.CurrentState()
-	if curStatus == Closed {
-		return true
-	} else if curStatus == Open {
-		// switch state to half-open to probe if retry timeout
-		if b.retryTimeoutArrived() && b.fromOpenToHalfOpen(ctx) {
-			return true
-		}
-	} else if curStatus == HalfOpen && b.probeNumber > 0 {
-		return true
-	}
-	return false
+	return b.tryPass(ctx)
 }
 
 func (b *slowRtCircuitBreaker) OnRequestComplete(rt uint64, _ error) {
@@ -330,6 +506,17 @@ func (b *slowRtCircuitBreaker) OnRequestComplete(rt uint64, _ error) {
 	if curStatus == Open {
 		return
 	} else if curStatus == HalfOpen {
+		if b.probeStrategy == ProbeByRatio {
+			if elapsed, probeTotal, probeSlow := b.onRatioProbeComplete(rt > b.maxAllowedRt); elapsed {
+				probeSlowRatio := float64(probeSlow) / float64(probeTotal)
+				if probeSlowRatio > b.maxSlowRequestRatio || util.Float64Equals(probeSlowRatio, b.maxSlowRequestRatio) {
+					b.fromHalfOpenToOpen(probeSlowRatio)
+				} else if b.fromHalfOpenToClosed() {
+					b.resetMetric()
+				}
+			}
+			return
+		}
 		if rt > b.maxAllowedRt {
 			// fail to probe
 			b.fromHalfOpenToOpen(1.0)
@@ -447,13 +634,7 @@ type errorRatioCircuitBreaker struct {
 
 func newErrorRatioCircuitBreakerWithStat(r *Rule, stat *errorCounterLeapArray) *errorRatioCircuitBreaker {
 	return &errorRatioCircuitBreaker{
-		circuitBreakerBase: circuitBreakerBase{
-			rule:                 r,
-			retryTimeoutMs:       r.RetryTimeoutMs,
-			nextRetryTimestampMs: 0,
-			state:                newState(),
-			probeNumber:          r.ProbeNum,
-		},
+		circuitBreakerBase:  newCircuitBreakerBase(r),
 		minRequestAmount:    r.MinRequestAmount,
 		errorRatioThreshold: r.Threshold,
 		stat:                stat,
@@ -477,18 +658,7 @@ func (b *errorRatioCircuitBreaker) BoundStat() interface{} {
 }
 
 func (b *errorRatioCircuitBreaker) TryPass(ctx *base.EntryContext) bool {
-	curStatus := b.CurrentState()
-	if curStatus == Closed {
-		return true
-	} else if curStatus == Open {
-		// switch state to half-open to probe if retry timeout
-		if b.retryTimeoutArrived() && b.fromOpenToHalfOpen(ctx) {
-			return true
-		}
-	} else if curStatus == HalfOpen && b.probeNumber > 0 {
-		return true
-	}
-	return false
+	return b.tryPass(ctx)
 }
 
 func (b *errorRatioCircuitBreaker) OnRequestComplete(_ uint64, err error) {
@@ -519,6 +689,17 @@ func (b *errorRatioCircuitBreaker) OnRequestComplete(_ uint64, err error) {
 		return
 	}
 	if curStatus == HalfOpen {
+		if b.probeStrategy == ProbeByRatio {
+			if elapsed, probeTotal, probeError := b.onRatioProbeComplete(err != nil); elapsed {
+				probeErrorRatio := float64(probeError) / float64(probeTotal)
+				if probeErrorRatio > b.errorRatioThreshold || util.Float64Equals(probeErrorRatio, b.errorRatioThreshold) {
+					b.fromHalfOpenToOpen(probeErrorRatio)
+				} else if b.fromHalfOpenToClosed() {
+					b.resetMetric()
+				}
+			}
+			return
+		}
 		if err == nil {
 			b.addCurProbeNum()
 			if b.probeNumber == 0 || atomic.LoadUint64(&b.curProbeNumber) >= b.probeNumber {
@@ -632,13 +813,7 @@ type errorCountCircuitBreaker struct {
 
 func newErrorCountCircuitBreakerWithStat(r *Rule, stat *errorCounterLeapArray) *errorCountCircuitBreaker {
 	return &errorCountCircuitBreaker{
-		circuitBreakerBase: circuitBreakerBase{
-			rule:                 r,
-			retryTimeoutMs:       r.RetryTimeoutMs,
-			nextRetryTimestampMs: 0,
-			state:                newState(),
-			probeNumber:          r.ProbeNum,
-		},
+		circuitBreakerBase:  newCircuitBreakerBase(r),
 		minRequestAmount:    r.MinRequestAmount,
 		errorCountThreshold: uint64(r.Threshold),
 		stat:                stat,
@@ -662,18 +837,7 @@ func (b *errorCountCircuitBreaker) BoundStat() interface{} {
 }
 
 func (b *errorCountCircuitBreaker) TryPass(ctx *base.EntryContext) bool {
-	curStatus := b.CurrentState()
-	if curStatus == Closed {
-		return true
-	} else if curStatus == Open {
-		// switch state to half-open to probe if retry timeout
-		if b.retryTimeoutArrived() && b.fromOpenToHalfOpen(ctx) {
-			return true
-		}
-	} else if curStatus == HalfOpen && b.probeNumber > 0 {
-		return true
-	}
-	return false
+	return b.tryPass(ctx)
 }
 
 func (b *errorCountCircuitBreaker) OnRequestComplete(_ uint64, err error) {
@@ -702,6 +866,16 @@ func (b *errorCountCircuitBreaker) OnRequestComplete(_ uint64, err error) {
 		return
 	}
 	if curStatus == HalfOpen {
+		if b.probeStrategy == ProbeByRatio {
+			if elapsed, _, probeError := b.onRatioProbeComplete(err != nil); elapsed {
+				if probeError >= b.errorCountThreshold {
+					b.fromHalfOpenToOpen(probeError)
+				} else if b.fromHalfOpenToClosed() {
+					b.resetMetric()
+				}
+			}
+			return
+		}
 		if err == nil {
 			b.addCurProbeNum()
 			if b.probeNumber == 0 || atomic.LoadUint64(&b.curProbeNumber) >= b.probeNumber {
@@ -16,11 +16,15 @@ package circuitbreaker
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Danceiny/sentinel-golang/core/base"
 	sbase "github.com/Danceiny/sentinel-golang/core/stat/base"
+	metric_exporter "github.com/Danceiny/sentinel-golang/exporter/metric"
 	"github.com/Danceiny/sentinel-golang/logging"
 	"github.com/Danceiny/sentinel-golang/util"
 	"github.com/stretchr/testify/assert"
@@ -509,3 +513,154 @@ func TestFromHalfOpenToOpen(t *testing.T) {
 		stateChangeListenerMock.MethodCalled("OnTransformToOpen", HalfOpen, mock.Anything, mock.Anything)
 	})
 }
+
+func TestProbeByRatio(t *testing.T) {
+	ClearStateChangeListeners()
+	t.Run("ProbeByRatio_Closed", func(t *testing.T) {
+		clock := util.NewMockClock()
+		util.SetClock(clock)
+		defer util.SetClock(util.NewRealClock())
+
+		r := &Rule{
+			Resource:         "abc",
+			Strategy:         ErrorRatio,
+			RetryTimeoutMs:   3000,
+			MinRequestAmount: 10,
+			StatIntervalMs:   10000,
+			Threshold:        0.5,
+			ProbeStrategy:    ProbeByRatio,
+			ProbeRatio:       1.0,
+			ProbeWindowMs:    1000,
+		}
+		b, err := newErrorRatioCircuitBreaker(r)
+		assert.Nil(t, err)
+
+		b.state.set(Open)
+		ctx := &base.EntryContext{
+			Resource: base.NewResourceWrapper("abc", base.ResTypeCommon, base.Inbound),
+		}
+		e := base.NewSentinelEntry(ctx, base.NewResourceWrapper("abc", base.ResTypeCommon, base.Inbound), nil)
+		ctx.SetEntry(e)
+		assert.True(t, b.TryPass(ctx))
+		assert.True(t, b.CurrentState() == HalfOpen)
+		for i := 0; i < 10; i++ {
+			assert.True(t, b.TryPass(ctx))
+			b.OnRequestComplete(1, nil)
+		}
+		b.OnRequestComplete(1, errors.New("errorRatio"))
+		// the probe window doesn't elapse
+		assert.True(t, b.CurrentState() == HalfOpen)
+
+		clock.Sleep(time.Second)
+		b.OnRequestComplete(1, nil)
+		assert.True(t, b.CurrentState() == Closed)
+	})
+
+	t.Run("ProbeByRatio_Open", func(t *testing.T) {
+		clock := util.NewMockClock()
+		util.SetClock(clock)
+		defer util.SetClock(util.NewRealClock())
+
+		r := &Rule{
+			Resource:         "abc",
+			Strategy:         SlowRequestRatio,
+			RetryTimeoutMs:   3000,
+			MinRequestAmount: 10,
+			StatIntervalMs:   10000,
+			MaxAllowedRtMs:   50,
+			Threshold:        0.5,
+			ProbeStrategy:    ProbeByRatio,
+			ProbeRatio:       1.0,
+			ProbeWindowMs:    1000,
+		}
+		b, err := newSlowRtCircuitBreaker(r)
+		assert.Nil(t, err)
+
+		b.state.set(Open)
+		ctx := &base.EntryContext{
+			Resource: base.NewResourceWrapper("abc", base.ResTypeCommon, base.Inbound),
+		}
+		e := base.NewSentinelEntry(ctx, base.NewResourceWrapper("abc", base.ResTypeCommon, base.Inbound), nil)
+		ctx.SetEntry(e)
+		assert.True(t, b.TryPass(ctx))
+		b.OnRequestComplete(100, nil)
+		b.OnRequestComplete(1, nil)
+		assert.True(t, b.CurrentState() == HalfOpen)
+
+		clock.Sleep(time.Second)
+		b.OnRequestComplete(100, nil)
+		assert.True(t, b.CurrentState() == Open)
+	})
+}
+
+func TestRecoveryRamp(t *testing.T) {
+	ClearStateChangeListeners()
+	clock := util.NewMockClock()
+	util.SetClock(clock)
+	defer util.SetClock(util.NewRealClock())
+
+	r := &Rule{
+		Resource:           "abc",
+		Strategy:           ErrorCount,
+		RetryTimeoutMs:     3000,
+		MinRequestAmount:   10,
+		StatIntervalMs:     10000,
+		Threshold:          1.0,
+		RecoveryRampRatios: []float64{0.1, 0.5},
+		RecoveryRampStepMs: 1000,
+	}
+	b, err := newErrorCountCircuitBreaker(r)
+	assert.Nil(t, err)
+	assert.True(t, b.rampPass())
+
+	b.state.set(HalfOpen)
+	b.OnRequestComplete(1, nil)
+	assert.True(t, b.CurrentState() == Closed)
+	assert.True(t, atomic.LoadUint64(&b.rampStartMs) > 0)
+
+	clock.Sleep(2 * time.Second)
+	assert.True(t, b.TryPass(base.NewEmptyEntryContext()))
+	assert.True(t, atomic.LoadUint64(&b.rampStartMs) == 0)
+}
+
+func exportedMetrics(t *testing.T) string {
+	rec := httptest.NewRecorder()
+	metric_exporter.HTTPHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
+
+func TestStateGauge(t *testing.T) {
+	ClearStateChangeListeners()
+	assert.NoError(t, metric_exporter.SetExporter(metric_exporter.PrometheusExporterName))
+	defer func() {
+		assert.NoError(t, metric_exporter.SetExporter(metric_exporter.EmptyExporterName))
+	}()
+
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:         "abc",
+			Strategy:         ErrorCount,
+			RetryTimeoutMs:   3000,
+			MinRequestAmount: 1,
+			StatIntervalMs:   10000,
+			Threshold:        1.0,
+		},
+	})
+	assert.NoError(t, err)
+	defer ClearRules()
+
+	stateOf := `sentinel_go_circuit_breaker_state\{[^}]*resource="abc",strategy="ErrorCount"\} `
+	assert.Regexp(t, stateOf+`0\n`, exportedMetrics(t))
+
+	cb := getBreakersOfResource("abc")[0]
+	cb.OnRequestComplete(1, errors.New("biz error"))
+	assert.True(t, cb.CurrentState() == Open)
+	metrics := exportedMetrics(t)
+	assert.Regexp(t, stateOf+`2\n`, metrics)
+	assert.Regexp(t, `sentinel_go_circuit_breaker_state_changed_total\{[^}]*from_state="Closed",[^}]*resource="abc",to_state="Open"\} 1\n`, metrics)
+
+	// the states of the removed circuit breakers are cleared
+	assert.NoError(t, ClearRules())
+	assert.NotRegexp(t, `sentinel_go_circuit_breaker_state\{`, exportedMetrics(t))
+}
@@ -30,6 +30,19 @@
 //  2. Open: the circuit breaker is broken, all entries are blocked. After retry timeout, circuit breaker switches state to Half-Open and allows one entry to probe whether the resource returns to its expected state.
 //  3. Half-Open: the circuit breaker is in a temporary state of probing, only one entry is allowed to access resource, others are blocked.
 //
+// The probe policy of Half-Open state is configured by Rule.ProbeStrategy:
+//
+//  1. ProbeByCount (default): ProbeNum entries are allowed to probe, any failed probe re-opens the circuit breaker.
+//  2. ProbeByRatio: ProbeRatio of the traffic is allowed to probe during ProbeWindowMs. When the window elapses,
+//     the circuit breaker is closed or re-opened based on the rule strategy evaluated over the probe window.
+//
+// Rule.RecoveryRampRatios enables a gradual ramp (e.g. 10% -> 50% -> 100%) after the circuit breaker is closed from Half-Open,
+// so that the recovering resource would not be flooded by the whole traffic at once.
+//
+// During incidents, circuit breakers could be controlled manually by ForceOpen/ForceClose (by resource)
+// or ForceOpenByRuleId/ForceCloseByRuleId (by rule id) with an optional TTL. The state is pinned and
+// all the automatic state transformations are suppressed until the TTL expires or Release is called.
+//
 // Sentinel circuit breaker provides the listener to observe events of state changes.
 //
 //	type StateChangeListener interface {
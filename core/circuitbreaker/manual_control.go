@@ -0,0 +1,137 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circuitbreaker
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Danceiny/sentinel-golang/logging"
+)
+
+// manualController is implemented by the circuit breakers which support manual state control.
+// All the built-in circuit breakers implement it.
+type manualController interface {
+	pin(target State, ttlMs uint64)
+	unpin()
+	isPinned() bool
+}
+
+var (
+	_ manualController = &slowRtCircuitBreaker{}
+	_ manualController = &errorRatioCircuitBreaker{}
+	_ manualController = &errorCountCircuitBreaker{}
+)
+
+// ForceOpen forces all the circuit breakers of the given resource into Open state,
+// so that all the requests are blocked. The state is pinned for the given ttl (rounded up to milliseconds),
+// all the automatic state transformations are suppressed while the state is pinned.
+// If ttl is not positive, the state is pinned until Release is called.
+//
+// Note that the pinned state is discarded if the circuit breaker is rebuilt by reloading a different rule.
+func ForceOpen(resource string, ttl time.Duration) error {
+	return pinBreakers(getBreakersOfResource(resource), Open, ttl, "resource", resource)
+}
+
+// ForceClose forces all the circuit breakers of the given resource into Closed state,
+// so that all the requests are permitted. The state is pinned for the given ttl,
+// all the automatic state transformations are suppressed while the state is pinned.
+// If ttl is not positive, the state is pinned until Release is called.
+func ForceClose(resource string, ttl time.Duration) error {
+	return pinBreakers(getBreakersOfResource(resource), Closed, ttl, "resource", resource)
+}
+
+// Release releases the pinned state of all the circuit breakers of the given resource,
+// the automatic state transformations take effect again.
+func Release(resource string) error {
+	return unpinBreakers(getBreakersOfResource(resource), "resource", resource)
+}
+
+// ForceOpenByRuleId works like ForceOpen, but only affects the circuit breakers whose rule id is the given id.
+func ForceOpenByRuleId(id string, ttl time.Duration) error {
+	return pinBreakers(getBreakersOfRuleId(id), Open, ttl, "ruleId", id)
+}
+
+// ForceCloseByRuleId works like ForceClose, but only affects the circuit breakers whose rule id is the given id.
+func ForceCloseByRuleId(id string, ttl time.Duration) error {
+	return pinBreakers(getBreakersOfRuleId(id), Closed, ttl, "ruleId", id)
+}
+
+// ReleaseByRuleId works like Release, but only affects the circuit breakers whose rule id is the given id.
+func ReleaseByRuleId(id string) error {
+	return unpinBreakers(getBreakersOfRuleId(id), "ruleId", id)
+}
+
+// IsPinned checks whether the state of any circuit breaker of the given resource is pinned by manual control.
+func IsPinned(resource string) bool {
+	for _, cb := range getBreakersOfResource(resource) {
+		if mc, ok := cb.(manualController); ok && mc.isPinned() {
+			return true
+		}
+	}
+	return false
+}
+
+func getBreakersOfRuleId(id string) []CircuitBreaker {
+	ret := make([]CircuitBreaker, 0)
+	if len(id) == 0 {
+		return ret
+	}
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+	for _, resCBs := range breakers {
+		for _, cb := range resCBs {
+			if cb.BoundRule().Id == id {
+				ret = append(ret, cb)
+			}
+		}
+	}
+	return ret
+}
+
+func pinBreakers(cbs []CircuitBreaker, target State, ttl time.Duration, keyName, key string) error {
+	if len(cbs) == 0 {
+		return errors.Errorf("no circuit breaker found for %s: %s", keyName, key)
+	}
+	ttlMs := uint64(0)
+	if ttl > 0 {
+		// round up, so that the sub-millisecond ttl doesn't turn into 0 which pins the state forever
+		ttlMs = uint64((ttl + time.Millisecond - 1) / time.Millisecond)
+	}
+	for _, cb := range cbs {
+		mc, ok := cb.(manualController)
+		if !ok {
+			logging.Warn("[CircuitBreaker pinBreakers] Ignoring the circuit breaker which doesn't support manual control", "rule", cb.BoundRule())
+			continue
+		}
+		mc.pin(target, ttlMs)
+	}
+	logging.Info("[CircuitBreaker] Circuit breakers were pinned by manual control", keyName, key, "state", target.String(), "ttl", ttl)
+	return nil
+}
+
+func unpinBreakers(cbs []CircuitBreaker, keyName, key string) error {
+	if len(cbs) == 0 {
+		return errors.Errorf("no circuit breaker found for %s: %s", keyName, key)
+	}
+	for _, cb := range cbs {
+		if mc, ok := cb.(manualController); ok {
+			mc.unpin()
+		}
+	}
+	logging.Info("[CircuitBreaker] Circuit breakers were released from manual control", keyName, key)
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestForceOpenAndClose(t *testing.T) {
+	ClearStateChangeListeners()
+	stateChangeListenerMock := &StateChangeListenerMock{}
+	stateChangeListenerMock.On("OnTransformToOpen", Closed, mock.Anything, mock.Anything).Return()
+	RegisterStateChangeListeners(stateChangeListenerMock)
+	defer ClearStateChangeListeners()
+
+	_, err := LoadRules([]*Rule{
+		{
+			Id:               "rule1",
+			Resource:         "abc",
+			Strategy:         ErrorCount,
+			RetryTimeoutMs:   1,
+			MinRequestAmount: 1,
+			StatIntervalMs:   10000,
+			Threshold:        1.0,
+		},
+	})
+	assert.Nil(t, err)
+	defer ClearRules()
+
+	t.Run("ForceOpen", func(t *testing.T) {
+		assert.Nil(t, ForceOpen("abc", 0))
+		assert.True(t, IsPinned("abc"))
+		cb := getBreakersOfResource("abc")[0]
+		assert.True(t, cb.CurrentState() == Open)
+		// the pinned Open state would not switch to HalfOpen even if retry timeout arrives
+		util.Sleep(2 * time.Millisecond)
+		assert.False(t, cb.TryPass(base.NewEmptyEntryContext()))
+		assert.True(t, cb.CurrentState() == Open)
+		stateChangeListenerMock.MethodCalled("OnTransformToOpen", Closed, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ForceCloseByRuleId", func(t *testing.T) {
+		assert.Nil(t, ForceCloseByRuleId("rule1", 0))
+		cb := getBreakersOfResource("abc")[0]
+		assert.True(t, cb.CurrentState() == Closed)
+		// the pinned Closed state would not switch to Open even if the threshold is exceeded
+		cb.OnRequestComplete(1, errors.New("biz error"))
+		cb.OnRequestComplete(1, errors.New("biz error"))
+		assert.True(t, cb.CurrentState() == Closed)
+		assert.True(t, cb.TryPass(base.NewEmptyEntryContext()))
+	})
+
+	t.Run("Release", func(t *testing.T) {
+		assert.Nil(t, Release("abc"))
+		assert.False(t, IsPinned("abc"))
+		cb := getBreakersOfResource("abc")[0]
+		cb.OnRequestComplete(1, errors.New("biz error"))
+		assert.True(t, cb.CurrentState() == Open)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		assert.NotNil(t, ForceOpen("def", 0))
+		assert.NotNil(t, ForceOpenByRuleId("rule2", 0))
+		assert.NotNil(t, ReleaseByRuleId(""))
+	})
+}
+
+func TestPinExpiration(t *testing.T) {
+	ClearStateChangeListeners()
+	clock := util.NewMockClock()
+	util.SetClock(clock)
+	defer util.SetClock(util.NewRealClock())
+
+	r := &Rule{
+		Resource:         "abc",
+		Strategy:         ErrorCount,
+		RetryTimeoutMs:   3000,
+		MinRequestAmount: 1,
+		StatIntervalMs:   10000,
+		Threshold:        1.0,
+	}
+	b, err := newErrorCountCircuitBreaker(r)
+	assert.Nil(t, err)
+
+	b.pin(Open, 1000)
+	assert.True(t, b.isPinned())
+	assert.True(t, b.CurrentState() == Open)
+
+	clock.Sleep(time.Second)
+	assert.False(t, b.isPinned())
+	assert.True(t, b.CurrentState() == Open)
+}
+
+func TestPinSubMillisecondTTL(t *testing.T) {
+	ClearStateChangeListeners()
+	clock := util.NewMockClock()
+	util.SetClock(clock)
+	defer util.SetClock(util.NewRealClock())
+
+	r := &Rule{
+		Resource:         "abc",
+		Strategy:         ErrorCount,
+		RetryTimeoutMs:   3000,
+		MinRequestAmount: 1,
+		StatIntervalMs:   10000,
+		Threshold:        1.0,
+	}
+	b, err := newErrorCountCircuitBreaker(r)
+	assert.Nil(t, err)
+
+	assert.Nil(t, pinBreakers([]CircuitBreaker{b}, Open, time.Microsecond, "resource", "abc"))
+	assert.True(t, b.isPinned())
+	assert.True(t, b.CurrentState() == Open)
+
+	// the ttl is rounded up to 1ms rather than pinning the state forever
+	clock.Sleep(time.Millisecond)
+	assert.False(t, b.isPinned())
+}
@@ -46,6 +46,29 @@ func (s Strategy) String() string {
 	}
 }
 
+// ProbeStrategy represents how the circuit breaker probes the resource when it is half-open.
+type ProbeStrategy uint32
+
+const (
+	// ProbeByCount lets ProbeNum sequential requests pass when half-open, any failed probe re-opens the circuit breaker.
+	ProbeByCount ProbeStrategy = iota
+	// ProbeByRatio lets a percentage (ProbeRatio) of the traffic pass during the probe window (ProbeWindowMs).
+	// When the window elapses, the circuit breaker is closed or re-opened based on the rule strategy
+	// evaluated over the requests completed in the probe window.
+	ProbeByRatio
+)
+
+func (s ProbeStrategy) String() string {
+	switch s {
+	case ProbeByCount:
+		return "ProbeByCount"
+	case ProbeByRatio:
+		return "ProbeByRatio"
+	default:
+		return "Undefined"
+	}
+}
+
 // Rule encompasses the fields of circuit breaking rule.
 type Rule struct {
 	// unique id
@@ -83,6 +106,20 @@ type Rule struct {
 	// if err occurs during the probe, the circuit breaker is opened immediately.
 	// otherwise,the circuit breaker is closed only after the number of probes is reached
 	ProbeNum uint64 `json:"probeNum"`
+	// ProbeStrategy represents the probe policy when the circuit breaker is half-open, ProbeByCount by default.
+	ProbeStrategy ProbeStrategy `json:"probeStrategy"`
+	// ProbeRatio is the ratio of traffic (valid range: (0.0, 1.0]) that could pass when the circuit breaker is half-open.
+	// ProbeRatio only takes effect for ProbeByRatio probe strategy.
+	ProbeRatio float64 `json:"probeRatio"`
+	// ProbeWindowMs is the duration (in ms) of the probe window when the circuit breaker is half-open.
+	// ProbeWindowMs only takes effect for ProbeByRatio probe strategy.
+	ProbeWindowMs uint32 `json:"probeWindowMs"`
+	// RecoveryRampRatios represents the gradual ramp of traffic after the circuit breaker is closed from half-open,
+	// e.g. [0.1, 0.5] lets 10% of traffic pass in the first step, 50% in the second step and then all the traffic.
+	// Each ratio must be in range (0.0, 1.0]. If it is empty, all the traffic passes once the circuit breaker is closed.
+	RecoveryRampRatios []float64 `json:"recoveryRampRatios,omitempty"`
+	// RecoveryRampStepMs is the duration (in ms) of each step of the recovery ramp.
+	RecoveryRampStepMs uint32 `json:"recoveryRampStepMs"`
 }
 
 func (r *Rule) String() string {
@@ -103,6 +140,10 @@ func (r *Rule) ResourceName() string {
 	return r.Resource
 }
 
+func (r *Rule) RuleID() string {
+	return r.Id
+}
+
 // Check whether the fields shared by all rule strategy types are consistent
 func (r *Rule) isEqualsToBase(newRule *Rule) bool {
 	if newRule == nil {
@@ -110,7 +151,22 @@ func (r *Rule) isEqualsToBase(newRule *Rule) bool {
 	}
 	return r.Resource == newRule.Resource && r.Strategy == newRule.Strategy && r.RetryTimeoutMs == newRule.RetryTimeoutMs &&
 		r.MinRequestAmount == newRule.MinRequestAmount && r.StatIntervalMs == newRule.StatIntervalMs && r.StatSlidingWindowBucketCount == newRule.StatSlidingWindowBucketCount &&
-		r.ProbeNum == newRule.ProbeNum
+		r.ProbeNum == newRule.ProbeNum && r.isProbePolicyEqualsTo(newRule)
+}
+
+// Check whether the half-open probe policy and the recovery ramp are consistent
+func (r *Rule) isProbePolicyEqualsTo(newRule *Rule) bool {
+	if r.ProbeStrategy != newRule.ProbeStrategy || !util.Float64Equals(r.ProbeRatio, newRule.ProbeRatio) ||
+		r.ProbeWindowMs != newRule.ProbeWindowMs || r.RecoveryRampStepMs != newRule.RecoveryRampStepMs ||
+		len(r.RecoveryRampRatios) != len(newRule.RecoveryRampRatios) {
+		return false
+	}
+	for i, ratio := range r.RecoveryRampRatios {
+		if !util.Float64Equals(ratio, newRule.RecoveryRampRatios[i]) {
+			return false
+		}
+	}
+	return true
 }
 
 func (r *Rule) isEqualsTo(newRule *Rule) bool {
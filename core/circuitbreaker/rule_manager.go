@@ -172,6 +172,7 @@ func LoadRulesOfResource(res string, rules []*Rule) (bool, error) {
 		delete(breakers, res)
 		delete(breakerRules, res)
 		updateMux.Unlock()
+		refreshStateGauge()
 		logging.Info("[CircuitBreaker] clear resource level rules", "resource", res)
 		return true, nil
 	}
@@ -274,12 +275,29 @@ func onRuleUpdate(rawResRulesMap map[string][]*Rule) (err error) {
 	breakers = newBreakers
 	updateMux.Unlock()
 	currentRules = rawResRulesMap
+	refreshStateGauge()
 
 	logging.Debug("[CircuitBreaker onRuleUpdate] Time statistics(ns) for updating circuit breaker rule", "timeCost", util.CurrentTimeNano()-start)
 	LogRuleUpdate(validResRulesMap)
 	return nil
 }
 
+// refreshStateGauge exports the current states of all the circuit breakers,
+// the states of the removed circuit breakers are cleared.
+func refreshStateGauge() {
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	stateGauge.Reset()
+	for _, cbs := range breakers {
+		for _, cb := range cbs {
+			if e, ok := cb.(stateExporter); ok {
+				e.exportState()
+			}
+		}
+	}
+}
+
 func onResourceRuleUpdate(res string, rawResRules []*Rule) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -318,6 +336,7 @@ func onResourceRuleUpdate(res string, rawResRules []*Rule) (err error) {
 	}
 	updateMux.Unlock()
 	currentRules[res] = rawResRules
+	refreshStateGauge()
 
 	logging.Debug("[CircuitBreaker onResourceRuleUpdate] Time statistics(ns) for updating circuit breaker rule", "timeCost", util.CurrentTimeNano()-start)
 	logging.Info("[CircuitBreaker] load resource level rules", "resource", res, "validResRules", validResRules)
@@ -468,6 +487,26 @@ func IsValidRule(r *Rule) error {
 	if r.Strategy == ErrorRatio && r.Threshold > 1.0 {
 		return errors.New("invalid error ratio threshold (valid range: [0.0, 1.0])")
 	}
+	if r.ProbeStrategy == ProbeByRatio {
+		if r.ProbeRatio <= 0.0 || r.ProbeRatio > 1.0 {
+			return errors.New("invalid probe ratio (valid range: (0.0, 1.0])")
+		}
+		if r.ProbeWindowMs == 0 {
+			return errors.New("invalid ProbeWindowMs")
+		}
+	} else if r.ProbeStrategy != ProbeByCount {
+		return errors.New("invalid ProbeStrategy")
+	}
+	if len(r.RecoveryRampRatios) > 0 {
+		if r.RecoveryRampStepMs == 0 {
+			return errors.New("invalid RecoveryRampStepMs")
+		}
+		for _, ratio := range r.RecoveryRampRatios {
+			if ratio <= 0.0 || ratio > 1.0 {
+				return errors.New("invalid recovery ramp ratio (valid range: (0.0, 1.0])")
+			}
+		}
+	}
 	if r.StatSlidingWindowBucketCount != 0 && r.StatIntervalMs%r.StatSlidingWindowBucketCount != 0 {
 		logging.Warn("[CircuitBreaker IsValidRule] The following must be true: StatIntervalMs % StatSlidingWindowBucketCount == 0. StatSlidingWindowBucketCount will be replaced by 1", "rule", r)
 	}
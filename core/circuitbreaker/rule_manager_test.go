@@ -130,6 +130,36 @@ func Test_isApplicableRule_invalid(t *testing.T) {
 			t.Errorf("RuleManager.isApplicable() = %v", got)
 		}
 	})
+	t.Run("probeByRatioRule_isApplicable_false", func(t *testing.T) {
+		rule := &Rule{
+			Resource:         "abc04",
+			Strategy:         ErrorRatio,
+			RetryTimeoutMs:   1000,
+			MinRequestAmount: 5,
+			StatIntervalMs:   1000,
+			Threshold:        0.3,
+			ProbeStrategy:    ProbeByRatio,
+			ProbeRatio:       1.5,
+			ProbeWindowMs:    1000,
+		}
+		if got := IsValidRule(rule); got == nil {
+			t.Errorf("RuleManager.isApplicable() = %v", got)
+		}
+	})
+	t.Run("recoveryRampRule_isApplicable_false", func(t *testing.T) {
+		rule := &Rule{
+			Resource:           "abc05",
+			Strategy:           ErrorRatio,
+			RetryTimeoutMs:     1000,
+			MinRequestAmount:   5,
+			StatIntervalMs:     1000,
+			Threshold:          0.3,
+			RecoveryRampRatios: []float64{0.1, 0.5},
+		}
+		if got := IsValidRule(rule); got == nil {
+			t.Errorf("RuleManager.isApplicable() = %v", got)
+		}
+	})
 }
 
 func Test_onUpdateRules(t *testing.T) {
@@ -208,10 +208,66 @@ func MetricExportHTTPAddr() string {
 	return globalCfg.MetricExportHTTPAddr()
 }
 
+func MetricExporterName() string {
+	return globalCfg.MetricExporterName()
+}
+
 func MetricExportHTTPPath() string {
 	return globalCfg.MetricExportHTTPPath()
 }
 
+func MetricExportRtBucketsMs() []float64 {
+	return globalCfg.MetricExportRtBucketsMs()
+}
+
+func MetricExportMaxLabelCardinality() uint32 {
+	return globalCfg.MetricExportMaxLabelCardinality()
+}
+
+func StatsDNetwork() string {
+	return globalCfg.StatsDNetwork()
+}
+
+func StatsDAddr() string {
+	return globalCfg.StatsDAddr()
+}
+
+func StatsDPrefix() string {
+	return globalCfg.StatsDPrefix()
+}
+
+func StatsDFlavor() string {
+	return globalCfg.StatsDFlavor()
+}
+
+func StatsDFlushIntervalMs() uint32 {
+	return globalCfg.StatsDFlushIntervalMs()
+}
+
+func StatsDMaxPacketSize() uint32 {
+	return globalCfg.StatsDMaxPacketSize()
+}
+
+func RtPercentileEnabled() bool {
+	return globalCfg.RtPercentileEnabled()
+}
+
+func SlowRtThresholdMs() uint32 {
+	return globalCfg.SlowRtThresholdMs()
+}
+
+func MaxResourceAmount() uint32 {
+	return globalCfg.MaxResourceAmount()
+}
+
+func ResourceOverflowPolicy() string {
+	return globalCfg.ResourceOverflowPolicy()
+}
+
+func MetricLogFormat() string {
+	return globalCfg.MetricLogFormat()
+}
+
 func MetricLogFlushIntervalSec() uint32 {
 	return globalCfg.MetricLogFlushIntervalSec()
 }
@@ -224,6 +280,18 @@ func MetricLogMaxFileAmount() uint32 {
 	return globalCfg.MetricLogMaxFileAmount()
 }
 
+func BlockLogFlushIntervalSec() uint32 {
+	return globalCfg.BlockLogFlushIntervalSec()
+}
+
+func BlockLogSingleFileMaxSize() uint64 {
+	return globalCfg.BlockLogSingleFileMaxSize()
+}
+
+func BlockLogMaxFileAmount() uint32 {
+	return globalCfg.BlockLogMaxFileAmount()
+}
+
 func SystemStatCollectIntervalMs() uint32 {
 	return globalCfg.SystemStatCollectIntervalMs()
 }
@@ -262,3 +330,19 @@ func MetricStatisticIntervalMs() uint32 {
 func MetricStatisticSampleCount() uint32 {
 	return globalCfg.MetricStatisticSampleCount()
 }
+
+func EntryLeakDetectionEnabled() bool {
+	return globalCfg.EntryLeakDetectionEnabled()
+}
+
+func EntryLeakCheckIntervalMs() uint32 {
+	return globalCfg.EntryLeakCheckIntervalMs()
+}
+
+func EntryLeakThresholdMs() uint32 {
+	return globalCfg.EntryLeakThresholdMs()
+}
+
+func EntryMaxLifetimeMs() uint32 {
+	return globalCfg.EntryMaxLifetimeMs()
+}
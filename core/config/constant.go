@@ -31,9 +31,38 @@ const (
 	DefaultMetricLogFlushIntervalSec   uint32 = 1
 	DefaultMetricLogSingleFileMaxSize  uint64 = 1024 * 1024 * 50
 	DefaultMetricLogMaxFileAmount      uint32 = 8
+	DefaultBlockLogFlushIntervalSec    uint32 = 1
+	DefaultBlockLogSingleFileMaxSize   uint64 = 1024 * 1024 * 50
+	DefaultBlockLogMaxFileAmount       uint32 = 6
 	DefaultSystemStatCollectIntervalMs uint32 = 1000
 	DefaultLoadStatCollectIntervalMs   uint32 = 1000
 	DefaultCpuStatCollectIntervalMs    uint32 = 1000
 	DefaultMemoryStatCollectIntervalMs uint32 = 150
 	DefaultWarmUpColdFactor            uint32 = 3
+	DefaultEntryLeakCheckIntervalMs    uint32 = 1000
+	DefaultEntryLeakThresholdMs        uint32 = 60000
+	DefaultStatsDFlushIntervalMs       uint32 = 1000
+	// DefaultStatsDMaxPacketSize is the max payload size of the UDP packet which avoids the fragmentation.
+	DefaultStatsDMaxPacketSize uint32 = 1432
+
+	// MetricLogFormatFat represents the pipe-delimited metric log format.
+	MetricLogFormatFat = "fat"
+	// MetricLogFormatJSON represents the JSON Lines metric log format.
+	MetricLogFormatJSON = "json"
+
+	// ResourceOverflowPolicyCollapse collapses the statistics of the resources beyond the max resource amount
+	// into the overflow resource, except the resources with rules.
+	ResourceOverflowPolicyCollapse = "collapse"
+	// ResourceOverflowPolicyWarn keeps creating the statistics of the resources beyond the max resource amount
+	// with warning logs.
+	ResourceOverflowPolicyWarn = "warn"
+
+	DefaultStatsDNetwork = "udp"
+	DefaultStatsDPrefix  = "sentinel_go"
+	DefaultStatsDFlavor  = "dogstatsd"
+)
+
+var (
+	// DefaultMetricExportRtBucketsMs represents the default buckets (in milliseconds) of the exported RT histograms.
+	DefaultMetricExportRtBucketsMs = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
 )
@@ -46,19 +46,65 @@ type SentinelConfig struct {
 	Stat StatConfig
 	// UseCacheTime indicates whether to cache time(ms)
 	UseCacheTime bool `yaml:"useCacheTime"`
+	// EntryLeakDetection represents configuration items related to the entry leak detector.
+	EntryLeakDetection EntryLeakDetectionConfig `yaml:"entryLeakDetection"`
+}
+
+// EntryLeakDetectionConfig represents the configuration items of the entry leak detector,
+// which tracks the live entries to find out the ones that are never exited.
+// The detector captures the call stack of each entry, so it should only be enabled for debugging.
+type EntryLeakDetectionConfig struct {
+	// Enabled indicates whether to enable the entry leak detector.
+	Enabled bool `yaml:"enabled"`
+	// CheckIntervalMs represents the interval of checking the live entries.
+	CheckIntervalMs uint32 `yaml:"checkIntervalMs"`
+	// LeakThresholdMs represents the age threshold, the entry alive longer than it is reported as leaked.
+	LeakThresholdMs uint32 `yaml:"leakThresholdMs"`
+	// MaxLifetimeMs represents the max lifetime of the entry, the statistic of the entry alive longer than it
+	// is released forcibly. 0 means the statistic of the leaked entries is never released forcibly.
+	MaxLifetimeMs uint32 `yaml:"maxLifetimeMs"`
 }
 
 // ExporterConfig represents configuration items related to exporter, like metric exporter.
 type ExporterConfig struct {
 	Metric MetricExporterConfig
+	// StatsD represents configuration of the StatsD metric exporter,
+	// which is selected by the metric exporter name "statsd".
+	StatsD StatsDExporterConfig
+}
+
+// StatsDExporterConfig represents configuration of the StatsD (DogStatsD) metric exporter.
+type StatsDExporterConfig struct {
+	// Network is the network of the StatsD server, "udp" or "unixgram" (unix domain socket).
+	Network string `yaml:"network"`
+	// Addr is the address of the StatsD server, like "127.0.0.1:8125" or "/var/run/datadog/dsd.socket".
+	Addr string `yaml:"addr"`
+	// Prefix is the prefix of the metric names.
+	Prefix string `yaml:"prefix"`
+	// Flavor is the flavor of the StatsD protocol, "dogstatsd" or "statsd".
+	// The labels are sent as tags in dogstatsd, while they are appended to the metric names in statsd.
+	Flavor string `yaml:"flavor"`
+	// FlushIntervalMs represents the interval (in milliseconds) of flushing the aggregated metrics.
+	FlushIntervalMs uint32 `yaml:"flush_interval_ms"`
+	// MaxPacketSize represents the max size (in bytes) of a single packet.
+	MaxPacketSize uint32 `yaml:"max_packet_size"`
 }
 
 // MetricExporterConfig represents configuration of metric exporter.
 type MetricExporterConfig struct {
+	// Name is the name of the metric exporter registered in exporter/metric, like "prometheus".
+	// If empty, the prometheus exporter is used when HttpAddr is configured, otherwise no metric is exported.
+	Name string `yaml:"name"`
 	// HttpAddr is the http server listen address, like ":8080".
 	HttpAddr string `yaml:"http_addr"`
 	// HttpPath is the http request path of access metrics, like "/metrics".
 	HttpPath string `yaml:"http_path"`
+	// RtBucketsMs represents the buckets (in milliseconds) of the exported RT histograms, in increasing order.
+	RtBucketsMs []float64 `yaml:"rt_buckets_ms"`
+	// MaxLabelCardinality represents the max amount of distinct label value sets of each exported metric.
+	// The label values exceeding the limit are aggregated into the overflow label values.
+	// 0 means no limit.
+	MaxLabelCardinality uint32 `yaml:"max_label_cardinality"`
 }
 
 // LogConfig represent the configuration of logging in Sentinel.
@@ -71,6 +117,8 @@ type LogConfig struct {
 	UsePid bool `yaml:"usePid"`
 	// Metric represents the configuration items of the metric log.
 	Metric MetricLogConfig
+	// Block represents the configuration items of the block log.
+	Block BlockLogConfig
 }
 
 // MetricLogConfig represents the configuration items of the metric log.
@@ -78,6 +126,16 @@ type MetricLogConfig struct {
 	SingleFileMaxSize uint64 `yaml:"singleFileMaxSize"`
 	MaxFileCount      uint32 `yaml:"maxFileCount"`
 	FlushIntervalSec  uint32 `yaml:"flushIntervalSec"`
+	// Format represents the line format of the metric log, "fat" (pipe-delimited, default) or "json" (JSON Lines).
+	Format string `yaml:"format"`
+}
+
+// BlockLogConfig represents the configuration items of the block log.
+// The block log is disabled if FlushIntervalSec is 0.
+type BlockLogConfig struct {
+	SingleFileMaxSize uint64 `yaml:"singleFileMaxSize"`
+	MaxFileCount      uint32 `yaml:"maxFileCount"`
+	FlushIntervalSec  uint32 `yaml:"flushIntervalSec"`
 }
 
 // StatConfig represents the configuration items of statistics.
@@ -90,6 +148,18 @@ type StatConfig struct {
 	// This default readonly metric statistic must be reusable based on global statistic.
 	MetricStatisticSampleCount uint32 `yaml:"metricStatisticSampleCount"`
 	MetricStatisticIntervalMs  uint32 `yaml:"metricStatisticIntervalMs"`
+	// RtPercentileEnabled indicates whether to record the RT distribution of the resources,
+	// so that the RT percentiles (e.g. p99) could be retrieved. It costs extra memory of each statistic bucket.
+	RtPercentileEnabled bool `yaml:"rtPercentileEnabled"`
+	// SlowRtThresholdMs represents the RT threshold (in ms) of the slow requests, which are counted
+	// in the statistic and the metric log. 0 means the slow requests are not counted.
+	SlowRtThresholdMs uint32 `yaml:"slowRtThresholdMs"`
+	// MaxResourceAmount represents the max amount of the resource statistics, 0 means base.DefaultMaxResourceAmount.
+	MaxResourceAmount uint32 `yaml:"maxResourceAmount"`
+	// ResourceOverflowPolicy represents how the resources beyond MaxResourceAmount are handled,
+	// "warn" (default) or "collapse" (into base.OverflowResourceName). The resources with flow or isolation rules
+	// are never collapsed.
+	ResourceOverflowPolicy string `yaml:"resourceOverflowPolicy"`
 
 	System SystemStatConfig `yaml:"system"`
 }
@@ -126,6 +196,12 @@ func NewDefaultConfig() *Entity {
 					SingleFileMaxSize: DefaultMetricLogSingleFileMaxSize,
 					MaxFileCount:      DefaultMetricLogMaxFileAmount,
 					FlushIntervalSec:  DefaultMetricLogFlushIntervalSec,
+					Format:            MetricLogFormatFat,
+				},
+				Block: BlockLogConfig{
+					SingleFileMaxSize: DefaultBlockLogSingleFileMaxSize,
+					MaxFileCount:      DefaultBlockLogMaxFileAmount,
+					FlushIntervalSec:  DefaultBlockLogFlushIntervalSec,
 				},
 			},
 			Stat: StatConfig{
@@ -133,6 +209,8 @@ func NewDefaultConfig() *Entity {
 				GlobalStatisticIntervalMsTotal:  base.DefaultIntervalMsTotal,
 				MetricStatisticSampleCount:      base.DefaultSampleCount,
 				MetricStatisticIntervalMs:       base.DefaultIntervalMs,
+				MaxResourceAmount:               base.DefaultMaxResourceAmount,
+				ResourceOverflowPolicy:          ResourceOverflowPolicyWarn,
 				System: SystemStatConfig{
 					CollectIntervalMs:       DefaultSystemStatCollectIntervalMs,
 					CollectLoadIntervalMs:   DefaultLoadStatCollectIntervalMs,
@@ -140,7 +218,25 @@ func NewDefaultConfig() *Entity {
 					CollectMemoryIntervalMs: DefaultMemoryStatCollectIntervalMs,
 				},
 			},
+			Exporter: ExporterConfig{
+				Metric: MetricExporterConfig{
+					RtBucketsMs: append([]float64(nil), DefaultMetricExportRtBucketsMs...),
+				},
+				StatsD: StatsDExporterConfig{
+					Network:         DefaultStatsDNetwork,
+					Prefix:          DefaultStatsDPrefix,
+					Flavor:          DefaultStatsDFlavor,
+					FlushIntervalMs: DefaultStatsDFlushIntervalMs,
+					MaxPacketSize:   DefaultStatsDMaxPacketSize,
+				},
+			},
 			UseCacheTime: false,
+			EntryLeakDetection: EntryLeakDetectionConfig{
+				Enabled:         false,
+				CheckIntervalMs: DefaultEntryLeakCheckIntervalMs,
+				LeakThresholdMs: DefaultEntryLeakThresholdMs,
+				MaxLifetimeMs:   0,
+			},
 		},
 	}
 }
@@ -169,10 +265,52 @@ func checkConfValid(conf *SentinelConfig) error {
 	if mc.SingleFileMaxSize <= 0 {
 		return errors.New("Illegal metric log globalCfg: singleFileMaxSize <= 0")
 	}
+	if mc.Format != "" && mc.Format != MetricLogFormatFat && mc.Format != MetricLogFormatJSON {
+		return errors.New("Illegal metric log globalCfg: format must be fat or json")
+	}
+	buckets := conf.Exporter.Metric.RtBucketsMs
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			return errors.New("Illegal metric exporter globalCfg: rt_buckets_ms must be in increasing order")
+		}
+	}
+	if sc := conf.Exporter.StatsD; sc.Addr != "" {
+		if sc.Network != "udp" && sc.Network != "unixgram" {
+			return errors.New("Illegal statsd exporter globalCfg: network must be udp or unixgram")
+		}
+		if sc.Flavor != "dogstatsd" && sc.Flavor != "statsd" {
+			return errors.New("Illegal statsd exporter globalCfg: flavor must be dogstatsd or statsd")
+		}
+		if sc.FlushIntervalMs == 0 {
+			return errors.New("Illegal statsd exporter globalCfg: flush_interval_ms <= 0")
+		}
+		if sc.MaxPacketSize == 0 {
+			return errors.New("Illegal statsd exporter globalCfg: max_packet_size <= 0")
+		}
+	}
+	if bc := conf.Log.Block; bc.FlushIntervalSec > 0 {
+		if bc.MaxFileCount <= 0 {
+			return errors.New("Illegal block log globalCfg: maxFileCount <= 0")
+		}
+		if bc.SingleFileMaxSize <= 0 {
+			return errors.New("Illegal block log globalCfg: singleFileMaxSize <= 0")
+		}
+	}
+	if p := conf.Stat.ResourceOverflowPolicy; p != "" && p != ResourceOverflowPolicyCollapse && p != ResourceOverflowPolicyWarn {
+		return errors.New("Illegal stat globalCfg: resourceOverflowPolicy must be collapse or warn")
+	}
 	if err := base.CheckValidityForReuseStatistic(conf.Stat.MetricStatisticSampleCount, conf.Stat.MetricStatisticIntervalMs,
 		conf.Stat.GlobalStatisticSampleCountTotal, conf.Stat.GlobalStatisticIntervalMsTotal); err != nil {
 		return err
 	}
+	if ld := conf.EntryLeakDetection; ld.Enabled {
+		if ld.CheckIntervalMs == 0 || ld.LeakThresholdMs == 0 {
+			return errors.New("Illegal entry leak detection globalCfg: checkIntervalMs or leakThresholdMs is 0")
+		}
+		if ld.MaxLifetimeMs != 0 && ld.MaxLifetimeMs < ld.LeakThresholdMs {
+			return errors.New("Illegal entry leak detection globalCfg: maxLifetimeMs < leakThresholdMs")
+		}
+	}
 	return nil
 }
 
@@ -209,10 +347,75 @@ func (entity *Entity) MetricExportHTTPAddr() string {
 	return entity.Sentinel.Exporter.Metric.HttpAddr
 }
 
+func (entity *Entity) MetricExporterName() string {
+	return entity.Sentinel.Exporter.Metric.Name
+}
+
 func (entity *Entity) MetricExportHTTPPath() string {
 	return entity.Sentinel.Exporter.Metric.HttpPath
 }
 
+func (entity *Entity) MetricExportRtBucketsMs() []float64 {
+	return entity.Sentinel.Exporter.Metric.RtBucketsMs
+}
+
+func (entity *Entity) MetricExportMaxLabelCardinality() uint32 {
+	return entity.Sentinel.Exporter.Metric.MaxLabelCardinality
+}
+
+func (entity *Entity) StatsDNetwork() string {
+	return entity.Sentinel.Exporter.StatsD.Network
+}
+
+func (entity *Entity) StatsDAddr() string {
+	return entity.Sentinel.Exporter.StatsD.Addr
+}
+
+func (entity *Entity) StatsDPrefix() string {
+	return entity.Sentinel.Exporter.StatsD.Prefix
+}
+
+func (entity *Entity) StatsDFlavor() string {
+	return entity.Sentinel.Exporter.StatsD.Flavor
+}
+
+func (entity *Entity) StatsDFlushIntervalMs() uint32 {
+	return entity.Sentinel.Exporter.StatsD.FlushIntervalMs
+}
+
+func (entity *Entity) StatsDMaxPacketSize() uint32 {
+	return entity.Sentinel.Exporter.StatsD.MaxPacketSize
+}
+
+func (entity *Entity) RtPercentileEnabled() bool {
+	return entity.Sentinel.Stat.RtPercentileEnabled
+}
+
+func (entity *Entity) SlowRtThresholdMs() uint32 {
+	return entity.Sentinel.Stat.SlowRtThresholdMs
+}
+
+func (entity *Entity) MaxResourceAmount() uint32 {
+	if entity.Sentinel.Stat.MaxResourceAmount == 0 {
+		return base.DefaultMaxResourceAmount
+	}
+	return entity.Sentinel.Stat.MaxResourceAmount
+}
+
+func (entity *Entity) ResourceOverflowPolicy() string {
+	if entity.Sentinel.Stat.ResourceOverflowPolicy == "" {
+		return ResourceOverflowPolicyWarn
+	}
+	return entity.Sentinel.Stat.ResourceOverflowPolicy
+}
+
+func (entity *Entity) MetricLogFormat() string {
+	if entity.Sentinel.Log.Metric.Format == "" {
+		return MetricLogFormatFat
+	}
+	return entity.Sentinel.Log.Metric.Format
+}
+
 func (entity *Entity) MetricLogFlushIntervalSec() uint32 {
 	return entity.Sentinel.Log.Metric.FlushIntervalSec
 }
@@ -225,6 +428,18 @@ func (entity *Entity) MetricLogMaxFileAmount() uint32 {
 	return entity.Sentinel.Log.Metric.MaxFileCount
 }
 
+func (entity *Entity) BlockLogFlushIntervalSec() uint32 {
+	return entity.Sentinel.Log.Block.FlushIntervalSec
+}
+
+func (entity *Entity) BlockLogSingleFileMaxSize() uint64 {
+	return entity.Sentinel.Log.Block.SingleFileMaxSize
+}
+
+func (entity *Entity) BlockLogMaxFileAmount() uint32 {
+	return entity.Sentinel.Log.Block.MaxFileCount
+}
+
 func (entity *Entity) SystemStatCollectIntervalMs() uint32 {
 	return entity.Sentinel.Stat.System.CollectIntervalMs
 }
@@ -259,3 +474,19 @@ func (entity *Entity) MetricStatisticIntervalMs() uint32 {
 func (entity *Entity) MetricStatisticSampleCount() uint32 {
 	return entity.Sentinel.Stat.MetricStatisticSampleCount
 }
+
+func (entity *Entity) EntryLeakDetectionEnabled() bool {
+	return entity.Sentinel.EntryLeakDetection.Enabled
+}
+
+func (entity *Entity) EntryLeakCheckIntervalMs() uint32 {
+	return entity.Sentinel.EntryLeakDetection.CheckIntervalMs
+}
+
+func (entity *Entity) EntryLeakThresholdMs() uint32 {
+	return entity.Sentinel.EntryLeakDetection.LeakThresholdMs
+}
+
+func (entity *Entity) EntryMaxLifetimeMs() uint32 {
+	return entity.Sentinel.EntryLeakDetection.MaxLifetimeMs
+}
@@ -0,0 +1,38 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fallback provides the registry of fallback handlers, which are invoked
+// when the invocation is blocked by Sentinel or the business logic returns an error.
+//
+// The fallback handler could be registered for a resource, or for a specific block type of a resource.
+// The block type specific handler takes precedence over the resource level handler when the invocation is blocked,
+// and the resource level handler also handles the business error.
+//
+// Here is the example code to use fallback with api.EntryWithFallback:
+//
+//	_ = fallback.RegisterHandler("some-test", func(ctx context.Context, resource string, err error) (interface{}, error) {
+//		return "default value", nil
+//	})
+//	_ = fallback.RegisterBlockTypeHandler("some-test", base.BlockTypeCircuitBreaking, func(ctx context.Context, resource string, err error) (interface{}, error) {
+//		return "cached value", nil
+//	})
+//
+//	result, err := sentinel.EntryWithFallback(ctx, "some-test", func(ctx context.Context) (interface{}, error) {
+//		return queryRemote(ctx)
+//	})
+//
+// The registry is consulted by api.EntryWithFallback, and by the adapters (e.g. gin, nethttp and grpc)
+// if no block fallback option is provided. See the docs of the adapters for how the results of the handlers
+// are responded.
+package fallback
@@ -0,0 +1,140 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fallback
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/logging"
+)
+
+// Handler handles the invocation of the resource when it is blocked or fails.
+// The err is *base.BlockError if the invocation is blocked by Sentinel, otherwise it is the business error.
+// The returned value and error would be returned to the caller instead.
+type Handler func(ctx context.Context, resource string, err error) (interface{}, error)
+
+type resourceHandlers struct {
+	// handler handles the blocked invocation as well as the business error
+	handler Handler
+	// blockTypeHandlers handles the blocked invocation of specific block type
+	blockTypeHandlers map[base.BlockType]Handler
+}
+
+var (
+	resHandlers = make(map[string]*resourceHandlers)
+	updateMux   = new(sync.RWMutex)
+)
+
+// RegisterHandler registers the fallback handler of the given resource, the previous handler would be replaced.
+// The handler is invoked when the invocation is blocked (if there is no block type specific handler) or fails.
+func RegisterHandler(resource string, h Handler) error {
+	if len(resource) == 0 {
+		return errors.New("empty resource")
+	}
+	if h == nil {
+		return errors.New("nil fallback handler")
+	}
+	updateMux.Lock()
+	defer updateMux.Unlock()
+
+	handlersOf(resource).handler = h
+	return nil
+}
+
+// RegisterBlockTypeHandler registers the fallback handler of the given resource and block type,
+// the previous handler would be replaced.
+// The handler is invoked only when the invocation is blocked with the given block type.
+func RegisterBlockTypeHandler(resource string, blockType base.BlockType, h Handler) error {
+	if len(resource) == 0 {
+		return errors.New("empty resource")
+	}
+	if h == nil {
+		return errors.New("nil fallback handler")
+	}
+	updateMux.Lock()
+	defer updateMux.Unlock()
+
+	handlersOf(resource).blockTypeHandlers[blockType] = h
+	return nil
+}
+
+// handlersOf returns the handlers of the given resource, the caller must hold the write lock.
+func handlersOf(resource string) *resourceHandlers {
+	rh, ok := resHandlers[resource]
+	if !ok {
+		rh = &resourceHandlers{
+			blockTypeHandlers: make(map[base.BlockType]Handler),
+		}
+		resHandlers[resource] = rh
+	}
+	return rh
+}
+
+// RemoveHandlers removes all the fallback handlers of the given resource.
+func RemoveHandlers(resource string) {
+	updateMux.Lock()
+	defer updateMux.Unlock()
+
+	delete(resHandlers, resource)
+}
+
+// ClearHandlers clears all the fallback handlers.
+func ClearHandlers() {
+	updateMux.Lock()
+	defer updateMux.Unlock()
+
+	resHandlers = make(map[string]*resourceHandlers)
+}
+
+// GetHandler returns the fallback handler of the given resource for the given error, nil if absent.
+// If err is *base.BlockError, the handler of its block type takes precedence over the resource level handler.
+func GetHandler(resource string, err error) Handler {
+	if err == nil {
+		return nil
+	}
+	updateMux.RLock()
+	defer updateMux.RUnlock()
+
+	rh, ok := resHandlers[resource]
+	if !ok {
+		return nil
+	}
+	if blockErr, ok := err.(*base.BlockError); ok {
+		if h, ok := rh.blockTypeHandlers[blockErr.BlockType()]; ok {
+			return h
+		}
+	}
+	return rh.handler
+}
+
+// Invoke invokes the fallback handler of the given resource for the given error.
+// The given error would be returned as it is if there is no matched handler.
+func Invoke(ctx context.Context, resource string, err error) (result interface{}, fallbackErr error) {
+	h := GetHandler(resource, err)
+	if h == nil {
+		return nil, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			logging.Error(errors.Errorf("%+v", r), "Panic in fallback handler", "resource", resource)
+			result, fallbackErr = nil, err
+		}
+	}()
+	return h(ctx, resource, err)
+}
@@ -0,0 +1,79 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fallback
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterHandler(t *testing.T) {
+	defer ClearHandlers()
+
+	h := func(ctx context.Context, resource string, err error) (interface{}, error) {
+		return resource, nil
+	}
+	assert.NotNil(t, RegisterHandler("", h))
+	assert.NotNil(t, RegisterHandler("abc", nil))
+	assert.NotNil(t, RegisterBlockTypeHandler("abc", base.BlockTypeFlow, nil))
+	assert.Nil(t, RegisterHandler("abc", h))
+	assert.Nil(t, RegisterBlockTypeHandler("def", base.BlockTypeFlow, h))
+
+	assert.Nil(t, GetHandler("abc", nil))
+	assert.NotNil(t, GetHandler("abc", errors.New("biz error")))
+	assert.NotNil(t, GetHandler("abc", base.NewBlockErrorWithMessage(base.BlockTypeIsolation, "")))
+	assert.NotNil(t, GetHandler("def", base.NewBlockErrorWithMessage(base.BlockTypeFlow, "")))
+	assert.Nil(t, GetHandler("def", base.NewBlockErrorWithMessage(base.BlockTypeIsolation, "")))
+	assert.Nil(t, GetHandler("def", errors.New("biz error")))
+
+	RemoveHandlers("abc")
+	assert.Nil(t, GetHandler("abc", errors.New("biz error")))
+}
+
+func TestInvoke(t *testing.T) {
+	defer ClearHandlers()
+
+	_ = RegisterHandler("abc", func(ctx context.Context, resource string, err error) (interface{}, error) {
+		return "resource", nil
+	})
+	_ = RegisterBlockTypeHandler("abc", base.BlockTypeFlow, func(ctx context.Context, resource string, err error) (interface{}, error) {
+		return "flow", nil
+	})
+	_ = RegisterBlockTypeHandler("abc", base.BlockTypeIsolation, func(ctx context.Context, resource string, err error) (interface{}, error) {
+		panic("fallback panic")
+	})
+
+	ret, err := Invoke(context.Background(), "abc", base.NewBlockErrorWithMessage(base.BlockTypeFlow, ""))
+	assert.Nil(t, err)
+	assert.Equal(t, "flow", ret)
+
+	ret, err = Invoke(context.Background(), "abc", base.NewBlockErrorWithMessage(base.BlockTypeCircuitBreaking, ""))
+	assert.Nil(t, err)
+	assert.Equal(t, "resource", ret)
+
+	blockErr := base.NewBlockErrorWithMessage(base.BlockTypeIsolation, "")
+	ret, err = Invoke(context.Background(), "abc", blockErr)
+	assert.Nil(t, ret)
+	assert.Equal(t, blockErr, err)
+
+	bizErr := errors.New("biz error")
+	ret, err = Invoke(context.Background(), "def", bizErr)
+	assert.Nil(t, ret)
+	assert.Equal(t, bizErr, err)
+}
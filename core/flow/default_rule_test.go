@@ -0,0 +1,67 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package flow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterDefaultRules(t *testing.T) {
+	defer func() {
+		ClearDefaultRules()
+		_ = ClearRules()
+	}()
+
+	newRule := func(threshold float64) *Rule {
+		return &Rule{
+			Resource:               "default-res",
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Reject,
+			Threshold:              threshold,
+			StatIntervalInMs:       1000,
+		}
+	}
+	thresholdOf := func() float64 {
+		rules := getRulesOfResource("default-res")
+		if len(rules) == 0 {
+			return -1
+		}
+		return rules[0].Threshold
+	}
+
+	assert.Nil(t, RegisterDefaultRules([]*Rule{newRule(10)}))
+	assert.Equal(t, 10.0, thresholdOf())
+	assert.NotNil(t, RegisterDefaultRules([]*Rule{newRule(20)}))
+	assert.NotNil(t, RegisterDefaultRules([]*Rule{{Resource: "invalid", Threshold: -1}}))
+	assert.Equal(t, 10.0, thresholdOf())
+
+	// the loaded rules override the default rules
+	_, err := LoadRules([]*Rule{newRule(30)})
+	assert.Nil(t, err)
+	assert.Equal(t, 30.0, thresholdOf())
+
+	// the default rules take effect again once the loaded rules are cleared
+	assert.Nil(t, ClearRules())
+	assert.Equal(t, 10.0, thresholdOf())
+	_, err = LoadRulesOfResource("default-res", []*Rule{newRule(30)})
+	assert.Nil(t, err)
+	assert.Equal(t, 30.0, thresholdOf())
+	assert.Nil(t, ClearRulesOfResource("default-res"))
+	assert.Equal(t, 10.0, thresholdOf())
+
+	ClearDefaultRules()
+	assert.Equal(t, -1.0, thresholdOf())
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/Danceiny/sentinel-golang/core/base"
 	"github.com/Danceiny/sentinel-golang/util"
 )
 
@@ -107,6 +108,12 @@ type Rule struct {
 	// If the StatIntervalInMs user specifies can not reuse the global statistic of resource,
 	// 		sentinel will generate independent statistic structure for this rule.
 	StatIntervalInMs uint32 `json:"statIntervalInMs"`
+	// MetricEvent is the name of the custom MetricEvent (registered by base.RegisterMetricEvent) which
+	// the flow control is based on, e.g. "cache_miss". The custom MetricEvent is recorded by api.RecordEvent.
+	// Empty MetricEvent means the flow control is based on the passed requests.
+	// Only the rule of Direct TokenCalculateStrategy and Reject ControlBehavior supports MetricEvent,
+	// and the StatIntervalInMs of the rule must be able to reuse the global statistic of resource.
+	MetricEvent string `json:"metricEvent,omitempty"`
 
 	// adaptive flow control algorithm related parameters
 	// limitation: LowMemUsageThreshold > HighMemUsageThreshold && MemHighWaterMarkBytes > MemLowWaterMarkBytes
@@ -128,7 +135,7 @@ func (r *Rule) isEqualsTo(newRule *Rule) bool {
 		r.TokenCalculateStrategy == newRule.TokenCalculateStrategy && r.ControlBehavior == newRule.ControlBehavior &&
 		util.Float64Equals(r.Threshold, newRule.Threshold) &&
 		r.MaxQueueingTimeMs == newRule.MaxQueueingTimeMs && r.WarmUpPeriodSec == newRule.WarmUpPeriodSec &&
-		r.WarmUpColdFactor == newRule.WarmUpColdFactor &&
+		r.WarmUpColdFactor == newRule.WarmUpColdFactor && r.MetricEvent == newRule.MetricEvent &&
 		r.LowMemUsageThreshold == newRule.LowMemUsageThreshold && r.HighMemUsageThreshold == newRule.HighMemUsageThreshold &&
 		r.MemLowWaterMarkBytes == newRule.MemLowWaterMarkBytes && r.MemHighWaterMarkBytes == newRule.MemHighWaterMarkBytes) {
 
@@ -150,15 +157,26 @@ func (r *Rule) needStatistic() bool {
 	return r.TokenCalculateStrategy == WarmUp || r.ControlBehavior == Reject
 }
 
+// metricEvent returns the MetricEvent which the flow control is based on.
+func (r *Rule) metricEvent() base.MetricEvent {
+	if r.MetricEvent == "" {
+		return base.MetricEventPass
+	}
+	if event, ok := base.MetricEventOf(r.MetricEvent); ok {
+		return event
+	}
+	return base.MetricEventPass
+}
+
 func (r *Rule) String() string {
 	b, err := json.Marshal(r)
 	if err != nil {
 		// Return the fallback string
 		return fmt.Sprintf("Rule{Resource=%s, TokenCalculateStrategy=%s, ControlBehavior=%s, "+
 			"Threshold=%.2f, RelationStrategy=%s, RefResource=%s, MaxQueueingTimeMs=%d, WarmUpPeriodSec=%d, WarmUpColdFactor=%d, StatIntervalInMs=%d, "+
-			"LowMemUsageThreshold=%v, HighMemUsageThreshold=%v, MemLowWaterMarkBytes=%v, MemHighWaterMarkBytes=%v}",
+			"MetricEvent=%s, LowMemUsageThreshold=%v, HighMemUsageThreshold=%v, MemLowWaterMarkBytes=%v, MemHighWaterMarkBytes=%v}",
 			r.Resource, r.TokenCalculateStrategy, r.ControlBehavior, r.Threshold, r.RelationStrategy, r.RefResource,
-			r.MaxQueueingTimeMs, r.WarmUpPeriodSec, r.WarmUpColdFactor, r.StatIntervalInMs,
+			r.MaxQueueingTimeMs, r.WarmUpPeriodSec, r.WarmUpColdFactor, r.StatIntervalInMs, r.MetricEvent,
 			r.LowMemUsageThreshold, r.HighMemUsageThreshold, r.MemLowWaterMarkBytes, r.MemHighWaterMarkBytes)
 	}
 	return string(b)
@@ -167,3 +185,7 @@ func (r *Rule) String() string {
 func (r *Rule) ResourceName() string {
 	return r.Resource
 }
+
+func (r *Rule) RuleID() string {
+	return r.ID
+}
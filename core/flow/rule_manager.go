@@ -247,6 +247,7 @@ func LoadRules(rules []*Rule) (bool, error) {
 
 	updateRuleMux.Lock()
 	defer updateRuleMux.Unlock()
+	resRulesMap = defaultRuleSet.Load(resRulesMap)
 	isEqual := reflect.DeepEqual(currentRules, resRulesMap)
 	if isEqual {
 		logging.Info("[Flow] Load rules is the same with current rules, so ignore load operation.")
@@ -256,6 +257,17 @@ func LoadRules(rules []*Rule) (bool, error) {
 	return true, err
 }
 
+// clearRulesOfResource clears the rules of the resource, updateRuleMux must be held.
+func clearRulesOfResource(res string) {
+	// clear resource's currentRules
+	delete(currentRules, res)
+	// clear tcMap
+	tcMux.Lock()
+	delete(tcMap, res)
+	tcMux.Unlock()
+	logging.Info("[Flow] clear resource level rules", "resource", res)
+}
+
 func onResourceRuleUpdate(res string, rawResRules []*Rule) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -304,17 +316,16 @@ func LoadRulesOfResource(res string, rules []*Rule) (bool, error) {
 	}
 	updateRuleMux.Lock()
 	defer updateRuleMux.Unlock()
+	effectiveRules := defaultRuleSet.LoadOfResource(res, rules)
 	// clear resource rules
-	if len(rules) == 0 {
-		// clear resource's currentRules
-		delete(currentRules, res)
-		// clear tcMap
-		tcMux.Lock()
-		delete(tcMap, res)
-		tcMux.Unlock()
-		logging.Info("[Flow] clear resource level rules", "resource", res)
+	if len(effectiveRules) == 0 {
+		clearRulesOfResource(res)
 		return true, nil
 	}
+	// fall back to the default rules of the resource
+	if len(rules) == 0 {
+		return true, onResourceRuleUpdate(res, effectiveRules)
+	}
 	// load resource level rules
 	isEqual := reflect.DeepEqual(currentRules[res], rules)
 	if isEqual {
@@ -374,7 +385,8 @@ func GetRulesOfResource(res string) []Rule {
 	return ret
 }
 
-// ClearRules clears all the rules in flow module.
+// ClearRules clears all the loaded rules in flow module,
+// while the default rules registered by RegisterDefaultRules are kept, see ClearDefaultRules.
 func ClearRules() error {
 	_, err := LoadRules(nil)
 	return err
@@ -416,9 +428,9 @@ func generateStatFor(rule *Rule) (*standaloneStatistic, error) {
 	var resNode *stat.ResourceNode
 	if rule.RelationStrategy == AssociatedResource {
 		// use associated statistic
-		resNode = stat.GetOrCreateResourceNode(rule.RefResource, base.ResTypeCommon)
+		resNode = stat.GetOrCreateRuleResourceNode(rule.RefResource, base.ResTypeCommon)
 	} else {
-		resNode = stat.GetOrCreateResourceNode(rule.Resource, base.ResTypeCommon)
+		resNode = stat.GetOrCreateRuleResourceNode(rule.Resource, base.ResTypeCommon)
 	}
 	if intervalInMs == 0 || intervalInMs == config.MetricStatisticIntervalMs() {
 		// default case, use the resource's default statistic
@@ -454,6 +466,9 @@ func generateStatFor(rule *Rule) (*standaloneStatistic, error) {
 		retStat.writeOnlyMetric = nil
 		return &retStat, nil
 	} else if err == base.GlobalStatisticNonReusableError {
+		if rule.MetricEvent != "" {
+			return nil, errors.Errorf("flow rule of MetricEvent must reuse the global statistic of resource, StatIntervalInMs: %d", intervalInMs)
+		}
 		logging.Info("[FlowRuleManager] Flow rule couldn't reuse global statistic and will generate independent statistic", "rule", rule)
 		retStat.reuseResourceStat = false
 		realLeapArray := sbase.NewBucketLeapArray(sampleCount, intervalInMs)
@@ -621,6 +636,14 @@ func IsValidRule(rule *Rule) error {
 			return errors.New("WarmUpColdFactor must be great than 1")
 		}
 	}
+	if rule.MetricEvent != "" {
+		if rule.TokenCalculateStrategy != Direct || rule.ControlBehavior != Reject {
+			return errors.New("MetricEvent only takes effect when TokenCalculateStrategy is Direct and ControlBehavior is Reject")
+		}
+		if _, ok := base.MetricEventOf(rule.MetricEvent); !ok {
+			return errors.Errorf("unregistered MetricEvent: %s", rule.MetricEvent)
+		}
+	}
 	if rule.StatIntervalInMs > 10*60*1000 {
 		logging.Info("StatIntervalInMs is great than 10 minutes, less than 10 minutes is recommended.")
 	}
@@ -18,6 +18,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/config"
 	"github.com/Danceiny/sentinel-golang/core/stat"
 	sbase "github.com/Danceiny/sentinel-golang/core/stat/base"
 	"github.com/stretchr/testify/assert"
@@ -500,6 +502,34 @@ func TestIsValidRule(t *testing.T) {
 	assert.Nil(t, IsValidRule(rule1))
 }
 
+func TestIsValidRuleWithMetricEvent(t *testing.T) {
+	_, err := base.RegisterMetricEvent("flow_test_cache_miss")
+	assert.NoError(t, err)
+
+	rule := &Rule{
+		Resource:               "abc",
+		TokenCalculateStrategy: Direct,
+		ControlBehavior:        Reject,
+		Threshold:              10,
+		MetricEvent:            "flow_test_cache_miss",
+	}
+	assert.Nil(t, IsValidRule(rule))
+	event, _ := base.MetricEventOf("flow_test_cache_miss")
+	assert.Equal(t, event, rule.metricEvent())
+
+	rule.ControlBehavior = Throttling
+	assert.NotNil(t, IsValidRule(rule))
+	rule.ControlBehavior = Reject
+	rule.TokenCalculateStrategy = WarmUp
+	rule.WarmUpPeriodSec = 10
+	assert.NotNil(t, IsValidRule(rule))
+	rule.TokenCalculateStrategy = Direct
+	rule.MetricEvent = "flow_test_unregistered"
+	assert.NotNil(t, IsValidRule(rule))
+	rule.MetricEvent = ""
+	assert.Equal(t, base.MetricEventPass, rule.metricEvent())
+}
+
 func TestLoadRulesOfResource(t *testing.T) {
 	r11 := &Rule{
 		Resource:               "abc1",
@@ -634,3 +664,38 @@ func TestClearRulesOfResource(t *testing.T) {
 		clearData()
 	})
 }
+
+func TestLoadRulesAfterResourceOverflow(t *testing.T) {
+	entity := config.NewDefaultConfig()
+	entity.Sentinel.Stat.MaxResourceAmount = 1
+	entity.Sentinel.Stat.ResourceOverflowPolicy = config.ResourceOverflowPolicyCollapse
+	config.ResetGlobalConfig(entity)
+	stat.ResetResourceNodeMap()
+	defer func() {
+		config.ResetGlobalConfig(config.NewDefaultConfig())
+		stat.ResetResourceNodeMap()
+		_ = ClearRules()
+	}()
+
+	stat.GetOrCreateResourceNode("overflow-existing", base.ResTypeCommon)
+	// the traffic of the collapsed resources is summed in the overflow node
+	collapsed := stat.GetOrCreateResourceNode("overflow-other", base.ResTypeCommon)
+	assert.Equal(t, base.OverflowResourceName, collapsed.ResourceName())
+	collapsed.AddCount(base.MetricEventPass, 100)
+
+	_, err := LoadRules([]*Rule{
+		{
+			Resource:               "overflow-ruled",
+			TokenCalculateStrategy: Direct,
+			ControlBehavior:        Reject,
+			Threshold:              10,
+		},
+	})
+	assert.Nil(t, err)
+	// the resource with rules is bound to its own node instead of the overflow node
+	node := stat.GetOrCreateResourceNode("overflow-ruled", base.ResTypeCommon)
+	assert.Equal(t, "overflow-ruled", node.ResourceName())
+	tc := getTrafficControllerListFor("overflow-ruled")[0]
+	assert.Equal(t, float64(0), tc.boundStat.readOnlyMetric.GetQPS(base.MetricEventPass))
+	assert.Nil(t, tc.PerformChecking(node, 1, 0))
+}
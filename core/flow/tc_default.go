@@ -41,12 +41,15 @@ func (d *DirectTrafficShapingCalculator) BoundOwner() *TrafficShapingController
 type RejectTrafficShapingChecker struct {
 	owner *TrafficShapingController
 	rule  *Rule
+	// event is the MetricEvent which the check is based on.
+	event base.MetricEvent
 }
 
 func NewRejectTrafficShapingChecker(owner *TrafficShapingController, rule *Rule) *RejectTrafficShapingChecker {
 	return &RejectTrafficShapingChecker{
 		owner: owner,
 		rule:  rule,
+		event: rule.metricEvent(),
 	}
 }
 
@@ -59,7 +62,7 @@ func (d *RejectTrafficShapingChecker) DoCheck(resStat base.StatNode, batchCount
 	if metricReadonlyStat == nil {
 		return nil
 	}
-	curCount := float64(metricReadonlyStat.GetSum(base.MetricEventPass))
+	curCount := float64(metricReadonlyStat.GetSum(d.event))
 	if curCount+float64(batchCount) > threshold {
 		msg := "flow reject check blocked"
 		return base.NewTokenResultBlockedWithCause(base.BlockTypeFlow, msg, d.rule, curCount)
@@ -109,6 +109,10 @@ func (r *Rule) ResourceName() string {
 	return r.Resource
 }
 
+func (r *Rule) RuleID() string {
+	return r.ID
+}
+
 // IsStatReusable checks whether current rule is "statistically" equal to the given rule.
 func (r *Rule) IsStatReusable(newRule *Rule) bool {
 	return r.Resource == newRule.Resource && r.ControlBehavior == newRule.ControlBehavior && r.ParamsMaxCapacity == newRule.ParamsMaxCapacity && r.DurationInSec == newRule.DurationInSec && r.MetricType == newRule.MetricType
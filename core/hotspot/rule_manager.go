@@ -200,6 +200,8 @@ func onRuleUpdate(rawResRulesMap map[string][]*Rule) (err error) {
 	tcMap = m
 	tcMux.Unlock()
 
+	updateTrackedKeysExportTask(len(m) > 0)
+
 	currentRules = rawResRulesMap
 
 	logging.Debug("[HotSpot onRuleUpdate] Time statistic(ns) for updating hotspot param flow rules", "timeCost", util.CurrentTimeNano()-start)
@@ -241,8 +243,11 @@ func onResourceRuleUpdate(res string, rawResRules []*Rule) (err error) {
 	} else {
 		tcMap[res] = newResTcs
 	}
+	hasControllers := len(tcMap) > 0
 	tcMux.Unlock()
 
+	updateTrackedKeysExportTask(hasControllers)
+
 	currentRules[res] = rawResRules
 
 	logging.Debug("[HotSpot onResourceRuleUpdate] Time statistic(ns) for updating hotspot param flow rules", "timeCost", util.CurrentTimeNano()-start)
@@ -268,7 +273,9 @@ func LoadRulesOfResource(res string, rules []*Rule) (bool, error) {
 		// clear tcMap
 		tcMux.Lock()
 		delete(tcMap, res)
+		hasControllers := len(tcMap) > 0
 		tcMux.Unlock()
+		updateTrackedKeysExportTask(hasControllers)
 		logging.Info("[HotSpot] clear resource level hotspot param flow rules", "resource", res)
 		return true, nil
 	}
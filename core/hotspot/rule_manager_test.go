@@ -17,9 +17,12 @@ package hotspot
 import (
 	"fmt"
 	"math"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/Danceiny/sentinel-golang/core/hotspot/cache"
+	metric_exporter "github.com/Danceiny/sentinel-golang/exporter/metric"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -567,3 +570,73 @@ func TestClearRulesOfResource(t *testing.T) {
 		clearData()
 	})
 }
+
+func isTrackedKeysExportTaskRunning() bool {
+	trackedKeysExportMux.Lock()
+	defer trackedKeysExportMux.Unlock()
+	return trackedKeysExportStopChan != nil
+}
+
+func TestTrackedKeysExportTask(t *testing.T) {
+	clearData()
+	defer clearData()
+
+	r := &Rule{
+		ID:              "1",
+		Resource:        "abc",
+		MetricType:      QPS,
+		ControlBehavior: Reject,
+		ParamIndex:      0,
+		Threshold:       100,
+		DurationInSec:   1,
+	}
+
+	t.Run("LoadRules", func(t *testing.T) {
+		_, err := LoadRules([]*Rule{r})
+		assert.Nil(t, err)
+		assert.True(t, isTrackedKeysExportTaskRunning())
+		assert.Nil(t, ClearRules())
+		assert.False(t, isTrackedKeysExportTaskRunning())
+	})
+
+	t.Run("LoadRulesOfResource", func(t *testing.T) {
+		_, err := LoadRulesOfResource("abc", []*Rule{r})
+		assert.Nil(t, err)
+		assert.True(t, isTrackedKeysExportTaskRunning())
+		assert.Nil(t, ClearRulesOfResource("abc"))
+		assert.False(t, isTrackedKeysExportTaskRunning())
+	})
+}
+
+func TestExportTrackedKeys(t *testing.T) {
+	clearData()
+	defer clearData()
+	assert.NoError(t, metric_exporter.SetExporter(metric_exporter.PrometheusExporterName))
+	defer func() {
+		assert.NoError(t, metric_exporter.SetExporter(metric_exporter.EmptyExporterName))
+	}()
+
+	_, err := LoadRules([]*Rule{
+		{
+			ID:              "1",
+			Resource:        "abc",
+			MetricType:      QPS,
+			ControlBehavior: Reject,
+			ParamIndex:      0,
+			Threshold:       100,
+			DurationInSec:   1,
+		},
+	})
+	assert.Nil(t, err)
+	defer ClearRules()
+
+	tc := getTrafficControllersFor("abc")[0]
+	for _, arg := range []string{"a", "b", "c", "a"} {
+		assert.Nil(t, tc.PerformChecking(arg, 1))
+	}
+	exportTrackedKeys()
+
+	rec := httptest.NewRecorder()
+	metric_exporter.HTTPHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Regexp(t, `sentinel_go_hotspot_tracked_keys\{[^}]*resource="abc",rule_id="1"\} 3\n`, rec.Body.String())
+}
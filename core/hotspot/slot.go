@@ -50,6 +50,7 @@ func (s *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
 			continue
 		}
 		if r.Status() == base.ResultStatusBlocked {
+			ctx.SetPair(base.LimitKeyPairKey, arg)
 			return r
 		}
 		if r.Status() == base.ResultStatusShouldWait {
@@ -18,16 +18,93 @@ import (
 	"fmt"
 	"math"
 	"runtime"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/Danceiny/sentinel-golang/core/base"
 	"github.com/Danceiny/sentinel-golang/core/hotspot/cache"
+	metric_exporter "github.com/Danceiny/sentinel-golang/exporter/metric"
 	"github.com/Danceiny/sentinel-golang/logging"
 	"github.com/Danceiny/sentinel-golang/util"
 	"github.com/pkg/errors"
 )
 
+var (
+	trackedKeysGauge = metric_exporter.NewGauge(
+		"hotspot_tracked_keys",
+		"Amount of the parameter keys tracked by the hotspot rule",
+		[]string{"resource", "rule_id"})
+
+	// trackedKeysExportMux guards the start and stop of the tracked keys export task.
+	trackedKeysExportMux sync.Mutex
+	// trackedKeysExportStopChan is non-nil while the tracked keys export task is running.
+	trackedKeysExportStopChan chan struct{}
+)
+
+const trackedKeysExportInterval = time.Second
+
+func init() {
+	metric_exporter.Register(trackedKeysGauge)
+}
+
+// updateTrackedKeysExportTask starts the task which exports the amount of the tracked
+// parameter keys of all hotspot rules periodically if there are any traffic shaping
+// controllers, and stops the task once all the controllers are removed.
+func updateTrackedKeysExportTask(hasControllers bool) {
+	trackedKeysExportMux.Lock()
+	defer trackedKeysExportMux.Unlock()
+
+	if hasControllers {
+		if trackedKeysExportStopChan != nil {
+			return
+		}
+		stopChan := make(chan struct{})
+		trackedKeysExportStopChan = stopChan
+		ticker := util.NewTicker(trackedKeysExportInterval)
+		go util.RunWithRecover(func() {
+			for {
+				select {
+				case <-ticker.C():
+					exportTrackedKeys()
+				case <-stopChan:
+					ticker.Stop()
+					return
+				}
+			}
+		})
+		return
+	}
+
+	if trackedKeysExportStopChan == nil {
+		return
+	}
+	close(trackedKeysExportStopChan)
+	trackedKeysExportStopChan = nil
+	// Remove the keys of the rules that have been cleared.
+	trackedKeysGauge.Reset()
+}
+
+// trackedKeysRecorder is implemented by the built-in traffic shaping controllers.
+type trackedKeysRecorder interface {
+	recordTrackedKeys()
+}
+
+func exportTrackedKeys() {
+	tcMux.RLock()
+	defer tcMux.RUnlock()
+
+	// Reset the gauge so that the keys of the removed rules are not exported anymore.
+	trackedKeysGauge.Reset()
+	for _, tcs := range tcMap {
+		for _, tc := range tcs {
+			if r, ok := tc.(trackedKeysRecorder); ok {
+				r.recordTrackedKeys()
+			}
+		}
+	}
+}
+
 type TrafficShapingController interface {
 	PerformChecking(arg interface{}, batchCount int64) *base.TokenResult
 
@@ -113,6 +190,21 @@ func (c *baseTrafficShapingController) BoundMetric() *ParamsMetric {
 	return c.metric
 }
 
+// recordTrackedKeys exports the amount of the parameter keys tracked by the controller.
+func (c *baseTrafficShapingController) recordTrackedKeys() {
+	if c.metric == nil {
+		return
+	}
+	counter := c.metric.RuleTokenCounter
+	if c.metricType == Concurrency {
+		counter = c.metric.ConcurrencyCounter
+	}
+	if counter == nil {
+		return
+	}
+	trackedKeysGauge.Set(float64(counter.Len()), c.res, c.r.ID)
+}
+
 func (c *baseTrafficShapingController) performCheckingForConcurrencyMetric(arg interface{}) *base.TokenResult {
 	specificItem := c.specificItems
 	initConcurrency := int64(0)
@@ -0,0 +1,67 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isolation
+
+import (
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/pkg/errors"
+)
+
+// defaultRuleSet holds the default rules and the loaded rules, guarded by updateRuleMux.
+var defaultRuleSet = base.NewDefaultRuleSet[*Rule]("Isolation")
+
+// RegisterDefaultRules registers the default rules of the resources, which take effect only if
+// no rule of the same resource is loaded via LoadRules or LoadRulesOfResource (e.g. by the datasource),
+// so that the rules could be declared in code and overridden by the dynamic rules.
+// Registering the same rules of a resource again is a no-op, while the error is returned
+// if the resource already has different default rules, which are kept.
+func RegisterDefaultRules(rules []*Rule) error {
+	resRulesMap := make(map[string][]*Rule, len(rules))
+	for _, rule := range rules {
+		if err := IsValidRule(rule); err != nil {
+			return errors.Wrapf(err, "invalid default rule %v", rule)
+		}
+		resRulesMap[rule.Resource] = append(resRulesMap[rule.Resource], rule)
+	}
+
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+	effectiveRules, err := defaultRuleSet.Register(resRulesMap)
+	for res, resRules := range effectiveRules {
+		if updateErr := onResourceRuleUpdate(res, resRules); updateErr != nil {
+			return updateErr
+		}
+	}
+	return err
+}
+
+// UnregisterDefaultRules removes the default rules of the given resources,
+// the loaded rules of the resources are not affected.
+func UnregisterDefaultRules(resources ...string) {
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+	for _, res := range defaultRuleSet.Unregister(resources) {
+		clearRulesOfResource(res)
+	}
+}
+
+// ClearDefaultRules removes all the default rules, the loaded rules are not affected.
+func ClearDefaultRules() {
+	updateRuleMux.Lock()
+	defer updateRuleMux.Unlock()
+	for _, res := range defaultRuleSet.Clear() {
+		clearRulesOfResource(res)
+	}
+}
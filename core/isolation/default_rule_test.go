@@ -0,0 +1,43 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package isolation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnregisterDefaultRules(t *testing.T) {
+	defer func() {
+		ClearDefaultRules()
+		_ = ClearRules()
+	}()
+
+	assert.Nil(t, RegisterDefaultRules([]*Rule{
+		{Resource: "default-a", MetricType: Concurrency, Threshold: 10},
+		{Resource: "default-b", MetricType: Concurrency, Threshold: 10},
+	}))
+	_, err := LoadRulesOfResource("default-a", []*Rule{{Resource: "default-a", MetricType: Concurrency, Threshold: 20}})
+	assert.Nil(t, err)
+
+	UnregisterDefaultRules("default-a", "default-b")
+	// the loaded rules are kept
+	assert.Equal(t, uint32(20), getRulesOfResource("default-a")[0].Threshold)
+	assert.Empty(t, getRulesOfResource("default-b"))
+
+	// no default rules to fall back to
+	assert.Nil(t, ClearRulesOfResource("default-a"))
+	assert.Empty(t, getRulesOfResource("default-a"))
+}
@@ -18,6 +18,9 @@ import (
 	"reflect"
 	"sync"
 
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/stat"
+	metric_exporter "github.com/Danceiny/sentinel-golang/exporter/metric"
 	"github.com/Danceiny/sentinel-golang/logging"
 	"github.com/Danceiny/sentinel-golang/util"
 	"github.com/pkg/errors"
@@ -28,8 +31,17 @@ var (
 	rwMux         = &sync.RWMutex{}
 	currentRules  = make(map[string][]*Rule, 0)
 	updateRuleMux = new(sync.Mutex)
+
+	resourceIsolationThresholdGauge = metric_exporter.NewGauge(
+		"resource_isolation_threshold",
+		"Resource isolation (concurrency) threshold",
+		[]string{"resource"})
 )
 
+func init() {
+	metric_exporter.Register(resourceIsolationThresholdGauge)
+}
+
 // LoadRules loads the given isolation rules to the rule manager, while all previous rules will be replaced.
 // the first returned value indicates whether do real load operation, if the rules is the same with previous rules, return false
 func LoadRules(rules []*Rule) (bool, error) {
@@ -44,6 +56,7 @@ func LoadRules(rules []*Rule) (bool, error) {
 
 	updateRuleMux.Lock()
 	defer updateRuleMux.Unlock()
+	resRulesMap = defaultRuleSet.Load(resRulesMap)
 	isEqual := reflect.DeepEqual(currentRules, resRulesMap)
 	if isEqual {
 		logging.Info("[Isolation] Load rules is the same with current rules, so ignore load operation.")
@@ -71,10 +84,14 @@ func onRuleUpdate(rawResRulesMap map[string][]*Rule) (err error) {
 	}
 
 	start := util.CurrentTimeNano()
+	for res := range validResRulesMap {
+		ensureResourceNode(res)
+	}
 	rwMux.Lock()
 	ruleMap = validResRulesMap
 	rwMux.Unlock()
 	currentRules = rawResRulesMap
+	updateThresholdGauge()
 
 	logging.Debug("[Isolation onRuleUpdate] Time statistic(ns) for updating isolation rule", "timeCost", util.CurrentTimeNano()-start)
 	logRuleUpdate(validResRulesMap)
@@ -89,17 +106,16 @@ func LoadRulesOfResource(res string, rules []*Rule) (bool, error) {
 	}
 	updateRuleMux.Lock()
 	defer updateRuleMux.Unlock()
+	effectiveRules := defaultRuleSet.LoadOfResource(res, rules)
 	// clear resource rules
-	if len(rules) == 0 {
-		// clear resource's currentRules
-		delete(currentRules, res)
-		// clear ruleMap
-		rwMux.Lock()
-		delete(ruleMap, res)
-		rwMux.Unlock()
-		logging.Info("[Isolation] clear resource level rules", "resource", res)
+	if len(effectiveRules) == 0 {
+		clearRulesOfResource(res)
 		return true, nil
 	}
+	// fall back to the default rules of the resource
+	if len(rules) == 0 {
+		return true, onResourceRuleUpdate(res, effectiveRules)
+	}
 	// load resource level rules
 	isEqual := reflect.DeepEqual(currentRules[res], rules)
 	if isEqual {
@@ -111,6 +127,18 @@ func LoadRulesOfResource(res string, rules []*Rule) (bool, error) {
 	return true, err
 }
 
+// clearRulesOfResource clears the rules of the resource, updateRuleMux must be held.
+func clearRulesOfResource(res string) {
+	// clear resource's currentRules
+	delete(currentRules, res)
+	// clear ruleMap
+	rwMux.Lock()
+	delete(ruleMap, res)
+	rwMux.Unlock()
+	updateThresholdGauge()
+	logging.Info("[Isolation] clear resource level rules", "resource", res)
+}
+
 func onResourceRuleUpdate(res string, rawResRules []*Rule) (err error) {
 	validResRules := make([]*Rule, 0, len(rawResRules))
 	for _, rule := range rawResRules {
@@ -122,6 +150,9 @@ func onResourceRuleUpdate(res string, rawResRules []*Rule) (err error) {
 	}
 
 	start := util.CurrentTimeNano()
+	if len(validResRules) > 0 {
+		ensureResourceNode(res)
+	}
 	rwMux.Lock()
 	if len(validResRules) == 0 {
 		delete(ruleMap, res)
@@ -130,12 +161,37 @@ func onResourceRuleUpdate(res string, rawResRules []*Rule) (err error) {
 	}
 	rwMux.Unlock()
 	currentRules[res] = rawResRules
+	updateThresholdGauge()
 	logging.Debug("[Isolation onResourceRuleUpdate] Time statistic(ns) for updating isolation rule", "timeCost", util.CurrentTimeNano()-start)
 	logging.Info("[Isolation] load resource level rules", "resource", res, "validResRules", validResRules)
 	return nil
 }
 
-// ClearRules clears all the rules in isolation module.
+// ensureResourceNode creates the statistic node of the resource with rules in advance,
+// so that the concurrency of the resource is never collapsed into the overflow resource.
+func ensureResourceNode(res string) {
+	stat.GetOrCreateRuleResourceNode(res, base.ResTypeCommon)
+}
+
+// updateThresholdGauge exports the effective (minimum) threshold of each resource.
+func updateThresholdGauge() {
+	rwMux.RLock()
+	defer rwMux.RUnlock()
+
+	resourceIsolationThresholdGauge.Reset()
+	for res, rules := range ruleMap {
+		var threshold uint32
+		for i, rule := range rules {
+			if i == 0 || rule.Threshold < threshold {
+				threshold = rule.Threshold
+			}
+		}
+		resourceIsolationThresholdGauge.Set(float64(threshold), res)
+	}
+}
+
+// ClearRules clears all the loaded rules in isolation module,
+// while the default rules registered by RegisterDefaultRules are kept, see ClearDefaultRules.
 func ClearRules() error {
 	_, err := LoadRules(nil)
 	return err
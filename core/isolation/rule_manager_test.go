@@ -15,8 +15,11 @@
 package isolation
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	metric_exporter "github.com/Danceiny/sentinel-golang/exporter/metric"
 	"github.com/Danceiny/sentinel-golang/logging"
 	"github.com/stretchr/testify/assert"
 )
@@ -223,3 +226,34 @@ func Test_ResourceRuleUpdate(t *testing.T) {
 		clearData()
 	})
 }
+
+func exportedMetrics(t *testing.T) string {
+	rec := httptest.NewRecorder()
+	metric_exporter.HTTPHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
+
+func TestThresholdGauge(t *testing.T) {
+	assert.NoError(t, metric_exporter.SetExporter(metric_exporter.PrometheusExporterName))
+	defer func() {
+		assert.NoError(t, metric_exporter.SetExporter(metric_exporter.EmptyExporterName))
+	}()
+	defer clearData()
+
+	_, err := LoadRules([]*Rule{
+		{Resource: "abc1", MetricType: Concurrency, Threshold: 100},
+		{Resource: "abc1", MetricType: Concurrency, Threshold: 50},
+		{Resource: "abc2", MetricType: Concurrency, Threshold: 200},
+	})
+	assert.NoError(t, err)
+	metrics := exportedMetrics(t)
+	// the minimum threshold of the resource is exported
+	assert.Regexp(t, `sentinel_go_resource_isolation_threshold\{[^}]*resource="abc1"\} 50\n`, metrics)
+	assert.Regexp(t, `sentinel_go_resource_isolation_threshold\{[^}]*resource="abc2"\} 200\n`, metrics)
+
+	assert.NoError(t, ClearRulesOfResource("abc1"))
+	metrics = exportedMetrics(t)
+	assert.NotContains(t, metrics, `resource="abc1"`)
+	assert.Regexp(t, `sentinel_go_resource_isolation_threshold\{[^}]*resource="abc2"\} 200\n`, metrics)
+}
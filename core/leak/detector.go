@@ -0,0 +1,217 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leak
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/stat"
+	metric_exporter "github.com/Danceiny/sentinel-golang/exporter/metric"
+	"github.com/Danceiny/sentinel-golang/logging"
+	"github.com/Danceiny/sentinel-golang/util"
+)
+
+const maxStackDepth = 32
+
+var (
+	enabled         int32
+	leakThresholdMs uint64
+	maxLifetimeMs   uint64
+
+	// liveEntries holds all the live entries: *base.SentinelEntry -> *liveEntry
+	liveEntries sync.Map
+
+	initOnce sync.Once
+	stopChan = make(chan struct{})
+
+	leakedCounter = metric_exporter.NewCounter(
+		"entry_leaked_total",
+		"Total count of the leaked entries (not exited after the leak threshold)",
+		[]string{"resource"})
+	forceReleasedCounter = metric_exporter.NewCounter(
+		"entry_force_released_total",
+		"Total count of the leaked entries whose statistic is released forcibly after the max lifetime",
+		[]string{"resource"})
+)
+
+func init() {
+	metric_exporter.Register(leakedCounter)
+	metric_exporter.Register(forceReleasedCounter)
+}
+
+type liveEntry struct {
+	createdMs uint64
+	pcs       []uintptr
+	reported  int32
+
+	// the statistic fields are captured on tracking, as the context is owned by the entry
+	statNode   base.StatNode
+	batchCount uint32
+	startTime  uint64
+}
+
+// EntryInfo describes a live entry tracked by the detector.
+type EntryInfo struct {
+	Resource string
+	// CreatedMs is the creation time of the entry
+	CreatedMs uint64
+	// AgeMs is the age of the entry
+	AgeMs uint64
+	// Stack is the call stack where the entry is created
+	Stack string
+}
+
+// InitDetector enables the entry leak detector and starts the checking task.
+// The entry alive longer than thresholdMs is reported as leaked, and the statistic of the entry alive
+// longer than lifetimeMs is released forcibly if lifetimeMs is positive.
+func InitDetector(checkIntervalMs, thresholdMs, lifetimeMs uint32) {
+	if checkIntervalMs == 0 || thresholdMs == 0 {
+		return
+	}
+	initOnce.Do(func() {
+		atomic.StoreUint64(&leakThresholdMs, uint64(thresholdMs))
+		atomic.StoreUint64(&maxLifetimeMs, uint64(lifetimeMs))
+		atomic.StoreInt32(&enabled, 1)
+
+		ticker := util.NewTicker(time.Duration(checkIntervalMs) * time.Millisecond)
+		go util.RunWithRecover(func() {
+			for {
+				select {
+				case <-ticker.C():
+					checkLiveEntries()
+				case <-stopChan:
+					ticker.Stop()
+					return
+				}
+			}
+		})
+		logging.Info("[EntryLeakDetector] Entry leak detector is enabled", "checkIntervalMs", checkIntervalMs,
+			"leakThresholdMs", thresholdMs, "maxLifetimeMs", lifetimeMs)
+	})
+}
+
+// Enabled checks whether the entry leak detector is enabled.
+func Enabled() bool {
+	return atomic.LoadInt32(&enabled) == 1
+}
+
+// Track starts tracking the given passed entry until it exits.
+func Track(e *base.SentinelEntry) {
+	if e == nil || e.Context() == nil {
+		return
+	}
+	ctx := e.Context()
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(2, pcs)
+	le := &liveEntry{
+		createdMs: util.CurrentTimeMillis(),
+		pcs:       pcs[:n],
+		statNode:  ctx.StatNode,
+		startTime: ctx.StartTime(),
+	}
+	if ctx.Input != nil {
+		le.batchCount = ctx.Input.BatchCount
+	}
+	liveEntries.Store(e, le)
+	e.WhenExit(func(entry *base.SentinelEntry, _ *base.EntryContext) error {
+		liveEntries.Delete(entry)
+		return nil
+	})
+}
+
+// LiveEntries returns all the live entries tracked by the detector.
+func LiveEntries() []EntryInfo {
+	now := util.CurrentTimeMillis()
+	ret := make([]EntryInfo, 0)
+	liveEntries.Range(func(key, value interface{}) bool {
+		e := key.(*base.SentinelEntry)
+		le := value.(*liveEntry)
+		ret = append(ret, newEntryInfo(e, le, now))
+		return true
+	})
+	return ret
+}
+
+func newEntryInfo(e *base.SentinelEntry, le *liveEntry, now uint64) EntryInfo {
+	ageMs := uint64(0)
+	if now > le.createdMs {
+		ageMs = now - le.createdMs
+	}
+	return EntryInfo{
+		Resource:  e.Resource().Name(),
+		CreatedMs: le.createdMs,
+		AgeMs:     ageMs,
+		Stack:     formatStack(le.pcs),
+	}
+}
+
+func checkLiveEntries() {
+	now := util.CurrentTimeMillis()
+	threshold := atomic.LoadUint64(&leakThresholdMs)
+	lifetime := atomic.LoadUint64(&maxLifetimeMs)
+	liveEntries.Range(func(key, value interface{}) bool {
+		e := key.(*base.SentinelEntry)
+		le := value.(*liveEntry)
+		if now < le.createdMs+threshold {
+			return true
+		}
+		resource := e.Resource().Name()
+		if atomic.CompareAndSwapInt32(&le.reported, 0, 1) {
+			info := newEntryInfo(e, le, now)
+			logging.Warn("[EntryLeakDetector] Found the entry which is not exited after the leak threshold",
+				"resource", resource, "ageMs", info.AgeMs, "stack", info.Stack)
+			leakedCounter.Add(float64(1), resource)
+		}
+		if lifetime > 0 && now >= le.createdMs+lifetime {
+			liveEntries.Delete(e)
+			// NOTICE: the entry must not be exited here, as the context may still be used by the owner,
+			// which is recycled by the Exit of the owner.
+			if e.MarkLeaked() {
+				logging.Warn("[EntryLeakDetector] Release the statistic of the leaked entry forcibly after the max lifetime",
+					"resource", resource, "maxLifetimeMs", lifetime)
+				stat.ReleaseLeaked(e.Resource(), le.statNode, le.batchCount, le.startTime)
+				forceReleasedCounter.Add(float64(1), resource)
+			}
+		}
+		return true
+	})
+}
+
+func formatStack(pcs []uintptr) string {
+	if len(pcs) == 0 {
+		return ""
+	}
+	b := strings.Builder{}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteString(":")
+		b.WriteString(strconv.Itoa(frame.Line))
+		b.WriteString("\n")
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,142 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leak
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/stat"
+	"github.com/Danceiny/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEntry(resource string) *base.SentinelEntry {
+	sc := base.NewSlotChain()
+	ctx := sc.GetPooledContext()
+	rw := base.NewResourceWrapper(resource, base.ResTypeCommon, base.Inbound)
+	ctx.Resource = rw
+	e := base.NewSentinelEntry(ctx, rw, sc)
+	ctx.SetEntry(e)
+	return e
+}
+
+func TestTrack(t *testing.T) {
+	e := newTestEntry("abc")
+	Track(e)
+	infos := LiveEntries()
+	assert.Equal(t, 1, len(infos))
+	assert.Equal(t, "abc", infos[0].Resource)
+	assert.Contains(t, infos[0].Stack, "TestTrack")
+
+	e.Exit()
+	assert.Equal(t, 0, len(LiveEntries()))
+}
+
+func TestCheckLiveEntries(t *testing.T) {
+	clock := util.NewMockClock()
+	util.SetClock(clock)
+	defer util.SetClock(util.NewRealClock())
+
+	atomic.StoreUint64(&leakThresholdMs, 1000)
+	atomic.StoreUint64(&maxLifetimeMs, 2000)
+	defer func() {
+		atomic.StoreUint64(&leakThresholdMs, 0)
+		atomic.StoreUint64(&maxLifetimeMs, 0)
+	}()
+
+	e := newTestEntry("abc")
+	Track(e)
+	exited := false
+	e.WhenExit(func(_ *base.SentinelEntry, _ *base.EntryContext) error {
+		exited = true
+		return nil
+	})
+
+	checkLiveEntries()
+	assert.Equal(t, 1, len(LiveEntries()))
+
+	clock.Sleep(time.Second)
+	checkLiveEntries()
+	liveEntries.Range(func(_, value interface{}) bool {
+		assert.Equal(t, int32(1), atomic.LoadInt32(&value.(*liveEntry).reported))
+		return true
+	})
+	assert.Equal(t, 1, len(LiveEntries()))
+	assert.False(t, exited)
+
+	clock.Sleep(time.Second)
+	checkLiveEntries()
+	assert.Equal(t, 0, len(LiveEntries()))
+	assert.True(t, e.Leaked())
+	// the leaked entry is left to be exited by the owner
+	assert.False(t, exited)
+	e.Exit()
+	assert.True(t, exited)
+}
+
+func TestReleaseLeakedEntryInUse(t *testing.T) {
+	clock := util.NewMockClock()
+	util.SetClock(clock)
+	defer util.SetClock(util.NewRealClock())
+
+	atomic.StoreUint64(&leakThresholdMs, 1000)
+	atomic.StoreUint64(&maxLifetimeMs, 2000)
+	defer func() {
+		atomic.StoreUint64(&leakThresholdMs, 0)
+		atomic.StoreUint64(&maxLifetimeMs, 0)
+	}()
+
+	const resource = "leaked-in-use"
+	sc := base.NewSlotChain()
+	sc.AddStatSlot(stat.DefaultSlot)
+	ctx := sc.GetPooledContext()
+	rw := base.NewResourceWrapper(resource, base.ResTypeCommon, base.Outbound)
+	ctx.Resource = rw
+	ctx.StatNode = stat.GetOrCreateResourceNode(resource, base.ResTypeCommon)
+	ctx.Input = &base.SentinelInput{BatchCount: 1}
+	e := base.NewSentinelEntry(ctx, rw, sc)
+	ctx.SetEntry(e)
+	stat.DefaultSlot.OnEntryPassed(ctx)
+	node := ctx.StatNode
+	Track(e)
+	assert.Equal(t, int32(1), node.CurrentConcurrency())
+
+	// the owner keeps using the entry while the detector releases it
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			e.SetPair("key", i)
+			e.SetError(errors.New("in use"))
+		}
+	}()
+	clock.Sleep(2 * time.Second)
+	checkLiveEntries()
+	<-done
+
+	assert.True(t, e.Leaked())
+	assert.Equal(t, int32(0), node.CurrentConcurrency())
+	// the context is not recycled by the detector
+	assert.Equal(t, 999, e.Context().GetPair("key"))
+	assert.Equal(t, e, e.Context().Entry())
+
+	// the exit of the owner doesn't release the statistic twice
+	e.Exit()
+	assert.Equal(t, int32(0), node.CurrentConcurrency())
+}
@@ -0,0 +1,35 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package leak provides the entry leak detector, a debug mode which tracks the live entries
+// to find out the ones that are never exited.
+//
+// If SentinelEntry.Exit() is never called, the EntryContext is never returned to the pool,
+// and the concurrency statistic of the resource grows forever, then the isolation rules would
+// block all the requests eventually. The detector records the creation stack and the age of
+// each live entry, reports the entries older than the leak threshold through logging and metric,
+// and releases the statistic (e.g. the concurrency) of the entries older than the max lifetime forcibly
+// if configured. The leaked entries are not exited by the detector, as the context may still be used
+// by the owner, the context is recycled only if the owner exits the entry eventually.
+//
+// The detector captures the call stack of each entry, which has a performance penalty,
+// so it should only be enabled for debugging, via the config item:
+//
+//	sentinel:
+//	  entryLeakDetection:
+//	    enabled: true
+//	    checkIntervalMs: 1000
+//	    leakThresholdMs: 60000
+//	    maxLifetimeMs: 300000
+package leak
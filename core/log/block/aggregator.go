@@ -0,0 +1,183 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package block
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/config"
+	"github.com/Danceiny/sentinel-golang/logging"
+	"github.com/Danceiny/sentinel-golang/util"
+)
+
+// The max amount of distinct block keys in one second, block events of the exceeded keys are dropped.
+const maxKeyAmountPerSecond = 2000
+
+type blockKey struct {
+	resource  string
+	blockType base.BlockType
+	ruleID    string
+	limitKey  string
+}
+
+type blockCountMap = map[blockKey]uint64
+
+var (
+	// The block counts aggregated per second, the key is the start timestamp (ms) of the second.
+	blockCounts = make(map[uint64]blockCountMap)
+	countsMux   = new(sync.Mutex)
+
+	// enabled indicates whether the block events should be recorded, 1 if enabled.
+	enabled  int32
+	stopChan = make(chan struct{})
+
+	blockWriter BlockLogWriter
+	initOnce    sync.Once
+)
+
+// InitTask initializes the block log writer and schedules the task which flushes
+// the aggregated block events into the block log periodically.
+func InitTask() (err error) {
+	initOnce.Do(func() {
+		flushInterval := config.BlockLogFlushIntervalSec()
+		if flushInterval == 0 {
+			return
+		}
+
+		blockWriter, err = NewDefaultBlockLogWriter(config.BlockLogSingleFileMaxSize(), config.BlockLogMaxFileAmount())
+		if err != nil {
+			logging.Error(err, "Failed to initialize the BlockLogWriter in block.InitTask()")
+			return
+		}
+		atomic.StoreInt32(&enabled, 1)
+
+		ticker := util.NewTicker(time.Duration(flushInterval) * time.Second)
+		go util.RunWithRecover(func() {
+			for {
+				select {
+				case <-ticker.C():
+					doFlush()
+				case <-stopChan:
+					ticker.Stop()
+					return
+				}
+			}
+		})
+	})
+	return err
+}
+
+// Record records the block event of the given context into the aggregated block counts of current second.
+// It's a no-op if the block log task is not initialized.
+func Record(ctx *base.EntryContext, blockError *base.BlockError) {
+	if atomic.LoadInt32(&enabled) == 0 || ctx == nil || ctx.Resource == nil || blockError == nil {
+		return
+	}
+	key := blockKey{
+		resource:  ctx.Resource.Name(),
+		blockType: blockError.BlockType(),
+	}
+	if r, ok := blockError.TriggeredRule().(base.IdentifiedRule); ok {
+		key.ruleID = r.RuleID()
+	}
+	if limitKey := ctx.GetPair(base.LimitKeyPairKey); limitKey != nil {
+		key.limitKey = fmt.Sprint(limitKey)
+	}
+	count := uint64(1)
+	if ctx.Input != nil && ctx.Input.BatchCount > 0 {
+		count = uint64(ctx.Input.BatchCount)
+	}
+	now := util.CurrentTimeMillis()
+	secStart := now - now%1000
+
+	countsMux.Lock()
+	defer countsMux.Unlock()
+
+	counts, ok := blockCounts[secStart]
+	if !ok {
+		counts = make(blockCountMap)
+		blockCounts[secStart] = counts
+	}
+	if _, exists := counts[key]; !exists && len(counts) >= maxKeyAmountPerSecond {
+		return
+	}
+	counts[key] += count
+}
+
+// doFlush writes the aggregated block counts of the completed seconds into the block log.
+func doFlush() {
+	now := util.CurrentTimeMillis()
+	curSecStart := now - now%1000
+
+	countsMux.Lock()
+	completed := make(map[uint64]blockCountMap)
+	for ts, counts := range blockCounts {
+		if ts < curSecStart {
+			completed[ts] = counts
+			delete(blockCounts, ts)
+		}
+	}
+	countsMux.Unlock()
+
+	if len(completed) == 0 || blockWriter == nil {
+		return
+	}
+	keys := make([]uint64, 0, len(completed))
+	for ts := range completed {
+		keys = append(keys, ts)
+	}
+	// Sort the time
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	for _, ts := range keys {
+		if err := blockWriter.Write(ts, toBlockItems(ts, completed[ts])); err != nil {
+			logging.Error(err, "[BlockAggregatorTask] Failed to write block items in block.doFlush()")
+		}
+	}
+}
+
+func toBlockItems(ts uint64, counts blockCountMap) []*BlockItem {
+	items := make([]*BlockItem, 0, len(counts))
+	for k, c := range counts {
+		items = append(items, &BlockItem{
+			Timestamp: ts,
+			Resource:  k.resource,
+			BlockType: k.blockType,
+			RuleID:    k.ruleID,
+			LimitKey:  k.limitKey,
+			Count:     c,
+		})
+	}
+	// Keep the items of the same resource together.
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Resource != items[j].Resource {
+			return items[i].Resource < items[j].Resource
+		}
+		if items[i].BlockType != items[j].BlockType {
+			return items[i].BlockType < items[j].BlockType
+		}
+		if items[i].RuleID != items[j].RuleID {
+			return items[i].RuleID < items[j].RuleID
+		}
+		return items[i].LimitKey < items[j].LimitKey
+	})
+	return items
+}
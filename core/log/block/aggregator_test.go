@@ -0,0 +1,97 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package block
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBlockedContext(resource string, limitKey interface{}) *base.EntryContext {
+	ctx := base.NewEmptyEntryContext()
+	ctx.Resource = base.NewResourceWrapper(resource, base.ResTypeCommon, base.Inbound)
+	ctx.Input = &base.SentinelInput{BatchCount: 1}
+	if limitKey != nil {
+		ctx.SetPair(base.LimitKeyPairKey, limitKey)
+	}
+	return ctx
+}
+
+func TestRecordAndFlush(t *testing.T) {
+	clock := util.NewMockClock()
+	util.SetClock(clock)
+	defer util.SetClock(util.NewRealClock())
+
+	dir := t.TempDir()
+	baseFilename := FormBlockFileName("block-test", false)
+	w, err := NewDefaultBlockLogWriterOfDir(1024*1024, 3, dir, baseFilename)
+	assert.Nil(t, err)
+	defer w.(*DefaultBlockLogWriter).Close()
+
+	blockWriter = w
+	atomic.StoreInt32(&enabled, 1)
+	defer func() {
+		blockWriter = nil
+		atomic.StoreInt32(&enabled, 0)
+	}()
+
+	flowErr := base.NewBlockErrorWithCause(base.BlockTypeFlow, "", &flow.Rule{ID: "flow-1", Resource: "abc"}, 10.0)
+	hotspotErr := base.NewBlockError(base.WithBlockType(base.BlockTypeHotSpotParamFlow))
+	beginMs := util.CurrentTimeMillis()
+	beginMs -= beginMs % 1000
+	for i := 0; i < 3; i++ {
+		Record(newBlockedContext("abc", nil), flowErr)
+	}
+	Record(newBlockedContext("abc", "user1"), hotspotErr)
+	Record(newBlockedContext("def", "user1"), hotspotErr)
+	// Incomplete second should not be flushed.
+	doFlush()
+	clock.Sleep(time.Second)
+	Record(newBlockedContext("abc", nil), flowErr)
+	clock.Sleep(time.Second)
+	doFlush()
+	assert.Equal(t, 0, len(blockCounts))
+
+	searcher, err := NewDefaultBlockSearcher(dir, baseFilename)
+	assert.Nil(t, err)
+	items, err := searcher.FindByTimeAndResource(beginMs, util.CurrentTimeMillis(), "abc")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(items))
+	assert.Equal(t, base.BlockTypeFlow, items[0].BlockType)
+	assert.Equal(t, "flow-1", items[0].RuleID)
+	assert.Equal(t, uint64(3), items[0].Count)
+	assert.Equal(t, base.BlockTypeHotSpotParamFlow, items[1].BlockType)
+	assert.Equal(t, "user1", items[1].LimitKey)
+	assert.Equal(t, uint64(1), items[1].Count)
+	assert.Equal(t, items[0].Timestamp+1000, items[2].Timestamp)
+
+	items, err = searcher.FindByTimeAndResource(beginMs, util.CurrentTimeMillis(), "")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(items))
+	items, err = searcher.FindByTimeAndResource(beginMs+1000, util.CurrentTimeMillis(), "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(items))
+}
+
+func TestRecordDisabled(t *testing.T) {
+	Record(newBlockedContext("abc", nil), base.NewBlockError(base.WithBlockType(base.BlockTypeFlow)))
+	assert.Equal(t, 0, len(blockCounts))
+}
@@ -0,0 +1,118 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package block
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/log/metric"
+	"github.com/Danceiny/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BlockFileNameSuffix represents the suffix of the block log file.
+	BlockFileNameSuffix = "sentinel-block.log"
+
+	blockPartSeparator = "|"
+)
+
+// BlockItem represents the aggregated block events in one second
+// of the same resource, block type, rule and limit key.
+type BlockItem struct {
+	Timestamp uint64
+	Resource  string
+	BlockType base.BlockType
+	// RuleID is the ID of the triggered rule, empty if the rule has no ID.
+	RuleID string
+	// LimitKey is the key that the requests are limited by (e.g. the hotspot parameter),
+	// empty if the block type has no such key.
+	LimitKey string
+	Count    uint64
+}
+
+// BlockLogWriter writes and flushes block items to current block log.
+type BlockLogWriter interface {
+	Write(ts uint64, items []*BlockItem) error
+}
+
+// BlockSearcher searches block items from the block log file under given condition.
+type BlockSearcher interface {
+	// FindByTimeAndResource finds the block items whose timestamp is in [beginTimeMs, endTimeMs].
+	// Empty resource indicates all resources.
+	FindByTimeAndResource(beginTimeMs uint64, endTimeMs uint64, resource string) ([]*BlockItem, error)
+}
+
+// ToString formats the BlockItem as a line of the block log:
+// timestamp|time|resource|blockType|ruleId|limitKey|count
+func (i *BlockItem) ToString() string {
+	return fmt.Sprintf("%d|%s|%s|%d|%s|%s|%d",
+		i.Timestamp, util.FormatTimeMillis(i.Timestamp), escapeBlockPart(i.Resource), i.BlockType,
+		escapeBlockPart(i.RuleID), escapeBlockPart(i.LimitKey), i.Count)
+}
+
+// BlockItemFromString parses the BlockItem from a line of the block log.
+func BlockItemFromString(line string) (*BlockItem, error) {
+	if len(line) == 0 {
+		return nil, errors.New("invalid block line: empty string")
+	}
+	arr := strings.Split(line, blockPartSeparator)
+	if len(arr) < 7 {
+		return nil, errors.New("invalid block line: invalid format")
+	}
+	ts, err := strconv.ParseUint(arr[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	bt, err := strconv.ParseUint(arr[3], 10, 8)
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.ParseUint(arr[6], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockItem{
+		Timestamp: ts,
+		Resource:  arr[2],
+		BlockType: base.BlockType(bt),
+		RuleID:    arr[4],
+		LimitKey:  arr[5],
+		Count:     count,
+	}, nil
+}
+
+// All "|" and line separators in the part will be replaced with "_".
+func escapeBlockPart(s string) string {
+	return strings.NewReplacer(blockPartSeparator, "_", "\n", "_", "\r", "_").Replace(s)
+}
+
+// FormBlockFileName generates the block log file name from the service name.
+func FormBlockFileName(serviceName string, withPid bool) string {
+	dot := "."
+	separator := "-"
+	if strings.Contains(serviceName, dot) {
+		serviceName = strings.ReplaceAll(serviceName, dot, separator)
+	}
+	filename := serviceName + separator + BlockFileNameSuffix
+	if withPid {
+		filename = filename + "." + metric.FilePidPrefix + strconv.Itoa(os.Getpid())
+	}
+	return filename
+}
@@ -0,0 +1,60 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package block
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormBlockFileName(t *testing.T) {
+	assert.Equal(t, "foo-test-sentinel-block.log", FormBlockFileName("foo.test", false))
+	withPid := FormBlockFileName("foo-test", true)
+	if !strings.HasSuffix(withPid, ".pid"+strconv.Itoa(os.Getpid())) {
+		t.Fatalf("Block log filename <%s> should end with the process id", withPid)
+	}
+}
+
+func TestBlockItemString(t *testing.T) {
+	item := &BlockItem{
+		Timestamp: 1581959010000,
+		Resource:  "GET:/foo|bar",
+		BlockType: base.BlockTypeHotSpotParamFlow,
+		RuleID:    "rule-1",
+		LimitKey:  "user\n1",
+		Count:     12,
+	}
+	line := item.ToString()
+	assert.Equal(t, 7, len(strings.Split(line, "|")))
+
+	parsed, err := BlockItemFromString(line)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1581959010000), parsed.Timestamp)
+	assert.Equal(t, "GET:/foo_bar", parsed.Resource)
+	assert.Equal(t, base.BlockTypeHotSpotParamFlow, parsed.BlockType)
+	assert.Equal(t, "rule-1", parsed.RuleID)
+	assert.Equal(t, "user_1", parsed.LimitKey)
+	assert.Equal(t, uint64(12), parsed.Count)
+
+	_, err = BlockItemFromString("")
+	assert.NotNil(t, err)
+	_, err = BlockItemFromString("1581959010000|abc|1")
+	assert.NotNil(t, err)
+}
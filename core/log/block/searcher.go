@@ -0,0 +1,49 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package block
+
+import (
+	"github.com/Danceiny/sentinel-golang/core/log/metric"
+	"github.com/pkg/errors"
+)
+
+// DefaultBlockSearcher searches the block items in the block log files,
+// in the same way as the metric log (see metric.LogSearcher).
+type DefaultBlockSearcher struct {
+	searcher *metric.LogSearcher[*BlockItem]
+}
+
+func (s *DefaultBlockSearcher) FindByTimeAndResource(beginTimeMs uint64, endTimeMs uint64, resource string) ([]*BlockItem, error) {
+	if beginTimeMs > endTimeMs {
+		return nil, errors.Errorf("invalid time range: [%d, %d]", beginTimeMs, endTimeMs)
+	}
+	return s.searcher.FindByTimeAndResource(beginTimeMs, endTimeMs, resource)
+}
+
+func NewDefaultBlockSearcher(baseDir, baseFilename string) (BlockSearcher, error) {
+	s, err := metric.NewLogSearcher(baseDir, baseFilename, parseBlockItem)
+	if err != nil {
+		return nil, err
+	}
+	return &DefaultBlockSearcher{searcher: s}, nil
+}
+
+func parseBlockItem(line string) (*BlockItem, uint64, string, error) {
+	item, err := BlockItemFromString(line)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	return item, item.Timestamp, item.Resource, nil
+}
@@ -0,0 +1,51 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package block
+
+import (
+	"github.com/Danceiny/sentinel-golang/core/config"
+	"github.com/Danceiny/sentinel-golang/core/log/metric"
+)
+
+// DefaultBlockLogWriter writes the block items into the rolling block log files, which are
+// rolled and indexed in the same way as the metric log (see metric.RollingLogWriter).
+type DefaultBlockLogWriter struct {
+	*metric.RollingLogWriter
+}
+
+func (d *DefaultBlockLogWriter) Write(ts uint64, items []*BlockItem) error {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		item.Timestamp = ts
+		lines = append(lines, item.ToString())
+	}
+	return d.WriteLines(ts, lines)
+}
+
+func NewDefaultBlockLogWriter(maxSize uint64, maxFileAmount uint32) (BlockLogWriter, error) {
+	logDir := config.LogBaseDir()
+	if len(logDir) == 0 {
+		logDir = config.GetDefaultLogDir()
+	}
+	return NewDefaultBlockLogWriterOfDir(maxSize, maxFileAmount, logDir, FormBlockFileName(config.AppName(), config.LogUsePid()))
+}
+
+func NewDefaultBlockLogWriterOfDir(maxSize uint64, maxFileAmount uint32, baseDir, baseFilename string) (BlockLogWriter, error) {
+	w, err := metric.NewRollingLogWriter(maxSize, maxFileAmount, baseDir, baseFilename)
+	if err != nil {
+		return nil, err
+	}
+	return &DefaultBlockLogWriter{RollingLogWriter: w}, nil
+}
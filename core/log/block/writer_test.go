@@ -0,0 +1,55 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package block
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultBlockLogWriterRolling(t *testing.T) {
+	clock := util.NewMockClock()
+	util.SetClock(clock)
+	defer util.SetClock(util.NewRealClock())
+
+	dir := t.TempDir()
+	baseFilename := FormBlockFileName("block-test", false)
+	// Each write exceeds the max size so that the file is rolled after every write.
+	w, err := NewDefaultBlockLogWriterOfDir(1, 3, dir, baseFilename)
+	assert.Nil(t, err)
+	defer w.(*DefaultBlockLogWriter).Close()
+
+	for i := 0; i < 5; i++ {
+		clock.Sleep(time.Second)
+		err = w.Write(util.CurrentTimeMillis(), []*BlockItem{{Resource: "abc", BlockType: base.BlockTypeFlow, Count: 1}})
+		assert.Nil(t, err)
+	}
+	// The index file is kept along with each block log file.
+	files, err := filepath.Glob(filepath.Join(dir, baseFilename+".*"))
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(files))
+
+	// Items written earlier than the latest second are ignored.
+	err = w.Write(util.CurrentTimeMillis()-2000, []*BlockItem{{Resource: "abc", BlockType: base.BlockTypeFlow, Count: 1}})
+	assert.Nil(t, err)
+
+	_, err = NewDefaultBlockLogWriterOfDir(0, 3, dir, baseFilename)
+	assert.NotNil(t, err)
+}
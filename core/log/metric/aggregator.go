@@ -24,6 +24,7 @@ import (
 	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/Danceiny/sentinel-golang/logging"
 	"github.com/Danceiny/sentinel-golang/util"
+	"github.com/pkg/errors"
 )
 
 type metricTimeMap = map[uint64][]*base.MetricItem
@@ -39,9 +40,37 @@ var (
 	stopChan            = make(chan struct{})
 
 	metricWriter MetricLogWriter
+	// extraWriters are the additional sinks which the aggregated metric items are fanned out to.
+	extraWriters = make([]MetricLogWriter, 0)
+	writersMux   = new(sync.RWMutex)
 	initOnce     sync.Once
 )
 
+// RegisterMetricLogWriter registers the MetricLogWriter sink (e.g. stdout, io.Writer or callback)
+// which the aggregated metric items are written into besides the metric log files.
+// The metric items are shared by all sinks, so they should not be modified by the sink.
+func RegisterMetricLogWriter(w MetricLogWriter) error {
+	if w == nil {
+		return errors.New("nil MetricLogWriter")
+	}
+	writersMux.Lock()
+	defer writersMux.Unlock()
+
+	extraWriters = append(extraWriters, w)
+	return nil
+}
+
+func metricLogWriters() []MetricLogWriter {
+	writersMux.RLock()
+	defer writersMux.RUnlock()
+
+	writers := make([]MetricLogWriter, 0, len(extraWriters)+1)
+	if metricWriter != nil {
+		writers = append(writers, metricWriter)
+	}
+	return append(writers, extraWriters...)
+}
+
 func InitTask() (err error) {
 	initOnce.Do(func() {
 		flushInterval := config.MetricLogFlushIntervalSec()
@@ -87,10 +116,13 @@ func writeTaskLoop() {
 				return keys[i] < keys[j]
 			})
 
+			writers := metricLogWriters()
 			for _, t := range keys {
-				err := metricWriter.Write(t, m[t])
-				if err != nil {
-					logging.Error(err, "[MetricAggregatorTask] fail tp write metric in aggregator.writeTaskLoop()")
+				for _, w := range writers {
+					err := w.Write(t, m[t])
+					if err != nil {
+						logging.Error(err, "[MetricAggregatorTask] fail tp write metric in aggregator.writeTaskLoop()")
+					}
 				}
 			}
 		}
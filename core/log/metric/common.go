@@ -48,8 +48,14 @@ type MetricLogWriter interface {
 
 // MetricSearcher searches metric items from the metric log file under given condition.
 type MetricSearcher interface {
+	// FindByTimeAndResource finds the metric items whose timestamp is in [beginTimeMs, endTimeMs],
+	// the amount of the items may be limited.
 	FindByTimeAndResource(beginTimeMs uint64, endTimeMs uint64, resource string) ([]*base.MetricItem, error)
 
+	// VisitByTimeAndResource visits all the metric items whose timestamp is in [beginTimeMs, endTimeMs]
+	// without materializing them, the visiting stops once visit returns false.
+	VisitByTimeAndResource(beginTimeMs uint64, endTimeMs uint64, resource string, visit func(*base.MetricItem) bool) error
+
 	FindFromTimeWithMaxLines(beginTimeMs uint64, maxLines uint32) ([]*base.MetricItem, error)
 }
 
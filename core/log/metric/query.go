@@ -0,0 +1,306 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/pkg/errors"
+)
+
+const (
+	Bucket10s uint64 = 10 * 1000
+	Bucket1m  uint64 = 60 * 1000
+	Bucket5m  uint64 = 5 * 60 * 1000
+)
+
+// GroupBy represents how the metric items of different resources are aggregated.
+type GroupBy int32
+
+const (
+	// GroupByResource aggregates the metric items of each resource separately.
+	GroupByResource GroupBy = iota
+	// GroupByClassification aggregates the metric items of the resources of the same classification together.
+	GroupByClassification
+	// GroupByAll aggregates the metric items of all the matched resources together.
+	GroupByAll
+)
+
+// GroupKeyAll is the key of the aggregated metrics grouped by GroupByAll.
+const GroupKeyAll = "*"
+
+// MetricQuery represents the condition of the metric history query.
+type MetricQuery struct {
+	// BeginTimeMs and EndTimeMs represent the time range [BeginTimeMs, EndTimeMs] of the query.
+	BeginTimeMs uint64
+	EndTimeMs   uint64
+	// Resource matches the resource of exactly the same name, empty indicates all resources.
+	Resource string
+	// ResourcePrefix matches the resources whose name has the prefix.
+	ResourcePrefix string
+	// Classification matches the resources of the classification if not nil.
+	Classification *int32
+	// BucketMs is the length of the time buckets, like Bucket10s and Bucket1m.
+	// 0 indicates the whole time range is aggregated into a single bucket.
+	BucketMs uint64
+	GroupBy  GroupBy
+}
+
+// AggregatedStat represents the aggregation of a metric in a time bucket.
+// Avg is the average per second over the seconds of the bucket in the queried time range.
+type AggregatedStat struct {
+	Sum uint64
+	Avg float64
+	Max uint64
+}
+
+// AggregatedMetric represents the aggregated metrics of a group in a time bucket.
+type AggregatedMetric struct {
+	// Timestamp is the start time of the bucket.
+	Timestamp uint64
+	// Key is the resource name, the classification or GroupKeyAll according to the GroupBy of the query.
+	Key string
+
+	Pass        AggregatedStat
+	Block       AggregatedStat
+	Complete    AggregatedStat
+	Error       AggregatedStat
+	Concurrency AggregatedStat
+	Slow        AggregatedStat
+	// Rt.Avg is the average RT weighted by the complete count, Rt.Max is the max RT
+	// (the max of the average RT if the max RT is absent), Rt.Sum is meaningless thus always 0.
+	Rt AggregatedStat
+}
+
+// ResourceBlockStat represents the total block and pass count of a resource in a time range.
+type ResourceBlockStat struct {
+	Resource string
+	Block    uint64
+	Pass     uint64
+}
+
+// MetricQuerier queries the metric history from the metric logs through the MetricSearcher.
+type MetricQuerier struct {
+	searcher MetricSearcher
+}
+
+func NewMetricQuerier(searcher MetricSearcher) (*MetricQuerier, error) {
+	if searcher == nil {
+		return nil, errors.New("nil MetricSearcher")
+	}
+	return &MetricQuerier{searcher: searcher}, nil
+}
+
+// Query aggregates the metric items matching the query over the time buckets, ordered by key and timestamp.
+func (q *MetricQuerier) Query(query *MetricQuery) ([]*AggregatedMetric, error) {
+	if query == nil {
+		return nil, errors.New("nil query")
+	}
+	// Merge the items of the same group in the same second while visiting the items,
+	// so that the max of the group is the max of the per-second total.
+	type groupSecond struct {
+		key string
+		ts  uint64
+	}
+	merged := make(map[groupSecond]*base.MetricItem)
+	err := q.visitItems(query, func(item *base.MetricItem) {
+		gs := groupSecond{key: groupKeyOf(item, query.GroupBy), ts: item.Timestamp - item.Timestamp%1000}
+		m, ok := merged[gs]
+		if !ok {
+			m = &base.MetricItem{Timestamp: gs.ts}
+			merged[gs] = m
+		}
+		mergeMetricItem(m, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type groupBucket struct {
+		key string
+		ts  uint64
+	}
+	buckets := make(map[groupBucket]*AggregatedMetric)
+	rtWeights := make(map[groupBucket]uint64)
+	for gs, item := range merged {
+		gb := groupBucket{key: gs.key, ts: bucketStartOf(gs.ts, query)}
+		am, ok := buckets[gb]
+		if !ok {
+			am = &AggregatedMetric{Timestamp: gb.ts, Key: gb.key}
+			buckets[gb] = am
+		}
+		addStat(&am.Pass, item.PassQps)
+		addStat(&am.Block, item.BlockQps)
+		addStat(&am.Complete, item.CompleteQps)
+		addStat(&am.Error, item.ErrorQps)
+		addStat(&am.Concurrency, uint64(item.Concurrency))
+		addStat(&am.Slow, item.SlowQps)
+		maxRt := item.MaxRt
+		if item.AvgRt > maxRt {
+			maxRt = item.AvgRt
+		}
+		if maxRt > am.Rt.Max {
+			am.Rt.Max = maxRt
+		}
+		am.Rt.Avg += float64(item.AvgRt * item.CompleteQps)
+		rtWeights[gb] += item.CompleteQps
+	}
+
+	result := make([]*AggregatedMetric, 0, len(buckets))
+	for gb, am := range buckets {
+		seconds := float64(bucketSeconds(gb.ts, query))
+		am.Pass.Avg = float64(am.Pass.Sum) / seconds
+		am.Block.Avg = float64(am.Block.Sum) / seconds
+		am.Complete.Avg = float64(am.Complete.Sum) / seconds
+		am.Error.Avg = float64(am.Error.Sum) / seconds
+		am.Concurrency.Avg = float64(am.Concurrency.Sum) / seconds
+		am.Slow.Avg = float64(am.Slow.Sum) / seconds
+		if w := rtWeights[gb]; w > 0 {
+			am.Rt.Avg = am.Rt.Avg / float64(w)
+		}
+		result = append(result, am)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Key != result[j].Key {
+			return result[i].Key < result[j].Key
+		}
+		return result[i].Timestamp < result[j].Timestamp
+	})
+	return result, nil
+}
+
+// TopNByBlock finds the top-N resources of the most block count in the time range [beginTimeMs, endTimeMs].
+// The resources without any blocked request are excluded.
+func (q *MetricQuerier) TopNByBlock(beginTimeMs, endTimeMs uint64, n int) ([]*ResourceBlockStat, error) {
+	if n <= 0 {
+		return nil, errors.Errorf("invalid n: %d", n)
+	}
+	stats := make(map[string]*ResourceBlockStat)
+	err := q.searcher.VisitByTimeAndResource(beginTimeMs, endTimeMs, "", func(item *base.MetricItem) bool {
+		s, ok := stats[item.Resource]
+		if !ok {
+			s = &ResourceBlockStat{Resource: item.Resource}
+			stats[item.Resource] = s
+		}
+		s.Block += item.BlockQps
+		s.Pass += item.PassQps
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*ResourceBlockStat, 0, len(stats))
+	for _, s := range stats {
+		if s.Block > 0 {
+			result = append(result, s)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Block != result[j].Block {
+			return result[i].Block > result[j].Block
+		}
+		return result[i].Resource < result[j].Resource
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result, nil
+}
+
+// visitItems visits all the metric items matching the query, the items are not materialized
+// so that the time range holding a large amount of items is aggregated completely.
+func (q *MetricQuerier) visitItems(query *MetricQuery, visit func(*base.MetricItem)) error {
+	return q.searcher.VisitByTimeAndResource(query.BeginTimeMs, query.EndTimeMs, query.Resource, func(item *base.MetricItem) bool {
+		if !strings.HasPrefix(item.Resource, query.ResourcePrefix) {
+			return true
+		}
+		if query.Classification != nil && item.Classification != *query.Classification {
+			return true
+		}
+		visit(item)
+		return true
+	})
+}
+
+func groupKeyOf(item *base.MetricItem, groupBy GroupBy) string {
+	switch groupBy {
+	case GroupByClassification:
+		return strconv.Itoa(int(item.Classification))
+	case GroupByAll:
+		return GroupKeyAll
+	default:
+		return item.Resource
+	}
+}
+
+// mergeMetricItem merges the item into the target item of the same second.
+func mergeMetricItem(target, item *base.MetricItem) {
+	completes := target.CompleteQps + item.CompleteQps
+	if completes > 0 {
+		target.AvgRt = (target.AvgRt*target.CompleteQps + item.AvgRt*item.CompleteQps) / completes
+	}
+	if item.CompleteQps > 0 {
+		if target.CompleteQps == 0 || item.MinRt < target.MinRt {
+			target.MinRt = item.MinRt
+		}
+		if item.MaxRt > target.MaxRt {
+			target.MaxRt = item.MaxRt
+		}
+	}
+	target.SlowQps += item.SlowQps
+	target.PassQps += item.PassQps
+	target.BlockQps += item.BlockQps
+	target.CompleteQps = completes
+	target.ErrorQps += item.ErrorQps
+	target.OccupiedPassQps += item.OccupiedPassQps
+	target.Concurrency += item.Concurrency
+}
+
+func addStat(s *AggregatedStat, v uint64) {
+	s.Sum += v
+	if v > s.Max {
+		s.Max = v
+	}
+}
+
+func bucketStartOf(ts uint64, query *MetricQuery) uint64 {
+	if query.BucketMs == 0 {
+		return query.BeginTimeMs - query.BeginTimeMs%1000
+	}
+	return ts - ts%query.BucketMs
+}
+
+// bucketSeconds returns the amount of seconds of the bucket in the queried time range.
+func bucketSeconds(bucketStart uint64, query *MetricQuery) uint64 {
+	begin := query.BeginTimeMs - query.BeginTimeMs%1000
+	end := query.EndTimeMs - query.EndTimeMs%1000 + 1000
+	bucketEnd := end
+	if query.BucketMs > 0 {
+		bucketEnd = bucketStart + query.BucketMs
+	}
+	if bucketStart > begin {
+		begin = bucketStart
+	}
+	if bucketEnd < end {
+		end = bucketEnd
+	}
+	if end <= begin {
+		return 1
+	}
+	return (end - begin) / 1000
+}
@@ -0,0 +1,180 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/config"
+	"github.com/Danceiny/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMetricSearcher struct {
+	items []*base.MetricItem
+}
+
+func (s *fakeMetricSearcher) FindByTimeAndResource(beginTimeMs uint64, endTimeMs uint64, resource string) ([]*base.MetricItem, error) {
+	ret := make([]*base.MetricItem, 0)
+	for _, item := range s.items {
+		if item.Timestamp < beginTimeMs || item.Timestamp > endTimeMs {
+			continue
+		}
+		if resource != "" && resource != item.Resource {
+			continue
+		}
+		ret = append(ret, item)
+	}
+	return ret, nil
+}
+
+func (s *fakeMetricSearcher) VisitByTimeAndResource(beginTimeMs uint64, endTimeMs uint64, resource string, visit func(*base.MetricItem) bool) error {
+	items, _ := s.FindByTimeAndResource(beginTimeMs, endTimeMs, resource)
+	for _, item := range items {
+		if !visit(item) {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *fakeMetricSearcher) FindFromTimeWithMaxLines(beginTimeMs uint64, maxLines uint32) ([]*base.MetricItem, error) {
+	return nil, nil
+}
+
+func newTestQuerier(t *testing.T) *MetricQuerier {
+	q, err := NewMetricQuerier(&fakeMetricSearcher{items: []*base.MetricItem{
+		{Resource: "/api/a", Classification: 1, Timestamp: 1000, PassQps: 10, BlockQps: 1, CompleteQps: 10, AvgRt: 10, Concurrency: 2},
+		{Resource: "/api/a", Classification: 1, Timestamp: 2000, PassQps: 20, BlockQps: 5, CompleteQps: 30, AvgRt: 20, Concurrency: 4},
+		{Resource: "/api/a", Classification: 1, Timestamp: 11000, PassQps: 5, CompleteQps: 5, AvgRt: 4},
+		{Resource: "/api/b", Classification: 1, Timestamp: 1000, PassQps: 1, BlockQps: 8, CompleteQps: 1, AvgRt: 100, Concurrency: 1},
+		{Resource: "db", Classification: 2, Timestamp: 2000, PassQps: 3, BlockQps: 3, CompleteQps: 3, AvgRt: 1},
+	}})
+	assert.NoError(t, err)
+	return q
+}
+
+func TestMetricQuerier_Query(t *testing.T) {
+	_, err := NewMetricQuerier(nil)
+	assert.Error(t, err)
+	q := newTestQuerier(t)
+
+	t.Run("GroupByResourceWithBuckets", func(t *testing.T) {
+		ret, err := q.Query(&MetricQuery{BeginTimeMs: 0, EndTimeMs: 19999, Resource: "/api/a", BucketMs: Bucket10s})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(ret))
+		assert.Equal(t, uint64(0), ret[0].Timestamp)
+		assert.Equal(t, "/api/a", ret[0].Key)
+		assert.Equal(t, uint64(30), ret[0].Pass.Sum)
+		assert.Equal(t, uint64(20), ret[0].Pass.Max)
+		assert.Equal(t, float64(3), ret[0].Pass.Avg)
+		assert.Equal(t, uint64(6), ret[0].Block.Sum)
+		assert.Equal(t, uint64(4), ret[0].Concurrency.Max)
+		// (10*10 + 20*30) / 40
+		assert.Equal(t, 17.5, ret[0].Rt.Avg)
+		assert.Equal(t, uint64(20), ret[0].Rt.Max)
+		assert.Equal(t, uint64(10000), ret[1].Timestamp)
+		assert.Equal(t, uint64(5), ret[1].Pass.Sum)
+	})
+
+	t.Run("GroupByAllWithPrefix", func(t *testing.T) {
+		ret, err := q.Query(&MetricQuery{BeginTimeMs: 0, EndTimeMs: 9999, ResourcePrefix: "/api/", GroupBy: GroupByAll})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(ret))
+		assert.Equal(t, GroupKeyAll, ret[0].Key)
+		assert.Equal(t, uint64(31), ret[0].Pass.Sum)
+		assert.Equal(t, uint64(14), ret[0].Block.Sum)
+		// The max is the max of per-second total: 10+1 at 1000 and 20 at 2000.
+		assert.Equal(t, uint64(20), ret[0].Pass.Max)
+		assert.Equal(t, uint64(9), ret[0].Block.Max)
+		assert.Equal(t, uint64(4), ret[0].Concurrency.Max)
+	})
+
+	t.Run("GroupByClassification", func(t *testing.T) {
+		cl := int32(2)
+		ret, err := q.Query(&MetricQuery{BeginTimeMs: 0, EndTimeMs: 19999, GroupBy: GroupByClassification})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(ret))
+		assert.Equal(t, "1", ret[0].Key)
+		assert.Equal(t, uint64(36), ret[0].Pass.Sum)
+		assert.Equal(t, "2", ret[1].Key)
+		assert.Equal(t, uint64(3), ret[1].Pass.Sum)
+
+		ret, err = q.Query(&MetricQuery{BeginTimeMs: 0, EndTimeMs: 19999, Classification: &cl})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(ret))
+		assert.Equal(t, "db", ret[0].Key)
+	})
+}
+
+func TestMetricQuerier_TopNByBlock(t *testing.T) {
+	q := newTestQuerier(t)
+	_, err := q.TopNByBlock(0, 19999, 0)
+	assert.Error(t, err)
+
+	ret, err := q.TopNByBlock(0, 19999, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(ret))
+	assert.Equal(t, "/api/b", ret[0].Resource)
+	assert.Equal(t, uint64(8), ret[0].Block)
+	assert.Equal(t, "/api/a", ret[1].Resource)
+	assert.Equal(t, uint64(6), ret[1].Block)
+	assert.Equal(t, uint64(35), ret[1].Pass)
+}
+
+func TestMetricQuerier_ItemsExceedingMaxAmount(t *testing.T) {
+	clock := util.NewMockClock()
+	util.SetClock(clock)
+	defer util.SetClock(util.NewRealClock())
+	defer func(amount int) { maxItemAmount = amount }(maxItemAmount)
+	maxItemAmount = 3
+
+	dir := t.TempDir()
+	baseFilename := FormMetricFileName("query-test", false)
+	rw, err := NewRollingLogWriter(1024*1024, 3, dir, baseFilename)
+	assert.NoError(t, err)
+	defer rw.Close()
+	w := &DefaultMetricLogWriter{RollingLogWriter: rw, format: config.MetricLogFormatFat}
+
+	beginMs := util.CurrentTimeMillis()
+	beginMs -= beginMs % 1000
+	for i := uint64(0); i < 3; i++ {
+		assert.NoError(t, w.Write(beginMs+i*1000, []*base.MetricItem{
+			{Resource: "abc", PassQps: 1, BlockQps: 1},
+			{Resource: "def", PassQps: 2, BlockQps: 2},
+		}))
+	}
+	searcher, err := NewDefaultMetricSearcher(dir, baseFilename)
+	assert.NoError(t, err)
+	items, err := searcher.FindByTimeAndResource(beginMs, beginMs+2000, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(items))
+
+	// The querier aggregates all the items beyond the max amount.
+	q, err := NewMetricQuerier(searcher)
+	assert.NoError(t, err)
+	ret, err := q.Query(&MetricQuery{BeginTimeMs: beginMs, EndTimeMs: beginMs + 2000, GroupBy: GroupByAll})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(ret))
+	assert.Equal(t, uint64(9), ret[0].Pass.Sum)
+
+	top, err := q.TopNByBlock(beginMs, beginMs+2000, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(top))
+	assert.Equal(t, "def", top[0].Resource)
+	assert.Equal(t, uint64(6), top[0].Block)
+	assert.Equal(t, uint64(3), top[1].Block)
+}
@@ -24,24 +24,36 @@ import (
 	"github.com/pkg/errors"
 )
 
-const maxItemAmount = 100000
+// maxItemAmount is the max amount of the items read by ReadItemsByEndTime to avoid infinite reading,
+// the items are not limited when visited by VisitItemsByEndTime.
+var maxItemAmount = 100000
 
-type MetricLogReader interface {
-	ReadMetrics(nameList []string, fileNo uint32, startOffset uint64, maxLines uint32) ([]*base.MetricItem, error)
+// LogItemParser parses the line of the log file into the item, along with the timestamp (ms) and the resource of the item.
+type LogItemParser[T any] func(line string) (item T, timestampMs uint64, resource string, err error)
 
-	ReadMetricsByEndTime(nameList []string, fileNo uint32, startOffset uint64, beginMs uint64, endMs uint64, resource string) ([]*base.MetricItem, error)
+// LogReader reads the items from the log files written by RollingLogWriter.
+type LogReader[T any] interface {
+	ReadItems(nameList []string, fileNo uint32, startOffset uint64, maxLines uint32) ([]T, error)
+
+	ReadItemsByEndTime(nameList []string, fileNo uint32, startOffset uint64, beginMs uint64, endMs uint64, resource string) ([]T, error)
+
+	// VisitItemsByEndTime visits the items in [beginMs, endMs] without materializing them,
+	// the visiting stops once visit returns false.
+	VisitItemsByEndTime(nameList []string, fileNo uint32, startOffset uint64, beginMs uint64, endMs uint64, resource string, visit func(T) bool) error
 }
 
-// Not thread-safe itself, but guarded by the outside MetricSearcher.
-type defaultMetricLogReader struct {
+type MetricLogReader = LogReader[*base.MetricItem]
+
+type defaultLogReader[T any] struct {
+	parse LogItemParser[T]
 }
 
-func (r *defaultMetricLogReader) ReadMetrics(nameList []string, fileNo uint32, startOffset uint64, maxLines uint32) ([]*base.MetricItem, error) {
+func (r *defaultLogReader[T]) ReadItems(nameList []string, fileNo uint32, startOffset uint64, maxLines uint32) ([]T, error) {
 	if len(nameList) == 0 {
-		return make([]*base.MetricItem, 0), nil
+		return make([]T, 0), nil
 	}
 	// startOffset: the offset of the first file to read
-	items, shouldContinue, err := r.readMetricsInOneFile(nameList[fileNo], startOffset, maxLines, 0, 0)
+	items, lastSec, shouldContinue, err := r.readItemsInOneFile(nameList[fileNo], startOffset, maxLines, 0, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +67,8 @@ func (r *defaultMetricLogReader) ReadMetrics(nameList []string, fileNo uint32, s
 			// No files to read.
 			break
 		}
-		arr, shouldContinue, err := r.readMetricsInOneFile(nameList[fileNo], 0, maxLines, getLatestSecond(items), uint32(len(items)))
+		var arr []T
+		arr, lastSec, shouldContinue, err = r.readItemsInOneFile(nameList[fileNo], 0, maxLines, lastSec, uint32(len(items)))
 		if err != nil {
 			return nil, err
 		}
@@ -68,108 +81,101 @@ func (r *defaultMetricLogReader) ReadMetrics(nameList []string, fileNo uint32, s
 	return items, nil
 }
 
-func (r *defaultMetricLogReader) ReadMetricsByEndTime(nameList []string, fileNo uint32, startOffset uint64, beginMs uint64, endMs uint64, resource string) ([]*base.MetricItem, error) {
-	if len(nameList) == 0 {
-		return make([]*base.MetricItem, 0), nil
-	}
-	// startOffset: the offset of the first file to read
-	items, shouldContinue, err := r.readMetricsInOneFileByEndTime(nameList[fileNo], startOffset, beginMs, endMs, resource, 0)
+func (r *defaultLogReader[T]) ReadItemsByEndTime(nameList []string, fileNo uint32, startOffset uint64, beginMs uint64, endMs uint64, resource string) ([]T, error) {
+	items := make([]T, 0, 1024)
+	err := r.VisitItemsByEndTime(nameList, fileNo, startOffset, beginMs, endMs, resource, func(item T) bool {
+		items = append(items, item)
+		// Max items limit to avoid infinite reading
+		return len(items) < maxItemAmount
+	})
 	if err != nil {
 		return nil, err
 	}
-	if !shouldContinue {
-		return items, nil
-	}
-	fileNo++
-	// Continue reading until the size or time does not satisfy the condition
-	for {
-		if int(fileNo) >= len(nameList) {
-			// No files to read.
-			break
-		}
-		arr, shouldContinue, err := r.readMetricsInOneFileByEndTime(nameList[fileNo], 0, beginMs, endMs, resource, uint32(len(items)))
+	return items, nil
+}
+
+func (r *defaultLogReader[T]) VisitItemsByEndTime(nameList []string, fileNo uint32, startOffset uint64, beginMs uint64, endMs uint64, resource string, visit func(T) bool) error {
+	// startOffset: the offset of the first file to read
+	for offset := startOffset; int(fileNo) < len(nameList); fileNo++ {
+		shouldContinue, err := r.visitItemsInOneFileByEndTime(nameList[fileNo], offset, beginMs, endMs, resource, visit)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		items = append(items, arr...)
 		if !shouldContinue {
 			break
 		}
-		fileNo++
+		// Continue reading the next file from the beginning until the time does not satisfy the condition
+		offset = 0
 	}
-	return items, nil
+	return nil
 }
 
-func (r *defaultMetricLogReader) readMetricsInOneFile(filename string, offset uint64, maxLines uint32, lastSec uint64, prevSize uint32) ([]*base.MetricItem, bool, error) {
+// readItemsInOneFile reads the items from the offset of the file, and returns the second of the last item read.
+func (r *defaultLogReader[T]) readItemsInOneFile(filename string, offset uint64, maxLines uint32, lastSec uint64, prevSize uint32) ([]T, uint64, bool, error) {
 	file, err := openFileAndSeekTo(filename, offset)
 	if err != nil {
-		return nil, false, err
+		return nil, lastSec, false, err
 	}
 	defer file.Close()
 
 	bufReader := bufio.NewReaderSize(file, 8192)
-	items := make([]*base.MetricItem, 0, 1024)
+	items := make([]T, 0, 1024)
 	for {
 		line, err := readLine(bufReader)
 		if err != nil {
 			if err == io.EOF {
 				shouldContinue := prevSize+uint32(len(items)) < maxLines
-				return items, shouldContinue, nil
+				return items, lastSec, shouldContinue, nil
 			}
-			return nil, false, errors.Wrap(err, "error when reading lines from file")
+			return nil, lastSec, false, errors.Wrap(err, "error when reading lines from file")
 		}
-		item, err := base.MetricItemFromFatString(line)
+		item, ts, _, err := r.parse(line)
 		if err != nil {
-			logging.Error(err, "Failed to convert MetricItem to string in defaultMetricLogReader.readMetricsInOneFile()")
+			logging.Error(err, "Failed to parse the line of log file in defaultLogReader.readItemsInOneFile()", "fileLine", line)
 			continue
 		}
-		tsSec := item.Timestamp / 1000
+		tsSec := ts / 1000
 
 		if prevSize+uint32(len(items)) >= maxLines && tsSec != lastSec {
-			return items, false, nil
+			return items, lastSec, false, nil
 		}
 		items = append(items, item)
 		lastSec = tsSec
 	}
 }
 
-func (r *defaultMetricLogReader) readMetricsInOneFileByEndTime(filename string, offset uint64, beginMs uint64, endMs uint64, resource string, prevSize uint32) ([]*base.MetricItem, bool, error) {
+func (r *defaultLogReader[T]) visitItemsInOneFileByEndTime(filename string, offset uint64, beginMs uint64, endMs uint64, resource string, visit func(T) bool) (bool, error) {
 	beginSec := beginMs / 1000
 	endSec := endMs / 1000
 	file, err := openFileAndSeekTo(filename, offset)
 	if err != nil {
-		return nil, false, err
+		return false, err
 	}
 	defer file.Close()
 
 	bufReader := bufio.NewReaderSize(file, 8192)
-	items := make([]*base.MetricItem, 0, 1024)
 	for {
 		line, err := readLine(bufReader)
 		if err != nil {
 			if err == io.EOF {
-				return items, true, nil
+				return true, nil
 			}
-			return nil, false, errors.Wrap(err, "error when reading lines from file")
+			return false, errors.Wrap(err, "error when reading lines from file")
 		}
-		item, err := base.MetricItemFromFatString(line)
+		item, ts, res, err := r.parse(line)
 		if err != nil {
-			logging.Error(err, "Invalid line of metric file in defaultMetricLogReader.readMetricsInOneFileByEndTime()", "fileLine", line)
+			logging.Error(err, "Invalid line of log file in defaultLogReader.visitItemsInOneFileByEndTime()", "fileLine", line)
 			continue
 		}
-		tsSec := item.Timestamp / 1000
+		tsSec := ts / 1000
 		// currentSecond should in [beginSec, endSec]
 		if tsSec < beginSec || tsSec > endSec {
-			return items, false, nil
+			return false, nil
 		}
 
 		// empty resource name indicates "fetch all"
-		if resource == "" || resource == item.Resource {
-			items = append(items, item)
-		}
-		// Max items limit to avoid infinite reading
-		if len(items)+int(prevSize) >= maxItemAmount {
-			return items, false, nil
+		if (resource == "" || resource == res) && !visit(item) {
+			return false, nil
 		}
 	}
 }
@@ -189,13 +195,6 @@ func readLine(bufReader *bufio.Reader) (string, error) {
 	}
 }
 
-func getLatestSecond(items []*base.MetricItem) uint64 {
-	if items == nil || len(items) == 0 {
-		return 0
-	}
-	return items[len(items)-1].Timestamp / 1000
-}
-
 func openFileAndSeekTo(filename string, offset uint64) (*os.File, error) {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -211,6 +210,6 @@ func openFileAndSeekTo(filename string, offset uint64) (*os.File, error) {
 	return file, nil
 }
 
-func newDefaultMetricLogReader() MetricLogReader {
-	return &defaultMetricLogReader{}
+func newDefaultLogReader[T any](parse LogItemParser[T]) LogReader[T] {
+	return &defaultLogReader[T]{parse: parse}
 }
@@ -28,8 +28,10 @@ import (
 
 const offsetNotFound = -1
 
-type DefaultMetricSearcher struct {
-	reader MetricLogReader
+// LogSearcher searches the items in the log files written by RollingLogWriter,
+// the index files are used to locate the offset to start reading.
+type LogSearcher[T any] struct {
+	reader LogReader[T]
 
 	baseDir      string
 	baseFilename string
@@ -49,49 +51,73 @@ type filePosition struct {
 	// TODO: cache the idx file handle here?
 }
 
-func (s *DefaultMetricSearcher) FindByTimeAndResource(beginTimeMs uint64, endTimeMs uint64, resource string) ([]*base.MetricItem, error) {
-	return s.searchOffsetAndRead(beginTimeMs, func(filenames []string, fileNo uint32, offset uint64) (items []*base.MetricItem, err error) {
-		return s.reader.ReadMetricsByEndTime(filenames, fileNo, offset, beginTimeMs, endTimeMs, resource)
-	})
+// FindByTimeAndResource finds the items whose timestamp is in [beginTimeMs, endTimeMs],
+// at most 100000 items are returned to avoid infinite reading.
+func (s *LogSearcher[T]) FindByTimeAndResource(beginTimeMs uint64, endTimeMs uint64, resource string) ([]T, error) {
+	filenames, fileNo, offset, err := s.searchOffset(beginTimeMs)
+	if err != nil {
+		return nil, err
+	}
+	if fileNo < 0 {
+		return make([]T, 0), nil
+	}
+	return s.reader.ReadItemsByEndTime(filenames, uint32(fileNo), offset, beginTimeMs, endTimeMs, resource)
 }
 
-func (s *DefaultMetricSearcher) FindFromTimeWithMaxLines(beginTimeMs uint64, maxLines uint32) ([]*base.MetricItem, error) {
-	return s.searchOffsetAndRead(beginTimeMs, func(filenames []string, fileNo uint32, offset uint64) (items []*base.MetricItem, err error) {
-		return s.reader.ReadMetrics(filenames, fileNo, offset, maxLines)
-	})
+// VisitByTimeAndResource visits all the items whose timestamp is in [beginTimeMs, endTimeMs] one by one,
+// the visiting stops once visit returns false.
+func (s *LogSearcher[T]) VisitByTimeAndResource(beginTimeMs uint64, endTimeMs uint64, resource string, visit func(T) bool) error {
+	filenames, fileNo, offset, err := s.searchOffset(beginTimeMs)
+	if err != nil || fileNo < 0 {
+		return err
+	}
+	return s.reader.VisitItemsByEndTime(filenames, uint32(fileNo), offset, beginTimeMs, endTimeMs, resource, visit)
 }
 
-func (s *DefaultMetricSearcher) searchOffsetAndRead(beginTimeMs uint64, doRead func([]string, uint32, uint64) ([]*base.MetricItem, error)) ([]*base.MetricItem, error) {
-	filenames, err := listMetricFiles(s.baseDir, s.baseFilename)
+func (s *LogSearcher[T]) FindFromTimeWithMaxLines(beginTimeMs uint64, maxLines uint32) ([]T, error) {
+	filenames, fileNo, offset, err := s.searchOffset(beginTimeMs)
 	if err != nil {
 		return nil, err
 	}
+	if fileNo < 0 {
+		return make([]T, 0), nil
+	}
+	return s.reader.ReadItems(filenames, uint32(fileNo), offset, maxLines)
+}
+
+// searchOffset finds the file and the offset in the file to start reading the items from beginTimeMs,
+// fileNo is -1 if no file satisfies the condition.
+func (s *LogSearcher[T]) searchOffset(beginTimeMs uint64) (filenames []string, fileNo int64, offset uint64, err error) {
+	filenames, err = listMetricFiles(s.baseDir, s.baseFilename)
+	if err != nil {
+		return nil, -1, 0, err
+	}
 	// Try to position the latest file index and offset from the cache (fast-path).
 	// If cache is not up-to-date, we'll read from the initial position (offset 0 of the first file).
-	offsetStart, fileNo, err := s.getOffsetStartAndFileIdx(filenames, beginTimeMs)
+	offsetStart, startNo, err := s.getOffsetStartAndFileIdx(filenames, beginTimeMs)
 	if err != nil {
-		logging.Warn("[searchOffsetAndRead] Failed to getOffsetStartAndFileIdx", "beginTimeMs", beginTimeMs, "err", err.Error())
+		logging.Warn("[searchOffset] Failed to getOffsetStartAndFileIdx", "beginTimeMs", beginTimeMs, "err", err.Error())
 	}
 	fileAmount := uint32(len(filenames))
-	for i := fileNo; i < fileAmount; i++ {
+	for i := startNo; i < fileAmount; i++ {
 		filename := filenames[i]
 		// Retrieve the start offset that is valid for given condition.
 		// If offset = -1, it indicates that current file (i) does not satisfy the condition.
-		offset, err := s.findOffsetToStart(filename, beginTimeMs, offsetStart)
+		pos, err := s.findOffsetToStart(filename, beginTimeMs, offsetStart)
 		if err != nil {
-			logging.Warn("[searchOffsetAndRead] Failed to findOffsetToStart, will try next file", "beginTimeMs", beginTimeMs,
+			logging.Warn("[searchOffset] Failed to findOffsetToStart, will try next file", "beginTimeMs", beginTimeMs,
 				"filename", filename, "offsetStart", offsetStart, "err", err)
 			continue
 		}
-		if offset >= 0 {
-			// Read metric items from the offset of current file (number i).
-			return doRead(filenames, i, uint64(offset))
+		if pos >= 0 {
+			// Read items from the offset of current file (number i).
+			return filenames, int64(i), uint64(pos), nil
 		}
 	}
-	return make([]*base.MetricItem, 0), nil
+	return filenames, -1, 0, nil
 }
 
-func (s *DefaultMetricSearcher) getOffsetStartAndFileIdx(filenames []string, beginTimeMs uint64) (offsetInIdx uint64, i uint32, err error) {
+func (s *LogSearcher[T]) getOffsetStartAndFileIdx(filenames []string, beginTimeMs uint64) (offsetInIdx uint64, i uint32, err error) {
 	cacheOk, err := s.isPositionInTimeFor(beginTimeMs)
 	if err != nil {
 		return
@@ -108,7 +134,7 @@ func (s *DefaultMetricSearcher) getOffsetStartAndFileIdx(filenames []string, beg
 	return
 }
 
-func (s *DefaultMetricSearcher) findOffsetToStart(filename string, beginTimeMs uint64, lastPos uint64) (int64, error) {
+func (s *LogSearcher[T]) findOffsetToStart(filename string, beginTimeMs uint64, lastPos uint64) (int64, error) {
 	s.cachedPos.idxFilename = ""
 	s.cachedPos.metricFilename = ""
 
@@ -119,7 +145,7 @@ func (s *DefaultMetricSearcher) findOffsetToStart(filename string, beginTimeMs u
 	beginSec := beginTimeMs / 1000
 	file, err := os.Open(idxFilename)
 	if err != nil {
-		return 0, errors.Wrap(err, "failed to open metric idx file: "+idxFilename)
+		return 0, errors.Wrap(err, "failed to open idx file: "+idxFilename)
 	}
 	defer file.Close()
 
@@ -169,7 +195,7 @@ func (s *DefaultMetricSearcher) findOffsetToStart(filename string, beginTimeMs u
 	return offset, nil
 }
 
-func (s *DefaultMetricSearcher) isPositionInTimeFor(beginTimeMs uint64) (bool, error) {
+func (s *LogSearcher[T]) isPositionInTimeFor(beginTimeMs uint64) (bool, error) {
 	if beginTimeMs/1000 < s.cachedPos.curSecInIdx {
 		return false, nil
 	}
@@ -195,22 +221,46 @@ func (s *DefaultMetricSearcher) isPositionInTimeFor(beginTimeMs uint64) (bool, e
 	return sec == s.cachedPos.curSecInIdx, nil
 }
 
-func NewDefaultMetricSearcher(baseDir, baseFilename string) (MetricSearcher, error) {
+// NewLogSearcher creates the LogSearcher which searches the log files named like "baseFilename.yyyy-MM-dd[.number]"
+// in the baseDir, the lines are parsed by the given parser.
+func NewLogSearcher[T any](baseDir, baseFilename string, parse LogItemParser[T]) (*LogSearcher[T], error) {
 	if baseDir == "" {
 		return nil, errors.New("empty base directory")
 	}
 	if baseFilename == "" {
 		return nil, errors.New("empty base filename pattern")
 	}
+	if parse == nil {
+		return nil, errors.New("nil item parser")
+	}
 	if baseDir[len(baseDir)-1] != os.PathSeparator {
 		baseDir = baseDir + string(os.PathSeparator)
 	}
-	reader := newDefaultMetricLogReader()
-	return &DefaultMetricSearcher{
+	return &LogSearcher[T]{
 		baseDir:      baseDir,
 		baseFilename: baseFilename,
-		reader:       reader,
+		reader:       newDefaultLogReader(parse),
 		cachedPos:    &filePosition{},
 		mux:          new(sync.Mutex),
 	}, nil
 }
+
+type DefaultMetricSearcher struct {
+	*LogSearcher[*base.MetricItem]
+}
+
+func NewDefaultMetricSearcher(baseDir, baseFilename string) (MetricSearcher, error) {
+	s, err := NewLogSearcher(baseDir, baseFilename, parseMetricItem)
+	if err != nil {
+		return nil, err
+	}
+	return &DefaultMetricSearcher{LogSearcher: s}, nil
+}
+
+func parseMetricItem(line string) (*base.MetricItem, uint64, string, error) {
+	item, err := base.MetricItemFromString(line)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	return item, item.Timestamp, item.Resource, nil
+}
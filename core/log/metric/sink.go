@@ -0,0 +1,93 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/config"
+	"github.com/Danceiny/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+// MetricLogWriterFunc is an adapter to allow the use of ordinary functions (e.g. callbacks) as MetricLogWriter.
+type MetricLogWriterFunc func(ts uint64, items []*base.MetricItem) error
+
+func (f MetricLogWriterFunc) Write(ts uint64, items []*base.MetricItem) error {
+	return f(ts, items)
+}
+
+// StreamMetricLogWriter writes the metric items into the io.Writer, one item per line.
+// The given items are not modified, as they are shared by all the registered writers.
+type StreamMetricLogWriter struct {
+	out    io.Writer
+	format string
+	mux    sync.Mutex
+}
+
+func (w *StreamMetricLogWriter) Write(ts uint64, items []*base.MetricItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	buf := bytes.Buffer{}
+	for _, item := range items {
+		stamped := *item
+		stamped.Timestamp = ts
+		s, err := formatMetricItem(&stamped, w.format)
+		if err != nil {
+			logging.Warn("[StreamMetricLogWriter] Failed to convert MetricItem to string", "resourceName", item.Resource, "err", err.Error())
+			continue
+		}
+		buf.WriteString(s)
+		buf.WriteByte('\n')
+	}
+
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	_, err := w.out.Write(buf.Bytes())
+	return err
+}
+
+// NewStreamMetricLogWriter creates the MetricLogWriter which writes into the given io.Writer
+// in the given format (config.MetricLogFormatFat or config.MetricLogFormatJSON).
+func NewStreamMetricLogWriter(out io.Writer, format string) (MetricLogWriter, error) {
+	if out == nil {
+		return nil, errors.New("nil io.Writer")
+	}
+	if format != config.MetricLogFormatFat && format != config.MetricLogFormatJSON {
+		return nil, errors.Errorf("unsupported metric log format: %s", format)
+	}
+	return &StreamMetricLogWriter{
+		out:    out,
+		format: format,
+	}, nil
+}
+
+// NewStdoutMetricLogWriter creates the MetricLogWriter which writes into the stdout in the given format.
+func NewStdoutMetricLogWriter(format string) (MetricLogWriter, error) {
+	return NewStreamMetricLogWriter(os.Stdout, format)
+}
+
+func formatMetricItem(item *base.MetricItem, format string) (string, error) {
+	if format == config.MetricLogFormatJSON {
+		return item.ToJSONString()
+	}
+	return item.ToFatString()
+}
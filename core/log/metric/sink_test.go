@@ -0,0 +1,80 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamMetricLogWriter(t *testing.T) {
+	_, err := NewStreamMetricLogWriter(nil, config.MetricLogFormatJSON)
+	assert.Error(t, err)
+	_, err = NewStreamMetricLogWriter(&bytes.Buffer{}, "xml")
+	assert.Error(t, err)
+
+	items := []*base.MetricItem{
+		{Resource: "abc", PassQps: 10, BlockQps: 2},
+		{Resource: "def", PassQps: 1, AvgRt: 5},
+	}
+	for _, format := range []string{config.MetricLogFormatFat, config.MetricLogFormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w, err := NewStreamMetricLogWriter(buf, format)
+			assert.NoError(t, err)
+			assert.NoError(t, w.Write(1564382218000, items))
+
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			assert.Equal(t, 2, len(lines))
+			for i, line := range lines {
+				item, err := base.MetricItemFromString(line)
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(1564382218000), item.Timestamp)
+				assert.Equal(t, items[i].Resource, item.Resource)
+				assert.Equal(t, items[i].PassQps, item.PassQps)
+				assert.Equal(t, items[i].BlockQps, item.BlockQps)
+				assert.Equal(t, items[i].AvgRt, item.AvgRt)
+				// the shared item is not modified
+				assert.Equal(t, uint64(0), items[i].Timestamp)
+			}
+		})
+	}
+}
+
+func TestRegisterMetricLogWriter(t *testing.T) {
+	assert.Error(t, RegisterMetricLogWriter(nil))
+
+	var written []*base.MetricItem
+	w := MetricLogWriterFunc(func(ts uint64, items []*base.MetricItem) error {
+		written = append(written, items...)
+		return nil
+	})
+	assert.NoError(t, RegisterMetricLogWriter(w))
+	defer func() {
+		writersMux.Lock()
+		extraWriters = make([]MetricLogWriter, 0)
+		writersMux.Unlock()
+	}()
+
+	// The registered sinks are placed after the default metric log writer (if initialized).
+	writers := metricLogWriters()
+	assert.NoError(t, writers[len(writers)-1].Write(1000, []*base.MetricItem{{Resource: "abc"}}))
+	assert.Equal(t, 1, len(written))
+}
@@ -31,7 +31,11 @@ import (
 	"github.com/pkg/errors"
 )
 
-type DefaultMetricLogWriter struct {
+// RollingLogWriter writes the lines of each second into the log files, along with the index files
+// recording the offset of each second in the log file (see MetricIdxSuffix). The log file is rolled
+// on a new day or when its size exceeds the max size, and only the latest files are kept.
+// It's shared by the metric log and other second-based logs (e.g. the block log), which differ in the file name.
+type RollingLogWriter struct {
 	baseDir      string
 	baseFilename string
 
@@ -40,6 +44,8 @@ type DefaultMetricLogWriter struct {
 
 	timezoneOffsetSec int64
 	latestOpSec       int64
+	// latestIdxSec is the latest second recorded in the current index file, 0 if none.
+	latestIdxSec int64
 
 	curMetricFile    *os.File
 	curMetricIdxFile *os.File
@@ -50,49 +56,49 @@ type DefaultMetricLogWriter struct {
 	mux *sync.RWMutex
 }
 
-func (d *DefaultMetricLogWriter) Write(ts uint64, items []*base.MetricItem) error {
-	if len(items) == 0 {
+// WriteLines writes the lines of the second of the given timestamp into the log file.
+// The lines earlier than the latest written second are ignored.
+func (d *RollingLogWriter) WriteLines(ts uint64, lines []string) error {
+	if len(lines) == 0 {
 		return nil
 	}
 	if ts <= 0 {
 		return errors.New(fmt.Sprintf("%s: %d", "Invalid timestamp: ", ts))
 	}
-	if d.curMetricFile == nil || d.curMetricIdxFile == nil {
-		return errors.New("file handle not initialized")
-	}
-	// Update all metric items to the given timestamp.
-	for _, item := range items {
-		item.Timestamp = ts
-	}
 
 	d.mux.Lock()
 	defer d.mux.Unlock()
 
+	if d.curMetricFile == nil || d.curMetricIdxFile == nil {
+		return errors.New("file handle not initialized")
+	}
 	timeSec := int64(ts / 1000)
 	if timeSec < d.latestOpSec {
 		// ignore
 		return nil
 	}
-	if timeSec > d.latestOpSec {
+	if timeSec > d.latestOpSec && d.isNewDay(d.latestOpSec, timeSec) {
+		if err := d.rollToNextFile(ts); err != nil {
+			return errors.Wrap(err, "failed to roll the log file")
+		}
+	}
+	// Record the offset of the first lines of the second in the index of current file.
+	if timeSec > d.latestIdxSec {
 		pos, err := util.FilePosition(d.curMetricFile)
 		if err != nil {
-			return errors.Wrap(err, "cannot get current pos of the metric file")
+			return errors.Wrap(err, "cannot get current pos of the log file")
 		}
 		if err = d.writeIndex(timeSec, pos); err != nil {
-			return errors.Wrap(err, "cannot write metric idx file")
-		}
-		if d.isNewDay(d.latestOpSec, timeSec) {
-			if err = d.rollToNextFile(ts); err != nil {
-				return errors.Wrap(err, "failed to roll the metric log")
-			}
+			return errors.Wrap(err, "cannot write idx file")
 		}
+		d.latestIdxSec = timeSec
 	}
 	// Write and flush
-	if err := d.writeItemsAndFlush(items); err != nil {
-		return errors.Wrap(err, "failed to write and flush metric items")
+	if err := d.writeLinesAndFlush(lines); err != nil {
+		return errors.Wrap(err, "failed to write and flush lines")
 	}
 	if err := d.rollFileIfSizeExceeded(ts); err != nil {
-		return errors.Wrap(err, "failed to pre-check the rolling condition of metric logs")
+		return errors.Wrap(err, "failed to pre-check the rolling condition of log files")
 	}
 	if timeSec > d.latestOpSec {
 		// Update the latest timeSec.
@@ -102,7 +108,7 @@ func (d *DefaultMetricLogWriter) Write(ts uint64, items []*base.MetricItem) erro
 	return nil
 }
 
-func (d *DefaultMetricLogWriter) Close() error {
+func (d *RollingLogWriter) Close() error {
 	d.mux.Lock()
 	defer d.mux.Unlock()
 
@@ -115,25 +121,17 @@ func (d *DefaultMetricLogWriter) Close() error {
 	return nil
 }
 
-func (d *DefaultMetricLogWriter) writeItemsAndFlush(items []*base.MetricItem) error {
-	for _, item := range items {
-		s, err := item.ToFatString()
-		if err != nil {
-			logging.Warn("[writeItemsAndFlush] Failed to convert MetricItem to string", "resourceName", item.Resource, "err", err.Error())
-			continue
-		}
-
+func (d *RollingLogWriter) writeLinesAndFlush(lines []string) error {
+	for _, line := range lines {
 		// Append the LF line separator.
-		bs := []byte(s + "\n")
-		_, err = d.metricOut.Write(bs)
-		if err != nil {
-			return nil
+		if _, err := d.metricOut.WriteString(line + "\n"); err != nil {
+			return err
 		}
 	}
 	return d.metricOut.Flush()
 }
 
-func (d *DefaultMetricLogWriter) rollFileIfSizeExceeded(time uint64) error {
+func (d *RollingLogWriter) rollFileIfSizeExceeded(time uint64) error {
 	if d.curMetricFile == nil {
 		return nil
 	}
@@ -147,7 +145,7 @@ func (d *DefaultMetricLogWriter) rollFileIfSizeExceeded(time uint64) error {
 	return nil
 }
 
-func (d *DefaultMetricLogWriter) rollToNextFile(time uint64) error {
+func (d *RollingLogWriter) rollToNextFile(time uint64) error {
 	newFilename, err := d.nextFileNameOfTime(time)
 	if err != nil {
 		return err
@@ -155,7 +153,7 @@ func (d *DefaultMetricLogWriter) rollToNextFile(time uint64) error {
 	return d.closeCurAndNewFile(newFilename)
 }
 
-func (d *DefaultMetricLogWriter) writeIndex(time, offset int64) error {
+func (d *RollingLogWriter) writeIndex(time, offset int64) error {
 	out := d.idxOut
 	if out == nil {
 		return errors.New("index buffered writer not ready")
@@ -173,7 +171,7 @@ func (d *DefaultMetricLogWriter) writeIndex(time, offset int64) error {
 	return out.Flush()
 }
 
-func (d *DefaultMetricLogWriter) removeDeprecatedFiles() error {
+func (d *RollingLogWriter) removeDeprecatedFiles() error {
 	files, err := listMetricFiles(d.baseDir, d.baseFilename)
 	if err != nil || len(files) == 0 {
 		return err
@@ -184,22 +182,25 @@ func (d *DefaultMetricLogWriter) removeDeprecatedFiles() error {
 		idxFilename := formMetricIdxFileName(filename)
 		err = os.Remove(filename)
 		if err != nil {
-			logging.Error(err, "Failed to remove metric log file in DefaultMetricLogWriter.removeDeprecatedFiles()", "filename", filename)
+			logging.Error(err, "Failed to remove log file in RollingLogWriter.removeDeprecatedFiles()", "filename", filename)
 		} else {
-			logging.Info("[MetricWriter] Metric log file removed in DefaultMetricLogWriter.removeDeprecatedFiles()", "filename", filename)
+			logging.Info("[RollingLogWriter] Log file removed in RollingLogWriter.removeDeprecatedFiles()", "filename", filename)
 		}
 
 		err = os.Remove(idxFilename)
-		if err != nil {
-			logging.Error(err, "Failed to remove metric log file in DefaultMetricLogWriter.removeDeprecatedFiles()", "idxFilename", idxFilename)
+		if os.IsNotExist(err) {
+			// The log file might be written without index (e.g. by the previous versions).
+			err = nil
+		} else if err != nil {
+			logging.Error(err, "Failed to remove index file in RollingLogWriter.removeDeprecatedFiles()", "idxFilename", idxFilename)
 		} else {
-			logging.Info("[MetricWriter] Metric index file removed", "idxFilename", idxFilename)
+			logging.Info("[RollingLogWriter] Index file removed", "idxFilename", idxFilename)
 		}
 	}
 	return err
 }
 
-func (d *DefaultMetricLogWriter) nextFileNameOfTime(time uint64) (string, error) {
+func (d *RollingLogWriter) nextFileNameOfTime(time uint64) (string, error) {
 	dateStr := util.FormatDate(time)
 	filePattern := d.baseFilename + "." + dateStr
 	list, err := listMetricFilesConditional(d.baseDir, filePattern, func(fn string, p string) bool {
@@ -223,7 +224,7 @@ func (d *DefaultMetricLogWriter) nextFileNameOfTime(time uint64) (string, error)
 	return filepath.Join(d.baseDir, fmt.Sprintf("%s.%d", filePattern, n+1)), nil
 }
 
-func (d *DefaultMetricLogWriter) closeCurAndNewFile(filename string) error {
+func (d *RollingLogWriter) closeCurAndNewFile(filename string) error {
 	err := d.removeDeprecatedFiles()
 	if err != nil {
 		return err
@@ -231,38 +232,39 @@ func (d *DefaultMetricLogWriter) closeCurAndNewFile(filename string) error {
 
 	if d.curMetricFile != nil {
 		if err = d.curMetricFile.Close(); err != nil {
-			logging.Error(err, "Failed to close metric log file in DefaultMetricLogWriter.closeCurAndNewFile()", "curMetricFile", d.curMetricFile.Name())
+			logging.Error(err, "Failed to close log file in RollingLogWriter.closeCurAndNewFile()", "curMetricFile", d.curMetricFile.Name())
 		}
 	}
 	if d.curMetricIdxFile != nil {
 		if err = d.curMetricIdxFile.Close(); err != nil {
-			logging.Error(err, "Failed to close metric index file in DefaultMetricLogWriter.closeCurAndNewFile()", "curMetricIdxFile", d.curMetricIdxFile.Name())
+			logging.Error(err, "Failed to close index file in RollingLogWriter.closeCurAndNewFile()", "curMetricIdxFile", d.curMetricIdxFile.Name())
 		}
 	}
-	// Create new metric log file, whether it exists or not.
+	// Create new log file, whether it exists or not.
 	mf, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
-	logging.Info("[MetricWriter] New metric log file created", "filename", filename)
+	logging.Info("[RollingLogWriter] New log file created", "filename", filename)
 
 	idxFile := formMetricIdxFileName(filename)
 	mif, err := os.Create(idxFile)
 	if err != nil {
 		return err
 	}
-	logging.Info("[MetricWriter] New metric log index file created", "idxFile", idxFile)
+	logging.Info("[RollingLogWriter] New log index file created", "idxFile", idxFile)
 
 	d.curMetricFile = mf
 	d.metricOut = bufio.NewWriter(mf)
 
 	d.curMetricIdxFile = mif
 	d.idxOut = bufio.NewWriter(mif)
+	d.latestIdxSec = 0
 
 	return nil
 }
 
-func (d *DefaultMetricLogWriter) initialize() error {
+func (d *RollingLogWriter) initialize() error {
 	// Create the dir if not exists.
 	err := util.CreateDirIfNotExists(d.baseDir)
 	if err != nil {
@@ -274,36 +276,27 @@ func (d *DefaultMetricLogWriter) initialize() error {
 	}
 	ts := util.CurrentTimeMillis()
 	if err := d.rollToNextFile(ts); err != nil {
-		return errors.Wrap(err, "failed to initialize metric log writer")
+		return errors.Wrap(err, "failed to initialize log writer")
 	}
 	d.latestOpSec = int64(ts / 1000)
 	return nil
 }
 
-func (d *DefaultMetricLogWriter) isNewDay(lastSec, sec int64) bool {
+func (d *RollingLogWriter) isNewDay(lastSec, sec int64) bool {
 	prevDayTs := (lastSec + d.timezoneOffsetSec) / 86400
 	newDayTs := (sec + d.timezoneOffsetSec) / 86400
 	return newDayTs > prevDayTs
 }
 
-func NewDefaultMetricLogWriter(maxSize uint64, maxFileAmount uint32) (MetricLogWriter, error) {
-	return NewDefaultMetricLogWriterOfApp(maxSize, maxFileAmount, config.AppName())
-}
-
-func NewDefaultMetricLogWriterOfApp(maxSize uint64, maxFileAmount uint32, appName string) (MetricLogWriter, error) {
+// NewRollingLogWriter creates the RollingLogWriter which writes into the files named
+// like "baseFilename.yyyy-MM-dd[.number]" in the baseDir.
+func NewRollingLogWriter(maxSize uint64, maxFileAmount uint32, baseDir, baseFilename string) (*RollingLogWriter, error) {
 	if maxSize == 0 || maxFileAmount == 0 {
 		return nil, errors.New("invalid maxSize or maxFileAmount")
 	}
 	_, offset := util.Now().Zone()
 
-	logDir := config.LogBaseDir()
-	if len(logDir) == 0 {
-		logDir = config.GetDefaultLogDir()
-	}
-	baseDir := logDir
-	baseFilename := FormMetricFileName(appName, config.LogUsePid())
-
-	writer := &DefaultMetricLogWriter{
+	writer := &RollingLogWriter{
 		maxSingleSize:     maxSize,
 		maxFileAmount:     maxFileAmount,
 		timezoneOffsetSec: int64(offset),
@@ -315,3 +308,47 @@ func NewDefaultMetricLogWriterOfApp(maxSize uint64, maxFileAmount uint32, appNam
 	err := writer.initialize()
 	return writer, err
 }
+
+type DefaultMetricLogWriter struct {
+	*RollingLogWriter
+	// format is the line format of the metric log, see config.MetricLogFormat.
+	format string
+}
+
+func (d *DefaultMetricLogWriter) Write(ts uint64, items []*base.MetricItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		// Format the copy of the item with the given timestamp, as the items may be shared by other writers.
+		stamped := *item
+		stamped.Timestamp = ts
+		s, err := formatMetricItem(&stamped, d.format)
+		if err != nil {
+			logging.Warn("[MetricWriter] Failed to convert MetricItem to string", "resourceName", item.Resource, "err", err.Error())
+			continue
+		}
+		lines = append(lines, s)
+	}
+	return d.WriteLines(ts, lines)
+}
+
+func NewDefaultMetricLogWriter(maxSize uint64, maxFileAmount uint32) (MetricLogWriter, error) {
+	return NewDefaultMetricLogWriterOfApp(maxSize, maxFileAmount, config.AppName())
+}
+
+func NewDefaultMetricLogWriterOfApp(maxSize uint64, maxFileAmount uint32, appName string) (MetricLogWriter, error) {
+	logDir := config.LogBaseDir()
+	if len(logDir) == 0 {
+		logDir = config.GetDefaultLogDir()
+	}
+	w, err := NewRollingLogWriter(maxSize, maxFileAmount, logDir, FormMetricFileName(appName, config.LogUsePid()))
+	if err != nil {
+		return nil, err
+	}
+	return &DefaultMetricLogWriter{
+		RollingLogWriter: w,
+		format:           config.MetricLogFormat(),
+	}, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/config"
+	"github.com/Danceiny/sentinel-golang/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollingLogWriterAndSearcher(t *testing.T) {
+	clock := util.NewMockClock()
+	util.SetClock(clock)
+	defer util.SetClock(util.NewRealClock())
+
+	dir := t.TempDir()
+	baseFilename := FormMetricFileName("writer-test", false)
+	rw, err := NewRollingLogWriter(1024*1024, 3, dir, baseFilename)
+	assert.NoError(t, err)
+	defer rw.Close()
+	w := &DefaultMetricLogWriter{RollingLogWriter: rw, format: config.MetricLogFormatFat}
+
+	beginMs := util.CurrentTimeMillis()
+	beginMs -= beginMs % 1000
+	// The items of the second when the writer is created should be indexed as well.
+	for i := uint64(0); i < 3; i++ {
+		err = w.Write(beginMs+i*1000, []*base.MetricItem{
+			{Resource: "abc", PassQps: i + 1},
+			{Resource: "def", PassQps: i + 1},
+		})
+		assert.NoError(t, err)
+	}
+	// The items are not modified by the writer.
+	shared := &base.MetricItem{Resource: "ghi", Timestamp: 1}
+	assert.NoError(t, w.Write(beginMs+2000, []*base.MetricItem{shared}))
+	assert.Equal(t, uint64(1), shared.Timestamp)
+	// Items earlier than the latest second are ignored.
+	assert.NoError(t, w.Write(beginMs, []*base.MetricItem{{Resource: "abc"}}))
+
+	searcher, err := NewDefaultMetricSearcher(dir, baseFilename)
+	assert.NoError(t, err)
+	items, err := searcher.FindByTimeAndResource(beginMs, beginMs+2000, "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(items))
+	for i, item := range items {
+		assert.Equal(t, beginMs+uint64(i)*1000, item.Timestamp)
+		assert.Equal(t, uint64(i+1), item.PassQps)
+	}
+
+	items, err = searcher.FindByTimeAndResource(beginMs+1000, beginMs+1000, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(items))
+	items, err = searcher.FindByTimeAndResource(beginMs+2000, beginMs+2000, "ghi")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(items))
+	assert.Equal(t, beginMs+2000, items[0].Timestamp)
+
+	items, err = searcher.FindFromTimeWithMaxLines(beginMs+1000, 1)
+	assert.NoError(t, err)
+	// The items of the same second are read completely.
+	assert.Equal(t, 2, len(items))
+
+	_, err = NewRollingLogWriter(0, 3, dir, baseFilename)
+	assert.Error(t, err)
+	_, err = NewLogSearcher[*base.MetricItem](dir, baseFilename, nil)
+	assert.Error(t, err)
+}
+
+func TestRollingLogWriterRemoveFilesWithoutIndex(t *testing.T) {
+	dir := t.TempDir()
+	baseFilename := FormMetricFileName("writer-no-idx-test", false)
+	for _, name := range []string{".2018-12-24", ".2018-12-25", ".2018-12-26"} {
+		f, err := os.Create(filepath.Join(dir, baseFilename+name))
+		assert.NoError(t, err)
+		_ = f.Close()
+	}
+	w, err := NewRollingLogWriter(1024*1024, 2, dir, baseFilename)
+	assert.NoError(t, err)
+	defer w.Close()
+
+	files, err := listMetricFiles(dir, baseFilename)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(files))
+}
+
+func TestRollingLogWriterNewDay(t *testing.T) {
+	clock := util.NewMockClock()
+	util.SetClock(clock)
+	defer util.SetClock(util.NewRealClock())
+
+	dir := t.TempDir()
+	baseFilename := FormMetricFileName("writer-day-test", false)
+	w, err := NewRollingLogWriter(1024*1024, 3, dir, baseFilename)
+	assert.NoError(t, err)
+	defer w.Close()
+
+	beginMs := util.CurrentTimeMillis()
+	beginMs -= beginMs % 1000
+	assert.NoError(t, w.WriteLines(beginMs, []string{"line1"}))
+	clock.Sleep(24 * time.Hour)
+	nextDayMs := beginMs + uint64(24*time.Hour/time.Millisecond)
+	assert.NoError(t, w.WriteLines(nextDayMs, []string{"line2"}))
+
+	files, err := listMetricFiles(dir, baseFilename)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(files))
+	// The first second of the new day is indexed in the file of the new day.
+	searcher, err := NewLogSearcher(dir, baseFilename, func(line string) (string, uint64, string, error) {
+		if line == "line1" {
+			return line, beginMs, "", nil
+		}
+		return line, nextDayMs, "", nil
+	})
+	assert.NoError(t, err)
+	lines, err := searcher.FindByTimeAndResource(nextDayMs, nextDayMs, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"line2"}, lines)
+}
@@ -16,6 +16,7 @@ package log
 
 import (
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/log/block"
 )
 
 const (
@@ -38,7 +39,7 @@ func (s *Slot) OnEntryPassed(_ *base.EntryContext) {
 }
 
 func (s *Slot) OnEntryBlocked(ctx *base.EntryContext, blockError *base.BlockError) {
-	// TODO: write sentinel-block.log here
+	block.Record(ctx, blockError)
 }
 
 func (s *Slot) OnCompleted(_ *base.EntryContext) {
@@ -40,13 +40,17 @@ func (s *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
 	if len(resource) == 0 {
 		return result
 	}
+	// no-op for the resource without outlier ejection rule
+	rule := getOutlierRuleOfResource(resource)
+	if rule == nil {
+		return result
+	}
 
-	filterNodes, outlierNodes, halfOpenNodes := checkAllNodes(ctx)
+	filterNodes, outlierNodes, halfOpenNodes := checkAllNodes(ctx, rule)
 	result.SetFilterNodes(filterNodes)
 	result.SetHalfOpenNodes(halfOpenNodes)
 
 	if len(outlierNodes) != 0 {
-		rule := getOutlierRuleOfResource(resource)
 		if rule.EnableActiveRecovery && len(retryerCh) < capacity {
 			retryerCh <- task{outlierNodes, resource}
 		}
@@ -57,10 +61,8 @@ func (s *Slot) Check(ctx *base.EntryContext) *base.TokenResult {
 	return result
 }
 
-func checkAllNodes(ctx *base.EntryContext) (filters []string, outliers []string, halfs []string) {
-	resource := ctx.Resource.Name()
-	nodeBreaks := getNodeBreakersOfResource(resource)
-	rule := getOutlierRuleOfResource(resource)
+func checkAllNodes(ctx *base.EntryContext, rule *Rule) (filters []string, outliers []string, halfs []string) {
+	nodeBreaks := getNodeBreakersOfResource(ctx.Resource.Name())
 	nodeCount := len(nodeBreaks)
 	for address, breaker := range nodeBreaks {
 		if breaker.TryPass(ctx) {
@@ -0,0 +1,66 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package outlier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/circuitbreaker"
+)
+
+func newEntryContext(resource string) *base.EntryContext {
+	ctx := base.NewEmptyEntryContext()
+	ctx.Resource = base.NewResourceWrapper(resource, base.ResTypeRPC, base.Outbound)
+	ctx.RuleCheckResult = base.NewTokenResultPass()
+	ctx.SetPair("address", "node0")
+	return ctx
+}
+
+func TestSlotWithoutRule(t *testing.T) {
+	ctx := newEntryContext("example.norule")
+	result := DefaultSlot.Check(ctx)
+	assert.True(t, result.IsPass())
+	assert.Empty(t, result.FilterNodes())
+	assert.Empty(t, result.HalfOpenNodes())
+
+	DefaultMetricStatSlot.OnCompleted(ctx)
+	assert.Empty(t, getNodeBreakersOfResource("example.norule"))
+}
+
+func TestSlotWithRule(t *testing.T) {
+	r1 := &Rule{
+		Rule: &circuitbreaker.Rule{
+			Resource:         "example.helloworld",
+			Strategy:         circuitbreaker.ErrorCount,
+			RetryTimeoutMs:   3000,
+			MinRequestAmount: 1,
+			StatIntervalMs:   1000,
+			Threshold:        1.0,
+		},
+		MaxEjectionPercent: 1.0,
+	}
+	_, _ = LoadRules([]*Rule{r1})
+	defer clearData()
+
+	ctx := newEntryContext(r1.Resource)
+	DefaultMetricStatSlot.OnCompleted(ctx)
+	assert.Len(t, getNodeBreakersOfResource(r1.Resource), 1)
+	result := DefaultSlot.Check(newEntryContext(r1.Resource))
+	assert.True(t, result.IsPass())
+	assert.Empty(t, result.FilterNodes())
+}
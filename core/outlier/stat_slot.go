@@ -48,6 +48,10 @@ func (c *MetricStatSlot) OnEntryBlocked(_ *base.EntryContext, _ *base.BlockError
 
 func (c *MetricStatSlot) OnCompleted(ctx *base.EntryContext) {
 	res := ctx.Resource.Name()
+	// no-op for the resource without outlier ejection rule
+	if getOutlierRuleOfResource(res) == nil {
+		return
+	}
 	err := ctx.Err()
 	nodeBreakers := getNodeBreakersOfResource(res)
 	if address, ok := ctx.GetPair("address").(string); !ok || address == "" {
@@ -16,8 +16,10 @@ package base
 
 import (
 	"sync/atomic"
+	"unsafe"
 
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/config"
 	"github.com/Danceiny/sentinel-golang/logging"
 	"github.com/pkg/errors"
 )
@@ -26,9 +28,18 @@ import (
 // Note that all operations of the MetricBucket are required to be thread-safe.
 type MetricBucket struct {
 	// Value of statistic
-	counter        [base.MetricEventTotal]int64
-	minRt          int64
+	counter [base.MetricEventTotal]int64
+	// customCounter points to the [base.MaxCustomMetricEventAmount]int64 counter of the custom MetricEvents,
+	// which is allocated on the first record of the custom MetricEvents.
+	customCounter unsafe.Pointer
+	minRt         int64
+	maxRt         int64
+	// slowCount is the count of the requests whose RT exceeds config.SlowRtThresholdMs.
+	slowCount      int64
 	maxConcurrency int32
+	// rtHistogram points to the RtHistogram which records the RT distribution. It is allocated on the first record
+	// of RT while the RT percentile statistic is enabled, and released on reset once the statistic is disabled.
+	rtHistogram unsafe.Pointer
 }
 
 func NewMetricBucket() *MetricBucket {
@@ -41,10 +52,16 @@ func NewMetricBucket() *MetricBucket {
 
 // Add statistic count for the given metric event.
 func (mb *MetricBucket) Add(event base.MetricEvent, count int64) {
-	if event >= base.MetricEventTotal || event < 0 {
+	if event < 0 {
 		logging.Error(errors.Errorf("Unknown metric event: %v", event), "")
 		return
 	}
+	if event.IsCustom() {
+		if c := mb.customCounterOf(event, true); c != nil {
+			atomic.AddInt64(c, count)
+		}
+		return
+	}
 	if event == base.MetricEventRt {
 		mb.AddRt(count)
 		return
@@ -58,33 +75,111 @@ func (mb *MetricBucket) addCount(event base.MetricEvent, count int64) {
 
 // Get current statistic count of the given metric event.
 func (mb *MetricBucket) Get(event base.MetricEvent) int64 {
-	if event >= base.MetricEventTotal || event < 0 {
+	if event < 0 {
 		logging.Error(errors.Errorf("Unknown metric event: %v", event), "")
 		return 0
 	}
+	if event.IsCustom() {
+		if c := mb.customCounterOf(event, false); c != nil {
+			return atomic.LoadInt64(c)
+		}
+		return 0
+	}
 	return atomic.LoadInt64(&mb.counter[event])
 }
 
+// customCounterOf returns the counter of the custom event, nil if the event is out of range
+// or the counters are not allocated and alloc is false.
+func (mb *MetricBucket) customCounterOf(event base.MetricEvent, alloc bool) *int64 {
+	idx := int(event - base.MetricEventTotal)
+	if idx >= base.MaxCustomMetricEventAmount {
+		logging.Error(errors.Errorf("Unknown metric event: %v", event), "")
+		return nil
+	}
+	counter := (*[base.MaxCustomMetricEventAmount]int64)(atomic.LoadPointer(&mb.customCounter))
+	if counter == nil {
+		if !alloc {
+			return nil
+		}
+		// The counters might have been allocated by another goroutine, so load again after the CAS.
+		atomic.CompareAndSwapPointer(&mb.customCounter, nil, unsafe.Pointer(new([base.MaxCustomMetricEventAmount]int64)))
+		counter = (*[base.MaxCustomMetricEventAmount]int64)(atomic.LoadPointer(&mb.customCounter))
+	}
+	return &counter[idx]
+}
+
 func (mb *MetricBucket) reset() {
 	for i := 0; i < int(base.MetricEventTotal); i++ {
 		atomic.StoreInt64(&mb.counter[i], 0)
 	}
+	if counter := (*[base.MaxCustomMetricEventAmount]int64)(atomic.LoadPointer(&mb.customCounter)); counter != nil {
+		for i := range counter {
+			atomic.StoreInt64(&counter[i], 0)
+		}
+	}
 	atomic.StoreInt64(&mb.minRt, base.DefaultStatisticMaxRt)
+	atomic.StoreInt64(&mb.maxRt, 0)
+	atomic.StoreInt64(&mb.slowCount, 0)
 	atomic.StoreInt32(&mb.maxConcurrency, int32(0))
+	if !config.RtPercentileEnabled() {
+		atomic.StorePointer(&mb.rtHistogram, nil)
+	} else if h := mb.RtHistogram(); h != nil {
+		h.reset()
+	}
 }
 
 func (mb *MetricBucket) AddRt(rt int64) {
 	mb.addCount(base.MetricEventRt, rt)
-	if rt < atomic.LoadInt64(&mb.minRt) {
-		// Might not be accurate here.
-		atomic.StoreInt64(&mb.minRt, rt)
+	for {
+		minRt := atomic.LoadInt64(&mb.minRt)
+		if rt >= minRt || atomic.CompareAndSwapInt64(&mb.minRt, minRt, rt) {
+			break
+		}
 	}
+	for {
+		maxRt := atomic.LoadInt64(&mb.maxRt)
+		if rt <= maxRt || atomic.CompareAndSwapInt64(&mb.maxRt, maxRt, rt) {
+			break
+		}
+	}
+	if threshold := config.SlowRtThresholdMs(); threshold > 0 && rt > int64(threshold) {
+		atomic.AddInt64(&mb.slowCount, 1)
+	}
+	if config.RtPercentileEnabled() {
+		mb.rtHistogramOf(true).Record(rt)
+	}
+}
+
+// RtHistogram returns the RT distribution of the bucket, nil if no RT is recorded
+// while the RT percentile statistic is enabled (see config.RtPercentileEnabled).
+func (mb *MetricBucket) RtHistogram() *RtHistogram {
+	return mb.rtHistogramOf(false)
+}
+
+// rtHistogramOf returns the RtHistogram of the bucket, nil if it is not allocated and alloc is false.
+func (mb *MetricBucket) rtHistogramOf(alloc bool) *RtHistogram {
+	h := (*RtHistogram)(atomic.LoadPointer(&mb.rtHistogram))
+	if h == nil && alloc {
+		// The histogram might have been allocated by another goroutine, so load again after the CAS.
+		atomic.CompareAndSwapPointer(&mb.rtHistogram, nil, unsafe.Pointer(NewRtHistogram()))
+		h = (*RtHistogram)(atomic.LoadPointer(&mb.rtHistogram))
+	}
+	return h
 }
 
 func (mb *MetricBucket) MinRt() int64 {
 	return atomic.LoadInt64(&mb.minRt)
 }
 
+func (mb *MetricBucket) MaxRt() int64 {
+	return atomic.LoadInt64(&mb.maxRt)
+}
+
+// SlowCount returns the count of the requests whose RT exceeds config.SlowRtThresholdMs.
+func (mb *MetricBucket) SlowCount() int64 {
+	return atomic.LoadInt64(&mb.slowCount)
+}
+
 func (mb *MetricBucket) UpdateConcurrency(concurrency int32) {
 	cc := concurrency
 	if cc > atomic.LoadInt32(&mb.maxConcurrency) {
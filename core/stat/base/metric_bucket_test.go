@@ -20,6 +20,7 @@ import (
 	"unsafe"
 
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/config"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,7 +28,9 @@ func Test_metricBucket_MemSize(t *testing.T) {
 	mb := NewMetricBucket()
 	t.Log("mb:", mb)
 	size := unsafe.Sizeof(*mb)
-	if size != 56 {
+	// counter (5 * 8) + customCounter pointer (8) + minRt (8) + maxRt (8) + slowCount (8)
+	// + maxConcurrency (4, padded to 8) + rtHistogram pointer (8)
+	if size != 88 {
 		t.Error("unexpect memory size of MetricBucket")
 	}
 }
@@ -142,3 +145,91 @@ func Test_Reset(t *testing.T) {
 	assert.True(t, rt == base.DefaultStatisticMaxRt)
 	assert.True(t, mc == int32(0))
 }
+
+func Test_metricBucket_RtDistribution(t *testing.T) {
+	conf := config.NewDefaultConfig()
+	conf.Sentinel.Stat.SlowRtThresholdMs = 100
+	config.ResetGlobalConfig(conf)
+	defer config.ResetGlobalConfig(config.NewDefaultConfig())
+
+	mb := NewMetricBucket()
+	assert.Equal(t, int64(0), mb.MaxRt())
+	mb.Add(base.MetricEventRt, 50)
+	mb.Add(base.MetricEventRt, 300)
+	mb.Add(base.MetricEventRt, 100)
+	mb.Add(base.MetricEventRt, 10)
+	assert.Equal(t, int64(10), mb.MinRt())
+	assert.Equal(t, int64(300), mb.MaxRt())
+	// Only the RT exceeding the threshold is slow.
+	assert.Equal(t, int64(1), mb.SlowCount())
+
+	mb.reset()
+	assert.Equal(t, int64(0), mb.MaxRt())
+	assert.Equal(t, int64(0), mb.SlowCount())
+
+	// The threshold takes effect on the existing buckets once it is updated.
+	config.ResetGlobalConfig(config.NewDefaultConfig())
+	mb.Add(base.MetricEventRt, 300)
+	assert.Equal(t, int64(0), mb.SlowCount())
+}
+
+func Test_metricBucket_ConcurrentMinMaxRt(t *testing.T) {
+	mb := NewMetricBucket()
+	wg := &sync.WaitGroup{}
+	for i := 1; i <= 1000; i++ {
+		wg.Add(1)
+		go func(rt int64) {
+			defer wg.Done()
+			mb.AddRt(rt)
+		}(int64(i))
+	}
+	wg.Wait()
+	assert.Equal(t, int64(1), mb.MinRt())
+	assert.Equal(t, int64(1000), mb.MaxRt())
+}
+
+func Test_metricBucket_CustomEvent(t *testing.T) {
+	cacheMiss, err := base.RegisterMetricEvent("bucket_test_cache_miss")
+	assert.NoError(t, err)
+
+	mb := NewMetricBucket()
+	mb.Add(cacheMiss, 2)
+	mb.Add(cacheMiss, 3)
+	mb.Add(base.MetricEventPass, 1)
+	assert.Equal(t, int64(5), mb.Get(cacheMiss))
+	assert.Equal(t, int64(1), mb.Get(base.MetricEventPass))
+
+	// The event registered after the bucket creation is also recorded.
+	retry, err := base.RegisterMetricEvent("bucket_test_retry")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), mb.Get(retry))
+	mb.Add(retry, 1)
+	assert.Equal(t, int64(1), mb.Get(retry))
+	assert.Equal(t, int64(0), NewMetricBucket().Get(retry))
+
+	mb.reset()
+	assert.Equal(t, int64(0), mb.Get(cacheMiss))
+}
+
+func Test_metricBucket_RtHistogram(t *testing.T) {
+	mb := NewMetricBucket()
+	mb.AddRt(10)
+	assert.Nil(t, mb.RtHistogram())
+
+	// The histogram is allocated once the RT percentile statistic is enabled.
+	conf := config.NewDefaultConfig()
+	conf.Sentinel.Stat.RtPercentileEnabled = true
+	config.ResetGlobalConfig(conf)
+	defer config.ResetGlobalConfig(config.NewDefaultConfig())
+	mb.AddRt(10)
+	assert.NotNil(t, mb.RtHistogram())
+	assert.Equal(t, 10.0, mb.RtHistogram().Percentile(1))
+	mb.reset()
+	assert.NotNil(t, mb.RtHistogram())
+	assert.Equal(t, 0.0, mb.RtHistogram().Percentile(1))
+
+	// The histogram is released on reset once the RT percentile statistic is disabled.
+	config.ResetGlobalConfig(config.NewDefaultConfig())
+	mb.reset()
+	assert.Nil(t, mb.RtHistogram())
+}
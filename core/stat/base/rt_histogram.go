@@ -0,0 +1,134 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package base
+
+import (
+	"math"
+	"math/bits"
+	"sync/atomic"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+)
+
+const (
+	rtHistogramSubBucketBits  = 4
+	rtHistogramSubBucketCount = 1 << rtHistogramSubBucketBits
+)
+
+// The amount of the buckets of RtHistogram, the RT exceeding base.DefaultStatisticMaxRt
+// is recorded as base.DefaultStatisticMaxRt.
+var rtHistogramBucketAmount = rtHistogramIndexOf(base.DefaultStatisticMaxRt) + 1
+
+// RtHistogram records the distribution of RT (in ms) in HDR-style log-linear buckets:
+// the RT less than 16 is recorded precisely, otherwise each power of two range is divided
+// into 16 sub-buckets, so that the relative error is less than 1/16.
+// RtHistogram is thread-safe and mergeable.
+type RtHistogram struct {
+	counts []int64
+}
+
+func NewRtHistogram() *RtHistogram {
+	return &RtHistogram{
+		counts: make([]int64, rtHistogramBucketAmount),
+	}
+}
+
+// Record records a single RT.
+func (h *RtHistogram) Record(rt int64) {
+	if rt < 0 {
+		rt = 0
+	}
+	if rt > base.DefaultStatisticMaxRt {
+		rt = base.DefaultStatisticMaxRt
+	}
+	atomic.AddInt64(&h.counts[rtHistogramIndexOf(rt)], 1)
+}
+
+// Count returns the amount of the recorded RTs.
+func (h *RtHistogram) Count() int64 {
+	total := int64(0)
+	for i := range h.counts {
+		total += atomic.LoadInt64(&h.counts[i])
+	}
+	return total
+}
+
+// Percentile returns the RT at the quantile q (in (0, 1]), 0 if nothing is recorded.
+func (h *RtHistogram) Percentile(q float64) float64 {
+	counts := make([]int64, rtHistogramBucketAmount)
+	h.addTo(counts)
+	return percentileOfCounts(counts, q)
+}
+
+func (h *RtHistogram) reset() {
+	for i := range h.counts {
+		atomic.StoreInt64(&h.counts[i], 0)
+	}
+}
+
+// addTo merges the bucket counts of the histogram into the given counts.
+func (h *RtHistogram) addTo(counts []int64) {
+	for i := range h.counts {
+		counts[i] += atomic.LoadInt64(&h.counts[i])
+	}
+}
+
+// percentileOfCounts returns the highest RT equivalent to the bucket which the quantile q falls in.
+func percentileOfCounts(counts []int64, q float64) float64 {
+	if q <= 0 || q > 1 {
+		return 0
+	}
+	total := int64(0)
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(q * float64(total)))
+	if rank < 1 {
+		rank = 1
+	}
+	cum := int64(0)
+	for i, c := range counts {
+		cum += c
+		if cum >= rank {
+			return float64(rtHistogramHighestValueOf(i))
+		}
+	}
+	return float64(base.DefaultStatisticMaxRt)
+}
+
+func rtHistogramIndexOf(rt int64) int {
+	if rt < rtHistogramSubBucketCount {
+		return int(rt)
+	}
+	// Make (rt >> shift) in [16, 32).
+	shift := bits.Len64(uint64(rt)) - (rtHistogramSubBucketBits + 1)
+	return (shift+1)*rtHistogramSubBucketCount + int(rt>>uint(shift)) - rtHistogramSubBucketCount
+}
+
+func rtHistogramHighestValueOf(idx int) int64 {
+	if idx < rtHistogramSubBucketCount {
+		return int64(idx)
+	}
+	shift := idx/rtHistogramSubBucketCount - 1
+	lowest := int64(idx%rtHistogramSubBucketCount+rtHistogramSubBucketCount) << uint(shift)
+	highest := lowest + int64(1)<<uint(shift) - 1
+	if highest > base.DefaultStatisticMaxRt {
+		return base.DefaultStatisticMaxRt
+	}
+	return highest
+}
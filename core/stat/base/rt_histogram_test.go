@@ -0,0 +1,68 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package base
+
+import (
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRtHistogramIndex(t *testing.T) {
+	assert.Equal(t, 0, rtHistogramIndexOf(0))
+	assert.Equal(t, 15, rtHistogramIndexOf(15))
+	assert.Equal(t, 16, rtHistogramIndexOf(16))
+	assert.Equal(t, 31, rtHistogramIndexOf(31))
+	assert.Equal(t, 32, rtHistogramIndexOf(32))
+	assert.Equal(t, 32, rtHistogramIndexOf(33))
+	assert.Equal(t, 33, rtHistogramIndexOf(34))
+
+	// The indexes are contiguous and the highest value of each bucket is consistent.
+	prev := 0
+	for rt := int64(0); rt <= base.DefaultStatisticMaxRt; rt++ {
+		idx := rtHistogramIndexOf(rt)
+		assert.True(t, idx == prev || idx == prev+1)
+		assert.True(t, rtHistogramHighestValueOf(idx) >= rt)
+		// The relative error is less than 1/16.
+		assert.True(t, float64(rtHistogramHighestValueOf(idx)-rt) <= float64(rt)/16)
+		prev = idx
+	}
+	assert.Equal(t, rtHistogramBucketAmount, prev+1)
+}
+
+func TestRtHistogram(t *testing.T) {
+	h := NewRtHistogram()
+	assert.Equal(t, 0.0, h.Percentile(0.5))
+
+	for i := int64(0); i < 90; i++ {
+		h.Record(5)
+	}
+	for i := int64(0); i < 9; i++ {
+		h.Record(100)
+	}
+	h.Record(base.DefaultStatisticMaxRt * 2)
+	assert.Equal(t, int64(100), h.Count())
+
+	assert.Equal(t, 5.0, h.Percentile(0.5))
+	assert.Equal(t, 5.0, h.Percentile(0.9))
+	assert.Equal(t, 103.0, h.Percentile(0.95))
+	assert.Equal(t, float64(base.DefaultStatisticMaxRt), h.Percentile(1))
+	assert.Equal(t, 0.0, h.Percentile(0))
+	assert.Equal(t, 0.0, h.Percentile(1.5))
+
+	h.reset()
+	assert.Equal(t, int64(0), h.Count())
+}
@@ -188,6 +188,30 @@ func (m *SlidingWindowMetric) MaxConcurrency() int32 {
 	return maxConcurrency
 }
 
+// Percentile returns the RT at the quantile q (in (0, 1]) of the sliding window,
+// 0 if the RT percentile statistic is disabled or there is no completed request.
+func (m *SlidingWindowMetric) Percentile(q float64) float64 {
+	now := util.CurrentTimeMillis()
+	satisfiedBuckets := m.getSatisfiedBuckets(now)
+	counts := make([]int64, rtHistogramBucketAmount)
+	for _, w := range satisfiedBuckets {
+		mb := w.Value.Load()
+		if mb == nil {
+			logging.Error(errors.New("nil BucketWrap"), "Current bucket value is nil in SlidingWindowMetric.Percentile()")
+			continue
+		}
+		counter, ok := mb.(*MetricBucket)
+		if !ok {
+			logging.Error(errors.New("type assert failed"), "Fail to do type assert in SlidingWindowMetric.Percentile()", "expectType", "*MetricBucket", "actualType", reflect.TypeOf(mb).Name())
+			continue
+		}
+		if h := counter.RtHistogram(); h != nil {
+			h.addTo(counts)
+		}
+	}
+	return percentileOfCounts(counts, q)
+}
+
 func (m *SlidingWindowMetric) AvgRT() float64 {
 	return float64(m.GetSum(base.MetricEventRt)) / float64(m.GetSum(base.MetricEventComplete))
 }
@@ -225,6 +249,8 @@ func (m *SlidingWindowMetric) SecondMetricsOnCondition(predicate base.TimePredic
 func (m *SlidingWindowMetric) metricItemFromBuckets(ts uint64, ws []*BucketWrap) *base.MetricItem {
 	item := &base.MetricItem{Timestamp: ts}
 	var allRt int64 = 0
+	var rtCounts []int64
+	hasCompleted := false
 	for _, w := range ws {
 		mi := w.Value.Load()
 		if mi == nil {
@@ -245,12 +271,33 @@ func (m *SlidingWindowMetric) metricItemFromBuckets(ts uint64, ws []*BucketWrap)
 			item.Concurrency = mc
 		}
 		allRt += mb.Get(base.MetricEventRt)
+		item.SlowQps += uint64(mb.SlowCount())
+		if mb.Get(base.MetricEventComplete) > 0 {
+			if minRt := uint64(mb.MinRt()); !hasCompleted || minRt < item.MinRt {
+				item.MinRt = minRt
+			}
+			hasCompleted = true
+			if maxRt := uint64(mb.MaxRt()); maxRt > item.MaxRt {
+				item.MaxRt = maxRt
+			}
+		}
+		if h := mb.RtHistogram(); h != nil {
+			if rtCounts == nil {
+				rtCounts = make([]int64, rtHistogramBucketAmount)
+			}
+			h.addTo(rtCounts)
+		}
 	}
 	if item.CompleteQps > 0 {
 		item.AvgRt = uint64(allRt) / item.CompleteQps
 	} else {
 		item.AvgRt = uint64(allRt)
 	}
+	if rtCounts != nil {
+		item.P50Rt = uint64(percentileOfCounts(rtCounts, 0.5))
+		item.P90Rt = uint64(percentileOfCounts(rtCounts, 0.9))
+		item.P99Rt = uint64(percentileOfCounts(rtCounts, 0.99))
+	}
 	return item
 }
 
@@ -271,9 +318,12 @@ func (m *SlidingWindowMetric) metricItemFromBucket(w *BucketWrap) *base.MetricIt
 		BlockQps:    uint64(mb.Get(base.MetricEventBlock)),
 		ErrorQps:    uint64(mb.Get(base.MetricEventError)),
 		CompleteQps: uint64(completeQps),
+		SlowQps:     uint64(mb.SlowCount()),
 		Timestamp:   w.BucketStart,
 	}
 	if completeQps > 0 {
+		item.MinRt = uint64(mb.MinRt())
+		item.MaxRt = uint64(mb.MaxRt())
 		item.AvgRt = uint64(mb.Get(base.MetricEventRt) / completeQps)
 	} else {
 		item.AvgRt = uint64(mb.Get(base.MetricEventRt))
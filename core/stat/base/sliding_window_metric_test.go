@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/config"
 	"github.com/Danceiny/sentinel-golang/util"
 	"github.com/stretchr/testify/assert"
 )
@@ -244,6 +245,34 @@ func TestAvgRT(t *testing.T) {
 	assert.True(t, util.Float64Equals(avgRT, 1.0))
 }
 
+func TestPercentile(t *testing.T) {
+	got, err := NewSlidingWindowMetric(4, 2000, NewBucketLeapArray(SampleCount, IntervalInMs))
+	assert.True(t, err == nil && got != nil)
+	got.real.AddCount(base.MetricEventRt, 10)
+	// The RT distribution is not recorded while the RT percentile statistic is disabled.
+	assert.True(t, util.Float64Equals(got.Percentile(0.99), 0.0))
+
+	// The statistic takes effect on the existing buckets once it is enabled.
+	conf := config.NewDefaultConfig()
+	conf.Sentinel.Stat.RtPercentileEnabled = true
+	config.ResetGlobalConfig(conf)
+	defer config.ResetGlobalConfig(config.NewDefaultConfig())
+	for i := int64(1); i <= 100; i++ {
+		got.real.AddCount(base.MetricEventRt, i)
+	}
+	assert.True(t, util.Float64Equals(got.Percentile(0.1), 10.0))
+	// The sub-bucket of 50 is [50, 51], and the one of 99 is [96, 99].
+	assert.True(t, util.Float64Equals(got.Percentile(0.5), 51.0))
+	assert.True(t, util.Float64Equals(got.Percentile(0.99), 99.0))
+
+	got.real.AddCount(base.MetricEventComplete, 100)
+	item := got.metricItemFromBuckets(util.CurrentTimeMillis(), got.real.data.array.data)
+	assert.Equal(t, uint64(1), item.MinRt)
+	assert.Equal(t, uint64(100), item.MaxRt)
+	assert.Equal(t, uint64(51), item.P50Rt)
+	assert.Equal(t, uint64(99), item.P99Rt)
+}
+
 func TestMetricItemFromBuckets(t *testing.T) {
 	got, err := NewSlidingWindowMetric(4, 2000, NewBucketLeapArray(SampleCount, IntervalInMs))
 	assert.True(t, err == nil && got != nil)
@@ -84,6 +84,12 @@ func (n *BaseStatNode) MinRT() float64 {
 	return float64(n.metric.MinRT())
 }
 
+// Percentile returns the RT at the quantile q (in (0, 1]) of the default metric statistic,
+// 0 if the RT percentile statistic is disabled (see config.RtPercentileEnabled).
+func (n *BaseStatNode) Percentile(q float64) float64 {
+	return n.metric.Percentile(q)
+}
+
 func (n *BaseStatNode) MaxConcurrency() int32 {
 	return n.metric.MaxConcurrency()
 }
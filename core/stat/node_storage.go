@@ -15,12 +15,20 @@
 package stat
 
 import (
+	"strconv"
 	"sync"
+	"sync/atomic"
 
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/config"
+	metric_exporter "github.com/Danceiny/sentinel-golang/exporter/metric"
 	"github.com/Danceiny/sentinel-golang/logging"
+	"github.com/Danceiny/sentinel-golang/util"
 )
 
+// overflowLogIntervalMs is the min interval of the warning logs of the overflow resources.
+const overflowLogIntervalMs = 60000
+
 type ResourceNodeMap map[string]*ResourceNode
 
 var (
@@ -28,8 +36,20 @@ var (
 
 	resNodeMap = make(ResourceNodeMap)
 	rnsMux     = new(sync.RWMutex)
+
+	overflowNode      = NewResourceNode(base.OverflowResourceName, base.ResTypeCommon)
+	lastOverflowLogMs uint64
+
+	overflowCounter = metric_exporter.NewCounter(
+		"resource_overflow_total",
+		"Total count of the entries whose resources are collapsed into the overflow resource",
+		[]string{"resource_type"})
 )
 
+func init() {
+	metric_exporter.Register(overflowCounter)
+}
+
 // InboundNode returns the global inbound statistic node.
 func InboundNode() *ResourceNode {
 	return inboundNode
@@ -54,11 +74,32 @@ func GetResourceNode(resource string) *ResourceNode {
 	return resNodeMap[resource]
 }
 
+// GetOrCreateResourceNode returns the statistic node of the resource, which is created if absent.
+// Once the amount of the resources reaches config.MaxResourceAmount, the statistics of the new resources
+// are collapsed into the node of base.OverflowResourceName under the "collapse" policy.
 func GetOrCreateResourceNode(resource string, resourceType base.ResourceType) *ResourceNode {
-	node := GetResourceNode(resource)
+	return getOrCreateResourceNode(resource, resourceType, true)
+}
+
+// GetOrCreateRuleResourceNode returns the statistic node of the resource which has rules, which is created
+// if absent and never collapsed into the overflow node, so that the rules check the statistics of the resource itself.
+func GetOrCreateRuleResourceNode(resource string, resourceType base.ResourceType) *ResourceNode {
+	return getOrCreateResourceNode(resource, resourceType, false)
+}
+
+func getOrCreateResourceNode(resource string, resourceType base.ResourceType, collapsible bool) *ResourceNode {
+	rnsMux.RLock()
+	node := resNodeMap[resource]
+	overflowed := node == nil && isOverflowed()
+	overflowTracked := resNodeMap[base.OverflowResourceName] != nil
+	rnsMux.RUnlock()
 	if node != nil {
 		return node
 	}
+	if collapsible && overflowed && config.ResourceOverflowPolicy() == config.ResourceOverflowPolicyCollapse {
+		return collapseOverflowResource(resource, resourceType, overflowTracked)
+	}
+
 	rnsMux.Lock()
 	defer rnsMux.Unlock()
 
@@ -67,14 +108,44 @@ func GetOrCreateResourceNode(resource string, resourceType base.ResourceType) *R
 		return node
 	}
 
-	if len(resNodeMap) >= int(base.DefaultMaxResourceAmount) {
-		logging.Warn("[GetOrCreateResourceNode] Resource amount exceeds the threshold", "maxResourceAmount", base.DefaultMaxResourceAmount)
+	if isOverflowed() {
+		logging.Warn("[GetOrCreateResourceNode] Resource amount exceeds the threshold", "maxResourceAmount", config.MaxResourceAmount())
 	}
 	node = NewResourceNode(resource, resourceType)
 	resNodeMap[resource] = node
 	return node
 }
 
+// isOverflowed indicates whether the amount of the resources reaches the threshold, rnsMux must be held.
+func isOverflowed() bool {
+	amount := len(resNodeMap)
+	if resNodeMap[base.OverflowResourceName] != nil {
+		amount--
+	}
+	return amount >= int(config.MaxResourceAmount())
+}
+
+// collapseOverflowResource returns the overflow node for the resource, the overflow node is tracked
+// in the resource node map once used, so that its statistics are exported as a normal resource.
+func collapseOverflowResource(resource string, resourceType base.ResourceType, tracked bool) *ResourceNode {
+	if !tracked {
+		rnsMux.Lock()
+		if resNodeMap[base.OverflowResourceName] == nil {
+			resNodeMap[base.OverflowResourceName] = overflowNode
+		}
+		rnsMux.Unlock()
+	}
+	overflowCounter.Add(1, strconv.Itoa(int(resourceType)))
+
+	now := util.CurrentTimeMillis()
+	last := atomic.LoadUint64(&lastOverflowLogMs)
+	if now-last >= overflowLogIntervalMs && atomic.CompareAndSwapUint64(&lastOverflowLogMs, last, now) {
+		logging.Warn("[GetOrCreateResourceNode] Resource amount exceeds the threshold, the statistics of the new resources are collapsed into the overflow resource",
+			"maxResourceAmount", config.MaxResourceAmount(), "resource", resource, "overflowResource", base.OverflowResourceName)
+	}
+	return overflowNode
+}
+
 func ResetResourceNodeMap() {
 	rnsMux.Lock()
 	defer rnsMux.Unlock()
@@ -0,0 +1,85 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/config"
+)
+
+func withStatConfig(t *testing.T, maxResourceAmount uint32, policy string) {
+	entity := config.NewDefaultConfig()
+	entity.Sentinel.Stat.MaxResourceAmount = maxResourceAmount
+	entity.Sentinel.Stat.ResourceOverflowPolicy = policy
+	config.ResetGlobalConfig(entity)
+	ResetResourceNodeMap()
+	t.Cleanup(func() {
+		config.ResetGlobalConfig(config.NewDefaultConfig())
+		ResetResourceNodeMap()
+	})
+}
+
+func TestGetOrCreateResourceNode_Collapse(t *testing.T) {
+	withStatConfig(t, 2, config.ResourceOverflowPolicyCollapse)
+
+	n1 := GetOrCreateResourceNode("res1", base.ResTypeWeb)
+	n2 := GetOrCreateResourceNode("res2", base.ResTypeWeb)
+	assert.Equal(t, "res1", n1.ResourceName())
+	assert.Equal(t, "res2", n2.ResourceName())
+
+	n3 := GetOrCreateResourceNode("res3", base.ResTypeWeb)
+	n4 := GetOrCreateResourceNode("res4", base.ResTypeWeb)
+	assert.Equal(t, base.OverflowResourceName, n3.ResourceName())
+	assert.Same(t, n3, n4)
+	assert.Nil(t, GetResourceNode("res3"))
+	assert.Same(t, n3, GetResourceNode(base.OverflowResourceName))
+	// the existing resources are not affected
+	assert.Same(t, n1, GetOrCreateResourceNode("res1", base.ResTypeWeb))
+	assert.Len(t, ResourceNodeList(), 3)
+}
+
+func TestGetOrCreateResourceNode_Warn(t *testing.T) {
+	withStatConfig(t, 2, config.ResourceOverflowPolicyWarn)
+
+	for _, res := range []string{"res1", "res2", "res3"} {
+		assert.Equal(t, res, GetOrCreateResourceNode(res, base.ResTypeWeb).ResourceName())
+	}
+	assert.Nil(t, GetResourceNode(base.OverflowResourceName))
+	assert.Len(t, ResourceNodeList(), 3)
+}
+
+func TestGetOrCreateRuleResourceNode(t *testing.T) {
+	withStatConfig(t, 1, config.ResourceOverflowPolicyCollapse)
+
+	GetOrCreateResourceNode("res1", base.ResTypeWeb)
+	assert.Equal(t, base.OverflowResourceName, GetOrCreateResourceNode("res2", base.ResTypeWeb).ResourceName())
+
+	// the resource with rules is never collapsed
+	n2 := GetOrCreateRuleResourceNode("res2", base.ResTypeCommon)
+	assert.Equal(t, "res2", n2.ResourceName())
+	assert.Same(t, n2, GetOrCreateResourceNode("res2", base.ResTypeWeb))
+}
+
+func TestDefaultResourceOverflowPolicy(t *testing.T) {
+	withStatConfig(t, 1, "")
+
+	GetOrCreateResourceNode("res1", base.ResTypeWeb)
+	assert.Equal(t, config.ResourceOverflowPolicyWarn, config.ResourceOverflowPolicy())
+	assert.Equal(t, "res2", GetOrCreateResourceNode("res2", base.ResTypeWeb).ResourceName())
+}
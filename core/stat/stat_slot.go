@@ -15,7 +15,10 @@
 package stat
 
 import (
+	"sync"
+
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/config"
 	metric_exporter "github.com/Danceiny/sentinel-golang/exporter/metric"
 	"github.com/Danceiny/sentinel-golang/util"
 )
@@ -33,10 +36,36 @@ var (
 		"handled_total",
 		"Total handled count",
 		[]string{"resource", "result", "block_type"})
+	concurrencyGauge = metric_exporter.NewGauge(
+		"resource_concurrency",
+		"Resource current concurrency",
+		[]string{"resource"})
+
+	rtHistogram     metric_exporter.Histogram
+	rtHistogramOnce sync.Once
 )
 
 func init() {
 	metric_exporter.Register(handledCounter)
+	metric_exporter.Register(concurrencyGauge)
+}
+
+// resourceRtHistogram creates the RT histogram lazily,
+// so that the buckets could be configured by config.MetricExportRtBucketsMs.
+func resourceRtHistogram() metric_exporter.Histogram {
+	rtHistogramOnce.Do(func() {
+		buckets := config.MetricExportRtBucketsMs()
+		if len(buckets) == 0 {
+			buckets = config.DefaultMetricExportRtBucketsMs
+		}
+		rtHistogram = metric_exporter.NewHistogram(
+			"resource_rt_ms",
+			"Resource response time in milliseconds",
+			buckets,
+			[]string{"resource"})
+		metric_exporter.Register(rtHistogram)
+	})
+	return rtHistogram
 }
 
 type Slot struct {
@@ -53,6 +82,9 @@ func (s *Slot) OnEntryPassed(ctx *base.EntryContext) {
 	}
 
 	handledCounter.Add(float64(ctx.Input.BatchCount), ctx.Resource.Name(), ResultPass, "")
+	if ctx.StatNode != nil {
+		concurrencyGauge.Set(float64(ctx.StatNode.CurrentConcurrency()), ctx.Resource.Name())
+	}
 }
 
 func (s *Slot) OnEntryBlocked(ctx *base.EntryContext, blockError *base.BlockError) {
@@ -65,12 +97,35 @@ func (s *Slot) OnEntryBlocked(ctx *base.EntryContext, blockError *base.BlockErro
 }
 
 func (s *Slot) OnCompleted(ctx *base.EntryContext) {
+	if entry := ctx.Entry(); entry != nil && !entry.MarkCompleted() {
+		// the statistic has been released by the leak detector
+		return
+	}
 	rt := util.CurrentTimeMillis() - ctx.StartTime()
 	ctx.PutRt(rt)
 	s.recordCompleteFor(ctx.StatNode, ctx.Input.BatchCount, rt, ctx.Err())
 	if ctx.Resource.FlowType() == base.Inbound {
 		s.recordCompleteFor(InboundNode(), ctx.Input.BatchCount, rt, ctx.Err())
 	}
+
+	resourceRtHistogram().Observe(float64(rt), ctx.Resource.Name())
+	if ctx.StatNode != nil {
+		concurrencyGauge.Set(float64(ctx.StatNode.CurrentConcurrency()), ctx.Resource.Name())
+	}
+}
+
+// ReleaseLeaked records the leaked entry as completed and releases its concurrency,
+// which is called by the leak detector instead of the Exit of the entry.
+// The arguments are captured when the entry passed, as the context is owned by the entry.
+func ReleaseLeaked(resource *base.ResourceWrapper, sn base.StatNode, batchCount uint32, startTime uint64) {
+	rt := util.CurrentTimeMillis() - startTime
+	DefaultSlot.recordCompleteFor(sn, batchCount, rt, nil)
+	if resource.FlowType() == base.Inbound {
+		DefaultSlot.recordCompleteFor(InboundNode(), batchCount, rt, nil)
+	}
+	if sn != nil {
+		concurrencyGauge.Set(float64(sn.CurrentConcurrency()), resource.Name())
+	}
 }
 
 func (s *Slot) recordPassFor(sn base.StatNode, count uint32) {
@@ -0,0 +1,55 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stat
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	metric_exporter "github.com/Danceiny/sentinel-golang/exporter/metric"
+)
+
+func exportedMetrics(t *testing.T) string {
+	rec := httptest.NewRecorder()
+	metric_exporter.HTTPHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
+
+func TestSlotExportMetrics(t *testing.T) {
+	assert.NoError(t, metric_exporter.SetExporter(metric_exporter.PrometheusExporterName))
+	defer func() {
+		assert.NoError(t, metric_exporter.SetExporter(metric_exporter.EmptyExporterName))
+	}()
+
+	ctx := base.NewEmptyEntryContext()
+	ctx.Resource = base.NewResourceWrapper("test-stat-slot", base.ResTypeCommon, base.Outbound)
+	ctx.StatNode = GetOrCreateResourceNode("test-stat-slot", base.ResTypeCommon)
+	ctx.Input = &base.SentinelInput{BatchCount: 1}
+
+	DefaultSlot.OnEntryPassed(ctx)
+	metrics := exportedMetrics(t)
+	assert.Regexp(t, `sentinel_go_resource_concurrency\{[^}]*resource="test-stat-slot"\} 1\n`, metrics)
+	assert.Regexp(t, `sentinel_go_handled_total\{[^}]*block_type="",[^}]*resource="test-stat-slot",result="pass"\} 1\n`, metrics)
+
+	DefaultSlot.OnCompleted(ctx)
+	metrics = exportedMetrics(t)
+	assert.Regexp(t, `sentinel_go_resource_concurrency\{[^}]*resource="test-stat-slot"\} 0\n`, metrics)
+	assert.Regexp(t, `sentinel_go_resource_rt_ms_count\{[^}]*resource="test-stat-slot"\} 1\n`, metrics)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/Danceiny/sentinel-golang/core/base"
 	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/Danceiny/sentinel-golang/core/system_metric"
+	metric_exporter "github.com/Danceiny/sentinel-golang/exporter/metric"
 )
 
 const (
@@ -26,8 +27,17 @@ const (
 
 var (
 	DefaultAdaptiveSlot = &AdaptiveSlot{}
+
+	triggeredCounter = metric_exporter.NewCounter(
+		"system_rule_triggered_total",
+		"Total count of the requests blocked by system rules",
+		[]string{"metric_type"})
 )
 
+func init() {
+	metric_exporter.Register(triggeredCounter)
+}
+
 type AdaptiveSlot struct {
 }
 
@@ -51,6 +61,7 @@ func (s *AdaptiveSlot) Check(ctx *base.EntryContext) *base.TokenResult {
 		} else {
 			result.ResetToBlockedWithCause(base.BlockTypeSystemFlow, msg, rule, snapshotValue)
 		}
+		triggeredCounter.Add(float64(ctx.Input.BatchCount), rule.MetricType.String())
 		return result
 	}
 	return result
@@ -15,11 +15,14 @@
 package system
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/Danceiny/sentinel-golang/core/base"
 	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/Danceiny/sentinel-golang/core/system_metric"
+	metric_exporter "github.com/Danceiny/sentinel-golang/exporter/metric"
 	"github.com/Danceiny/sentinel-golang/util"
 	"github.com/stretchr/testify/assert"
 )
@@ -127,3 +130,28 @@ func TestDoCheckRuleDefault(t *testing.T) {
 	assert.Equal(t, true, isOK)
 	assert.True(t, util.Float64Equals(float64(0.0), v))
 }
+
+func TestCheckTriggeredCounter(t *testing.T) {
+	assert.NoError(t, metric_exporter.SetExporter(metric_exporter.PrometheusExporterName))
+	defer func() {
+		assert.NoError(t, metric_exporter.SetExporter(metric_exporter.EmptyExporterName))
+	}()
+
+	_, err := LoadRules([]*Rule{{MetricType: Concurrency, TriggerCount: 0.5}})
+	assert.NoError(t, err)
+	defer ClearRules()
+
+	stat.InboundNode().IncreaseConcurrency()
+	defer stat.InboundNode().DecreaseConcurrency()
+
+	var sas *AdaptiveSlot
+	r := sas.Check(&base.EntryContext{
+		Resource: base.NewResourceWrapper("test", base.ResTypeCommon, base.Inbound),
+		Input:    &base.SentinelInput{BatchCount: 2},
+	})
+	assert.True(t, r != nil && r.IsBlocked())
+
+	rec := httptest.NewRecorder()
+	metric_exporter.HTTPHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Regexp(t, `sentinel_go_system_rule_triggered_total\{[^}]*metric_type="concurrency",[^}]*\} 2\n`, rec.Body.String())
+}
@@ -0,0 +1,129 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/Danceiny/sentinel-golang/core/config"
+	"github.com/Danceiny/sentinel-golang/logging"
+)
+
+// OverflowLabelValue replaces all the label values of the label value sets
+// which exceed the cardinality limit of the metric (see config.MetricExportMaxLabelCardinality).
+const OverflowLabelValue = "__overflow__"
+
+// cardinalityLimiter limits the amount of distinct label value sets of a metric.
+type cardinalityLimiter struct {
+	name string
+	seen map[string]struct{}
+	// overflowed indicates whether the overflow has been reported.
+	overflowed bool
+	mux        sync.RWMutex
+}
+
+func newCardinalityLimiter(name string) *cardinalityLimiter {
+	return &cardinalityLimiter{
+		name: name,
+		seen: make(map[string]struct{}),
+	}
+}
+
+// limit returns the label values to use: the given label values if the limit is not exceeded,
+// otherwise the overflow label values.
+func (l *cardinalityLimiter) limit(labelValues []string) []string {
+	max := int(config.MetricExportMaxLabelCardinality())
+	if max <= 0 || len(labelValues) == 0 {
+		return labelValues
+	}
+	key := strings.Join(labelValues, "\x00")
+
+	l.mux.RLock()
+	_, exists := l.seen[key]
+	l.mux.RUnlock()
+	if exists {
+		return labelValues
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if _, exists = l.seen[key]; exists {
+		return labelValues
+	}
+	if len(l.seen) >= max {
+		if !l.overflowed {
+			l.overflowed = true
+			logging.Warn("[MetricExporter] The label cardinality of metric exceeds the limit, the exceeded label values are aggregated into the overflow values",
+				"metric", l.name, "limit", max)
+		}
+		overflow := make([]string, len(labelValues))
+		for i := range overflow {
+			overflow[i] = OverflowLabelValue
+		}
+		return overflow
+	}
+	l.seen[key] = struct{}{}
+	return labelValues
+}
+
+func (l *cardinalityLimiter) reset() {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.seen = make(map[string]struct{})
+	l.overflowed = false
+}
+
+type limitedCounter struct {
+	Counter
+	limiter *cardinalityLimiter
+}
+
+func (c *limitedCounter) Add(value float64, labelValues ...string) {
+	c.Counter.Add(value, c.limiter.limit(labelValues)...)
+}
+
+func (c *limitedCounter) Reset() {
+	c.Counter.Reset()
+	c.limiter.reset()
+}
+
+type limitedGauge struct {
+	Gauge
+	limiter *cardinalityLimiter
+}
+
+func (g *limitedGauge) Set(value float64, labelValues ...string) {
+	g.Gauge.Set(value, g.limiter.limit(labelValues)...)
+}
+
+func (g *limitedGauge) Reset() {
+	g.Gauge.Reset()
+	g.limiter.reset()
+}
+
+type limitedHistogram struct {
+	Histogram
+	limiter *cardinalityLimiter
+}
+
+func (h *limitedHistogram) Observe(value float64, labelValues ...string) {
+	h.Histogram.Observe(value, h.limiter.limit(labelValues)...)
+}
+
+func (h *limitedHistogram) Reset() {
+	h.Histogram.Reset()
+	h.limiter.reset()
+}
@@ -0,0 +1,115 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func setMaxLabelCardinality(t *testing.T, max uint32) {
+	entity := config.NewDefaultConfig()
+	entity.Sentinel.Exporter.Metric.MaxLabelCardinality = max
+	config.ResetGlobalConfig(entity)
+	t.Cleanup(func() {
+		config.ResetGlobalConfig(config.NewDefaultConfig())
+	})
+}
+
+func TestCardinalityLimiter(t *testing.T) {
+	t.Run("Unlimited", func(t *testing.T) {
+		setMaxLabelCardinality(t, 0)
+		l := newCardinalityLimiter("test")
+		for _, res := range []string{"a", "b", "c"} {
+			assert.Equal(t, []string{res, "x"}, l.limit([]string{res, "x"}))
+		}
+		assert.Empty(t, l.seen)
+	})
+
+	t.Run("NoLabels", func(t *testing.T) {
+		setMaxLabelCardinality(t, 1)
+		l := newCardinalityLimiter("test")
+		assert.Empty(t, l.limit(nil))
+		assert.Empty(t, l.seen)
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		setMaxLabelCardinality(t, 2)
+		l := newCardinalityLimiter("test")
+		assert.Equal(t, []string{"a", "x"}, l.limit([]string{"a", "x"}))
+		assert.Equal(t, []string{"b", "x"}, l.limit([]string{"b", "x"}))
+		assert.Equal(t, []string{OverflowLabelValue, OverflowLabelValue}, l.limit([]string{"c", "x"}))
+		assert.True(t, l.overflowed)
+		// The label values seen before are still exported.
+		assert.Equal(t, []string{"a", "x"}, l.limit([]string{"a", "x"}))
+		assert.Len(t, l.seen, 2)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		setMaxLabelCardinality(t, 1)
+		l := newCardinalityLimiter("test")
+		assert.Equal(t, []string{"a"}, l.limit([]string{"a"}))
+		assert.Equal(t, []string{OverflowLabelValue}, l.limit([]string{"b"}))
+
+		l.reset()
+		assert.Empty(t, l.seen)
+		assert.False(t, l.overflowed)
+		assert.Equal(t, []string{"b"}, l.limit([]string{"b"}))
+	})
+}
+
+func TestLimitedMetrics(t *testing.T) {
+	setMaxLabelCardinality(t, 1)
+
+	e := newMemoryExporter()
+	assert.NoError(t, RegisterExporter("memory-limited", e))
+	assert.NoError(t, SetExporter("memory-limited"))
+	defer func() {
+		assert.NoError(t, SetExporter(EmptyExporterName))
+	}()
+
+	counter := NewCounter("test_limited_counter", "", []string{"resource"})
+	gauge := NewGauge("test_limited_gauge", "", []string{"resource"})
+	histogram := NewHistogram("test_limited_histogram", "", []float64{1, 10}, []string{"resource"})
+
+	counter.Add(1, "a")
+	counter.Add(2, "b")
+	counter.Add(3, "c")
+	assert.Equal(t, float64(1), e.values["test_limited_counter|a"])
+	assert.Equal(t, float64(5), e.values["test_limited_counter|"+OverflowLabelValue])
+
+	gauge.Set(1, "a")
+	gauge.Set(2, "b")
+	assert.Equal(t, float64(1), e.values["test_limited_gauge|a"])
+	assert.Equal(t, float64(2), e.values["test_limited_gauge|"+OverflowLabelValue])
+
+	histogram.Observe(1, "a")
+	histogram.Observe(2, "b")
+	assert.Equal(t, float64(1), e.values["test_limited_histogram|a"])
+	assert.Equal(t, float64(2), e.values["test_limited_histogram|"+OverflowLabelValue])
+
+	// The label values are admitted again after the metrics are reset.
+	counter.Reset()
+	gauge.Reset()
+	histogram.Reset()
+	counter.Add(4, "b")
+	gauge.Set(4, "b")
+	histogram.Observe(4, "b")
+	assert.Equal(t, float64(4), e.values["test_limited_counter|b"])
+	assert.Equal(t, float64(4), e.values["test_limited_gauge|b"])
+	assert.Equal(t, float64(4), e.values["test_limited_histogram|b"])
+}
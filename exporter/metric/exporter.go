@@ -21,11 +21,10 @@ import (
 
 	"github.com/Danceiny/sentinel-golang/core/config"
 	"github.com/Danceiny/sentinel-golang/exporter/metric/prometheus"
+	"github.com/Danceiny/sentinel-golang/logging"
 )
 
 var (
-	exporter Exporter
-
 	host      string
 	app       string
 	pid       string
@@ -33,12 +32,6 @@ var (
 )
 
 func init() {
-	if config.MetricExportHTTPAddr() != "" {
-		exporter = newPrometheusExporter()
-	} else {
-		exporter = newEmptyExporter()
-	}
-
 	host, _ = os.Hostname()
 	if host == "" {
 		host = "unknown"
@@ -46,6 +39,10 @@ func init() {
 	app = config.AppName()
 	pid = strconv.Itoa(os.Getpid())
 	namespace = "sentinel_go"
+
+	if err := InitExporter(); err != nil {
+		logging.Error(err, "Failed to initialize the metric exporter in metric.init()")
+	}
 }
 
 // Metric models basic operations of metric being exported.
@@ -121,22 +118,46 @@ func newConstLabels() map[string]string {
 }
 
 // NewCounter creates a Counter metric partitioned by the given label names.
+// The metric is bound to the current exporter and re-bound when the exporter changes (see SetExporter).
+// The label cardinality of the metric is limited by config.MetricExportMaxLabelCardinality.
 func NewCounter(name, desc string, labelNames []string) Counter {
-	return exporter.NewCounter(name, desc, labelNames)
+	return &limitedCounter{
+		Counter: &boundCounter{newBinding(func(e Exporter) Metric {
+			return e.NewCounter(name, desc, labelNames)
+		})},
+		limiter: newCardinalityLimiter(name),
+	}
 }
 
 // NewGauge creates a Gauge metric partitioned by the given label names.
+// The metric is bound to the current exporter and re-bound when the exporter changes (see SetExporter).
+// The label cardinality of the metric is limited by config.MetricExportMaxLabelCardinality.
 func NewGauge(name, desc string, labelNames []string) Gauge {
-	return exporter.NewGauge(name, desc, labelNames)
+	return &limitedGauge{
+		Gauge: &boundGauge{newBinding(func(e Exporter) Metric {
+			return e.NewGauge(name, desc, labelNames)
+		})},
+		limiter: newCardinalityLimiter(name),
+	}
 }
 
 // NewHistogram creates a histogram metric partitioned by the given label names.
+// The metric is bound to the current exporter and re-bound when the exporter changes (see SetExporter).
+// The label cardinality of the metric is limited by config.MetricExportMaxLabelCardinality.
 func NewHistogram(name, desc string, buckets []float64, labelNames []string) Histogram {
-	return exporter.NewHistogram(name, desc, buckets, labelNames)
+	return &limitedHistogram{
+		Histogram: &boundHistogram{newBinding(func(e Exporter) Metric {
+			return e.NewHistogram(name, desc, buckets, labelNames)
+		})},
+		limiter: newCardinalityLimiter(name),
+	}
 }
 
 // HTTPHandler returns http.Handler used to export metrics.
 func HTTPHandler() http.Handler {
+	exporterMux.RLock()
+	defer exporterMux.RUnlock()
+
 	return exporter.HTTPHandler()
 }
 
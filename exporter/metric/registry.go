@@ -0,0 +1,207 @@
+// Copyright 1999-2021 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/Danceiny/sentinel-golang/core/config"
+	"github.com/Danceiny/sentinel-golang/logging"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PrometheusExporterName is the name of the built-in prometheus exporter.
+	PrometheusExporterName = "prometheus"
+	// EmptyExporterName is the name of the built-in exporter which exports nothing.
+	EmptyExporterName = "empty"
+	// StatsDExporterName is the name of the built-in StatsD exporter, which is created
+	// from the StatsD exporter config when it's selected.
+	StatsDExporterName = "statsd"
+)
+
+var (
+	exporters = map[string]Exporter{
+		PrometheusExporterName: newPrometheusExporter(),
+		EmptyExporterName:      newEmptyExporter(),
+	}
+	// exporter is the exporter which all metrics are bound to currently.
+	exporter     Exporter = exporters[EmptyExporterName]
+	exporterName          = EmptyExporterName
+	// bindings holds all the metrics created by NewCounter, NewGauge and NewHistogram,
+	// so that they could be re-bound to the exporter when the exporter changes.
+	bindings    = make([]*binding, 0)
+	exporterMux = new(sync.RWMutex)
+)
+
+// RegisterExporter registers the custom Exporter with the given name, so that it could be
+// selected by the metric exporter name in config (see config.MetricExporterName).
+// The built-in exporters ("prometheus", "empty" and "statsd") could not be overridden.
+func RegisterExporter(name string, e Exporter) error {
+	if name == "" {
+		return errors.New("empty exporter name")
+	}
+	if e == nil {
+		return errors.New("nil exporter")
+	}
+	if name == PrometheusExporterName || name == EmptyExporterName || name == StatsDExporterName {
+		return errors.Errorf("the built-in exporter %s could not be overridden", name)
+	}
+
+	exporterMux.Lock()
+	defer exporterMux.Unlock()
+
+	exporters[name] = e
+	return nil
+}
+
+// InitExporter binds all metrics to the exporter configured in the global config.
+// If the exporter name is not configured, the prometheus exporter is selected
+// when the metric export http address is configured, otherwise the empty exporter is selected.
+func InitExporter() error {
+	name := config.MetricExporterName()
+	if name == "" {
+		if config.MetricExportHTTPAddr() != "" {
+			name = PrometheusExporterName
+		} else {
+			name = EmptyExporterName
+		}
+	}
+	return SetExporter(name)
+}
+
+// SetExporter binds all metrics, including the ones created before, to the exporter of given name.
+// The metrics registered before are unregistered from the previous exporter and registered to the new one.
+func SetExporter(name string) error {
+	exporterMux.Lock()
+	defer exporterMux.Unlock()
+
+	if name == exporterName {
+		return nil
+	}
+	e, ok := exporters[name]
+	if !ok && name == StatsDExporterName {
+		se, err := newStatsDExporter()
+		if err != nil {
+			return errors.Wrap(err, "failed to create the StatsD exporter")
+		}
+		exporters[name], e, ok = se, se, true
+	}
+	if !ok {
+		return errors.Errorf("unknown metric exporter: %s", name)
+	}
+	// The const labels of the metrics are determined by the current config.
+	app = config.AppName()
+	for _, b := range bindings {
+		b.bind(e)
+	}
+	exporter = e
+	exporterName = name
+	logging.Info("[MetricExporter] Metric exporter bound", "exporter", name, "metricCount", len(bindings))
+	return nil
+}
+
+func newBinding(create func(e Exporter) Metric) *binding {
+	exporterMux.Lock()
+	defer exporterMux.Unlock()
+
+	b := &binding{create: create}
+	b.bind(exporter)
+	bindings = append(bindings, b)
+	return b
+}
+
+// metricHolder wraps the Metric so that metrics of different types could be stored in the same atomic.Value.
+type metricHolder struct {
+	m Metric
+}
+
+// binding delegates to the Metric created by the current exporter.
+type binding struct {
+	create func(e Exporter) Metric
+	// delegate holds the metricHolder of the Metric created by the current exporter.
+	delegate   atomic.Value
+	registered bool
+	mux        sync.Mutex
+}
+
+func (b *binding) bind(e Exporter) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	m := b.create(e)
+	if b.registered {
+		if old := b.current(); old != nil {
+			old.Unregister()
+		}
+		if err := m.Register(); err != nil {
+			logging.Error(err, "Failed to register metric to the new exporter in binding.bind()")
+		}
+	}
+	b.delegate.Store(metricHolder{m: m})
+}
+
+func (b *binding) current() Metric {
+	h, ok := b.delegate.Load().(metricHolder)
+	if !ok {
+		return nil
+	}
+	return h.m
+}
+
+func (b *binding) Register() error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.registered = true
+	return b.current().Register()
+}
+
+func (b *binding) Unregister() bool {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.registered = false
+	return b.current().Unregister()
+}
+
+func (b *binding) Reset() {
+	b.current().Reset()
+}
+
+type boundCounter struct {
+	*binding
+}
+
+func (c *boundCounter) Add(value float64, labelValues ...string) {
+	c.current().(Counter).Add(value, labelValues...)
+}
+
+type boundGauge struct {
+	*binding
+}
+
+func (g *boundGauge) Set(value float64, labelValues ...string) {
+	g.current().(Gauge).Set(value, labelValues...)
+}
+
+type boundHistogram struct {
+	*binding
+}
+
+func (h *boundHistogram) Observe(value float64, labelValues ...string) {
+	h.current().(Histogram).Observe(value, labelValues...)
+}
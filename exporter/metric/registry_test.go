@@ -0,0 +1,132 @@
+// Copyright 1999-2021 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryMetric struct {
+	exporter *memoryExporter
+	name     string
+}
+
+func (m *memoryMetric) Register() error {
+	m.exporter.mux.Lock()
+	defer m.exporter.mux.Unlock()
+	m.exporter.registered[m.name] = true
+	return nil
+}
+
+func (m *memoryMetric) Unregister() bool {
+	m.exporter.mux.Lock()
+	defer m.exporter.mux.Unlock()
+	delete(m.exporter.registered, m.name)
+	return true
+}
+
+func (m *memoryMetric) Reset() {}
+
+func (m *memoryMetric) record(value float64, labelValues []string) {
+	m.exporter.mux.Lock()
+	defer m.exporter.mux.Unlock()
+	m.exporter.values[m.name+"|"+strings.Join(labelValues, ",")] += value
+}
+
+func (m *memoryMetric) Add(value float64, labelValues ...string) {
+	m.record(value, labelValues)
+}
+
+func (m *memoryMetric) Set(value float64, labelValues ...string) {
+	m.record(value, labelValues)
+}
+
+func (m *memoryMetric) Observe(value float64, labelValues ...string) {
+	m.record(value, labelValues)
+}
+
+type memoryExporter struct {
+	values     map[string]float64
+	registered map[string]bool
+	mux        sync.Mutex
+}
+
+func newMemoryExporter() *memoryExporter {
+	return &memoryExporter{
+		values:     make(map[string]float64),
+		registered: make(map[string]bool),
+	}
+}
+
+func (e *memoryExporter) NewCounter(name, desc string, labelNames []string) Counter {
+	return &memoryMetric{exporter: e, name: name}
+}
+
+func (e *memoryExporter) NewGauge(name, desc string, labelNames []string) Gauge {
+	return &memoryMetric{exporter: e, name: name}
+}
+
+func (e *memoryExporter) NewHistogram(name, desc string, buckets []float64, labelNames []string) Histogram {
+	return &memoryMetric{exporter: e, name: name}
+}
+
+func (e *memoryExporter) HTTPHandler() http.Handler {
+	return http.NotFoundHandler()
+}
+
+func TestRegisterExporter(t *testing.T) {
+	assert.Error(t, RegisterExporter("", newMemoryExporter()))
+	assert.Error(t, RegisterExporter("memory", nil))
+	assert.Error(t, RegisterExporter(PrometheusExporterName, newMemoryExporter()))
+	assert.Error(t, RegisterExporter(EmptyExporterName, newMemoryExporter()))
+	assert.Error(t, SetExporter("not-exist"))
+}
+
+func TestSetExporter(t *testing.T) {
+	// The metrics created before the exporter is set.
+	counter := NewCounter("test_registry_counter", "", []string{"resource"})
+	gauge := NewGauge("test_registry_gauge", "", []string{"resource"})
+	histogram := NewHistogram("test_registry_histogram", "", []float64{1, 10}, []string{"resource"})
+	assert.NoError(t, Register(counter))
+	counter.Add(1, "abc")
+
+	e := newMemoryExporter()
+	assert.NoError(t, RegisterExporter("memory", e))
+	assert.NoError(t, SetExporter("memory"))
+	defer func() {
+		assert.NoError(t, SetExporter(EmptyExporterName))
+		assert.False(t, e.registered["test_registry_counter"])
+	}()
+
+	assert.True(t, e.registered["test_registry_counter"])
+	assert.False(t, e.registered["test_registry_gauge"])
+
+	counter.Add(2, "abc")
+	gauge.Set(3, "abc")
+	histogram.Observe(4, "abc")
+	assert.Equal(t, float64(2), e.values["test_registry_counter|abc"])
+	assert.Equal(t, float64(3), e.values["test_registry_gauge|abc"])
+	assert.Equal(t, float64(4), e.values["test_registry_histogram|abc"])
+
+	// The metrics created after the exporter is set.
+	lateCounter := NewCounter("test_registry_late_counter", "", []string{"resource"})
+	lateCounter.Add(5, "def")
+	assert.Equal(t, float64(5), e.values["test_registry_late_counter|def"])
+}
@@ -0,0 +1,304 @@
+// Copyright 1999-2021 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsd
+
+import (
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Danceiny/sentinel-golang/logging"
+	"github.com/Danceiny/sentinel-golang/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// FlavorDogStatsD sends the labels as DogStatsD tags, like "name:1|c|#resource:abc".
+	FlavorDogStatsD = "dogstatsd"
+	// FlavorStatsD appends the label values to the metric name, like "name.abc:1|c".
+	FlavorStatsD = "statsd"
+
+	// The max amount of the histogram values of one metric series sent in a flush interval,
+	// the values are sampled once the amount is exceeded and sent with the sample rate.
+	maxHistogramSampleAmount = 1000
+)
+
+var (
+	tagReplacer  = strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_", "\r", "_")
+	nameReplacer = strings.NewReplacer(":", "_", "|", "_", "@", "_", "#", "_", ".", "_", " ", "_", "\n", "_", "\r", "_")
+)
+
+// Options represents the options of the StatsD client.
+type Options struct {
+	// Network is "udp" or "unixgram".
+	Network string
+	Addr    string
+	// Prefix is the prefix of the metric names, joined with a ".".
+	Prefix string
+	Flavor string
+	// FlushInterval is the interval of flushing the aggregated metrics.
+	FlushInterval time.Duration
+	// MaxPacketSize is the max size (in bytes) of a single packet.
+	MaxPacketSize int
+	// ConstTags are attached to all metrics in DogStatsD flavor.
+	ConstTags map[string]string
+}
+
+// series identifies a metric series, the tags (or the name suffix in StatsD flavor) are formatted already.
+type series struct {
+	name string
+	tags string
+}
+
+// Client aggregates the metric updates on client side and sends them to the StatsD server periodically.
+// Counters are summed, gauges keep the latest value and histogram values are sent as they are,
+// or sampled with the sample rate "|@rate" once there are too many values of a series in a flush interval,
+// so that the StatsD server scales the counts back.
+type Client struct {
+	opts      Options
+	conn      net.Conn
+	constTags []string
+
+	counters   map[series]float64
+	gauges     map[series]float64
+	histograms map[series]*histogramSamples
+	mux        sync.Mutex
+
+	stopChan  chan struct{}
+	closeOnce sync.Once
+}
+
+// NewClient dials the StatsD server and starts the flushing task.
+func NewClient(opts Options) (*Client, error) {
+	if opts.Network != "udp" && opts.Network != "unixgram" {
+		return nil, errors.Errorf("unsupported network: %s", opts.Network)
+	}
+	if opts.Addr == "" {
+		return nil, errors.New("empty address")
+	}
+	if opts.Flavor != FlavorDogStatsD && opts.Flavor != FlavorStatsD {
+		return nil, errors.Errorf("unsupported flavor: %s", opts.Flavor)
+	}
+	if opts.FlushInterval <= 0 || opts.MaxPacketSize <= 0 {
+		return nil, errors.New("invalid flush interval or max packet size")
+	}
+	conn, err := net.Dial(opts.Network, opts.Addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dial the StatsD server")
+	}
+	c := &Client{
+		opts:       opts,
+		conn:       conn,
+		counters:   make(map[series]float64),
+		gauges:     make(map[series]float64),
+		histograms: make(map[series]*histogramSamples),
+		stopChan:   make(chan struct{}),
+	}
+	for k, v := range opts.ConstTags {
+		c.constTags = append(c.constTags, formatTag(k, v))
+	}
+	sort.Strings(c.constTags)
+
+	ticker := util.NewTicker(opts.FlushInterval)
+	go util.RunWithRecover(func() {
+		for {
+			select {
+			case <-ticker.C():
+				c.Flush()
+			case <-c.stopChan:
+				ticker.Stop()
+				return
+			}
+		}
+	})
+	return c, nil
+}
+
+// Close stops the flushing task, flushes the pending metrics and closes the connection.
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.stopChan)
+		c.Flush()
+		err = c.conn.Close()
+	})
+	return err
+}
+
+func (c *Client) series(name string, labelNames, labelValues []string) series {
+	if c.opts.Prefix != "" {
+		name = c.opts.Prefix + "." + name
+	}
+	if c.opts.Flavor == FlavorStatsD {
+		for _, v := range labelValues {
+			name = name + "." + nameReplacer.Replace(v)
+		}
+		return series{name: name}
+	}
+	tags := make([]string, 0, len(c.constTags)+len(labelValues))
+	tags = append(tags, c.constTags...)
+	for i := 0; i < len(labelNames) && i < len(labelValues); i++ {
+		tags = append(tags, formatTag(labelNames[i], labelValues[i]))
+	}
+	return series{name: name, tags: strings.Join(tags, ",")}
+}
+
+func (c *Client) count(s series, value float64) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.counters[s] += value
+}
+
+func (c *Client) gauge(s series, value float64) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.gauges[s] = value
+}
+
+func (c *Client) histogram(s series, value float64) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	h, ok := c.histograms[s]
+	if !ok {
+		h = &histogramSamples{values: make([]float64, 0, 4)}
+		c.histograms[s] = h
+	}
+	h.observe(value)
+}
+
+// histogramSamples keeps a uniform sample of the histogram values of a series by reservoir sampling.
+type histogramSamples struct {
+	values []float64
+	// count is the amount of all the observed values.
+	count uint64
+}
+
+func (h *histogramSamples) observe(value float64) {
+	h.count++
+	if len(h.values) < maxHistogramSampleAmount {
+		h.values = append(h.values, value)
+		return
+	}
+	if i := rand.Int63n(int64(h.count)); i < maxHistogramSampleAmount {
+		h.values[i] = value
+	}
+}
+
+// sampleRate returns the ratio of the sampled values to all the observed values.
+func (h *histogramSamples) sampleRate() float64 {
+	return float64(len(h.values)) / float64(h.count)
+}
+
+// reset drops the pending values of the metric series of given name.
+func (c *Client) reset(name string) {
+	if c.opts.Prefix != "" {
+		name = c.opts.Prefix + "." + name
+	}
+	matches := func(s series) bool {
+		return s.name == name || strings.HasPrefix(s.name, name+".")
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for s := range c.counters {
+		if matches(s) {
+			delete(c.counters, s)
+		}
+	}
+	for s := range c.gauges {
+		if matches(s) {
+			delete(c.gauges, s)
+		}
+	}
+	for s := range c.histograms {
+		if matches(s) {
+			delete(c.histograms, s)
+		}
+	}
+}
+
+// Flush sends all the aggregated metrics to the StatsD server.
+func (c *Client) Flush() {
+	c.mux.Lock()
+	counters, gauges, histograms := c.counters, c.gauges, c.histograms
+	c.counters = make(map[series]float64)
+	c.gauges = make(map[series]float64)
+	c.histograms = make(map[series]*histogramSamples)
+	c.mux.Unlock()
+
+	histogramType := "h"
+	if c.opts.Flavor == FlavorStatsD {
+		histogramType = "ms"
+	}
+	lines := make([]string, 0, len(counters)+len(gauges)+len(histograms))
+	for s, v := range counters {
+		lines = append(lines, formatLine(s, v, "c", 1))
+	}
+	for s, v := range gauges {
+		lines = append(lines, formatLine(s, v, "g", 1))
+	}
+	for s, h := range histograms {
+		rate := h.sampleRate()
+		for _, v := range h.values {
+			lines = append(lines, formatLine(s, v, histogramType, rate))
+		}
+	}
+	sort.Strings(lines)
+	for _, packet := range packLines(lines, c.opts.MaxPacketSize) {
+		if _, err := c.conn.Write(packet); err != nil {
+			logging.Error(err, "Failed to send StatsD packet in Client.Flush()", "addr", c.opts.Addr)
+		}
+	}
+}
+
+// packLines packs the lines into packets separated by "\n", each packet doesn't exceed the max size
+// unless it consists of a single line which exceeds the max size.
+func packLines(lines []string, maxSize int) [][]byte {
+	packets := make([][]byte, 0)
+	buf := make([]byte, 0, maxSize)
+	for _, line := range lines {
+		if len(buf) > 0 && len(buf)+1+len(line) > maxSize {
+			packets = append(packets, buf)
+			buf = make([]byte, 0, maxSize)
+		}
+		if len(buf) > 0 {
+			buf = append(buf, '\n')
+		}
+		buf = append(buf, line...)
+	}
+	if len(buf) > 0 {
+		packets = append(packets, buf)
+	}
+	return packets
+}
+
+// formatLine formats the line of the metric, the sample rate is omitted unless it's less than 1.
+func formatLine(s series, value float64, metricType string, sampleRate float64) string {
+	line := s.name + ":" + strconv.FormatFloat(value, 'f', -1, 64) + "|" + metricType
+	if sampleRate < 1 {
+		line = line + "|@" + strconv.FormatFloat(sampleRate, 'g', 6, 64)
+	}
+	if s.tags != "" {
+		line = line + "|#" + s.tags
+	}
+	return line
+}
+
+func formatTag(name, value string) string {
+	return tagReplacer.Replace(name) + ":" + tagReplacer.Replace(value)
+}
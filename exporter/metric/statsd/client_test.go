@@ -0,0 +1,189 @@
+// Copyright 1999-2021 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsd
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, flavor string, maxPacketSize int) (*Client, *net.UDPConn) {
+	server, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	client, err := NewClient(Options{
+		Network:       "udp",
+		Addr:          server.LocalAddr().String(),
+		Prefix:        "sentinel_go",
+		Flavor:        flavor,
+		FlushInterval: time.Hour,
+		MaxPacketSize: maxPacketSize,
+		ConstTags:     map[string]string{"app": "test"},
+	})
+	require.NoError(t, err)
+	return client, server
+}
+
+func readPackets(t *testing.T, server *net.UDPConn, n int) []string {
+	packets := make([]string, 0, n)
+	buf := make([]byte, 65535)
+	for i := 0; i < n; i++ {
+		require.NoError(t, server.SetReadDeadline(time.Now().Add(time.Second)))
+		l, err := server.Read(buf)
+		require.NoError(t, err)
+		packets = append(packets, string(buf[:l]))
+	}
+	return packets
+}
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient(Options{Network: "tcp", Addr: "127.0.0.1:8125", Flavor: FlavorStatsD, FlushInterval: time.Second, MaxPacketSize: 1432})
+	assert.Error(t, err)
+	_, err = NewClient(Options{Network: "udp", Addr: "", Flavor: FlavorStatsD, FlushInterval: time.Second, MaxPacketSize: 1432})
+	assert.Error(t, err)
+	_, err = NewClient(Options{Network: "udp", Addr: "127.0.0.1:8125", Flavor: "graphite", FlushInterval: time.Second, MaxPacketSize: 1432})
+	assert.Error(t, err)
+	_, err = NewClient(Options{Network: "udp", Addr: "127.0.0.1:8125", Flavor: FlavorStatsD, FlushInterval: 0, MaxPacketSize: 1432})
+	assert.Error(t, err)
+}
+
+func TestClientDogStatsD(t *testing.T) {
+	client, server := newTestClient(t, FlavorDogStatsD, 1432)
+	defer server.Close()
+	defer client.Close()
+
+	counter := NewCounter(client, "block_total", []string{"resource"})
+	gauge := NewGauge(client, "resource_concurrency", []string{"resource"})
+	histogram := NewHistogram(client, "resource_rt_ms", []string{"resource"})
+	_ = counter.Register()
+	_ = gauge.Register()
+	_ = histogram.Register()
+
+	counter.Add(1, "abc")
+	counter.Add(2, "abc")
+	counter.Add(1, "def")
+	gauge.Set(3, "abc")
+	gauge.Set(5, "abc")
+	histogram.Observe(10, "abc")
+	histogram.Observe(20.5, "abc")
+	client.Flush()
+
+	packets := readPackets(t, server, 1)
+	assert.Equal(t, []string{
+		"sentinel_go.block_total:1|c|#app:test,resource:def",
+		"sentinel_go.block_total:3|c|#app:test,resource:abc",
+		"sentinel_go.resource_concurrency:5|g|#app:test,resource:abc",
+		"sentinel_go.resource_rt_ms:10|h|#app:test,resource:abc",
+		"sentinel_go.resource_rt_ms:20.5|h|#app:test,resource:abc",
+	}, strings.Split(packets[0], "\n"))
+}
+
+func TestClientStatsD(t *testing.T) {
+	client, server := newTestClient(t, FlavorStatsD, 1432)
+	defer server.Close()
+	defer client.Close()
+
+	counter := NewCounter(client, "block_total", []string{"resource", "type"})
+	histogram := NewHistogram(client, "resource_rt_ms", []string{"resource"})
+	_ = counter.Register()
+	_ = histogram.Register()
+
+	counter.Add(1, "GET:/foo.bar", "Flow")
+	histogram.Observe(10, "abc")
+	client.Flush()
+
+	packets := readPackets(t, server, 1)
+	assert.Equal(t, "sentinel_go.block_total.GET_/foo_bar.Flow:1|c\nsentinel_go.resource_rt_ms.abc:10|ms", packets[0])
+}
+
+func TestClientUnregisteredAndReset(t *testing.T) {
+	client, server := newTestClient(t, FlavorDogStatsD, 1432)
+	defer server.Close()
+	defer client.Close()
+
+	counter := NewCounter(client, "block_total", []string{"resource"})
+	gauge := NewGauge(client, "resource_concurrency", []string{"resource"})
+	_ = gauge.Register()
+
+	// The updates of unregistered metric are dropped.
+	counter.Add(1, "abc")
+	gauge.Set(1, "abc")
+	gauge.Reset()
+	gauge.Set(2, "def")
+	client.Flush()
+
+	packets := readPackets(t, server, 1)
+	assert.Equal(t, "sentinel_go.resource_concurrency:2|g|#app:test,resource:def", packets[0])
+}
+
+func TestClientMaxPacketSize(t *testing.T) {
+	client, server := newTestClient(t, FlavorStatsD, 64)
+	defer server.Close()
+	defer client.Close()
+
+	counter := NewCounter(client, "block_total", []string{"resource"})
+	_ = counter.Register()
+	for _, res := range []string{"a", "b", "c", "d", "e"} {
+		counter.Add(1, res)
+	}
+	client.Flush()
+
+	// Each line is 29 bytes, so a packet holds 2 lines at most.
+	packets := readPackets(t, server, 3)
+	for _, p := range packets {
+		assert.True(t, len(p) <= 64)
+	}
+	assert.Equal(t, "sentinel_go.block_total.a:1|c\nsentinel_go.block_total.b:1|c", packets[0])
+	assert.Equal(t, "sentinel_go.block_total.e:1|c", packets[2])
+}
+
+func TestClientSampleHistogramValues(t *testing.T) {
+	client, server := newTestClient(t, FlavorStatsD, 65535)
+	defer server.Close()
+	defer client.Close()
+
+	histogram := NewHistogram(client, "resource_rt_ms", []string{"resource"})
+	_ = histogram.Register()
+	for i := 0; i < maxHistogramSampleAmount*4; i++ {
+		histogram.Observe(1, "abc")
+	}
+	client.Flush()
+
+	// The sampled values are sent with the sample rate so that the server scales the count back.
+	packets := readPackets(t, server, 1)
+	lines := strings.Split(packets[0], "\n")
+	assert.Equal(t, maxHistogramSampleAmount, len(lines))
+	for _, line := range lines {
+		assert.Equal(t, "sentinel_go.resource_rt_ms.abc:1|ms|@0.25", line)
+	}
+
+	// The sampling applies to each flush interval.
+	histogram.Observe(1, "abc")
+	client.Flush()
+	packets = readPackets(t, server, 1)
+	assert.Equal(t, "sentinel_go.resource_rt_ms.abc:1|ms", packets[0])
+}
+
+func TestPackLines(t *testing.T) {
+	packets := packLines([]string{"aaaa", "bbbbbbbbbb", "cc"}, 8)
+	assert.Equal(t, [][]byte{[]byte("aaaa"), []byte("bbbbbbbbbb"), []byte("cc")}, packets)
+	packets = packLines([]string{"aa", "bb", "cc"}, 8)
+	assert.Equal(t, [][]byte{[]byte("aa\nbb\ncc")}, packets)
+	assert.Empty(t, packLines(nil, 8))
+}
@@ -0,0 +1,91 @@
+// Copyright 1999-2021 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsd
+
+import (
+	"sync/atomic"
+)
+
+// metric holds the common parts of the metrics. The updates of the unregistered metric are dropped.
+type metric struct {
+	client     *Client
+	name       string
+	labelNames []string
+	// registered indicates whether the metric is registered, 1 if registered.
+	registered int32
+}
+
+func (m *metric) Register() error {
+	atomic.StoreInt32(&m.registered, 1)
+	return nil
+}
+
+func (m *metric) Unregister() bool {
+	return atomic.CompareAndSwapInt32(&m.registered, 1, 0)
+}
+
+func (m *metric) Reset() {
+	m.client.reset(m.name)
+}
+
+func (m *metric) isRegistered() bool {
+	return atomic.LoadInt32(&m.registered) == 1
+}
+
+type Counter struct {
+	metric
+}
+
+type Gauge struct {
+	metric
+}
+
+type Histogram struct {
+	metric
+}
+
+func NewCounter(client *Client, name string, labelNames []string) *Counter {
+	return &Counter{metric{client: client, name: name, labelNames: labelNames}}
+}
+
+func (c *Counter) Add(value float64, labelValues ...string) {
+	if !c.isRegistered() {
+		return
+	}
+	c.client.count(c.client.series(c.name, c.labelNames, labelValues), value)
+}
+
+func NewGauge(client *Client, name string, labelNames []string) *Gauge {
+	return &Gauge{metric{client: client, name: name, labelNames: labelNames}}
+}
+
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	if !g.isRegistered() {
+		return
+	}
+	g.client.gauge(g.client.series(g.name, g.labelNames, labelValues), value)
+}
+
+// NewHistogram creates the Histogram, the buckets are computed by the StatsD server.
+func NewHistogram(client *Client, name string, labelNames []string) *Histogram {
+	return &Histogram{metric{client: client, name: name, labelNames: labelNames}}
+}
+
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	if !h.isRegistered() {
+		return
+	}
+	h.client.histogram(h.client.series(h.name, h.labelNames, labelValues), value)
+}
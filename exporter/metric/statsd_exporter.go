@@ -0,0 +1,62 @@
+// Copyright 1999-2021 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Danceiny/sentinel-golang/core/config"
+	"github.com/Danceiny/sentinel-golang/exporter/metric/statsd"
+)
+
+// statsDExporter is the exporter which pushes metrics to the StatsD (DogStatsD) server.
+type statsDExporter struct {
+	client *statsd.Client
+}
+
+// newStatsDExporter creates the statsDExporter from the StatsD exporter config.
+func newStatsDExporter() (*statsDExporter, error) {
+	client, err := statsd.NewClient(statsd.Options{
+		Network:       config.StatsDNetwork(),
+		Addr:          config.StatsDAddr(),
+		Prefix:        config.StatsDPrefix(),
+		Flavor:        config.StatsDFlavor(),
+		FlushInterval: time.Duration(config.StatsDFlushIntervalMs()) * time.Millisecond,
+		MaxPacketSize: int(config.StatsDMaxPacketSize()),
+		ConstTags:     newConstLabels(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &statsDExporter{client: client}, nil
+}
+
+func (e *statsDExporter) NewCounter(name, desc string, labelNames []string) Counter {
+	return statsd.NewCounter(e.client, name, labelNames)
+}
+
+func (e *statsDExporter) NewGauge(name, desc string, labelNames []string) Gauge {
+	return statsd.NewGauge(e.client, name, labelNames)
+}
+
+func (e *statsDExporter) NewHistogram(name, desc string, buckets []float64, labelNames []string) Histogram {
+	return statsd.NewHistogram(e.client, name, labelNames)
+}
+
+// HTTPHandler returns the handler which responds 404, since the metrics are pushed to the StatsD server.
+func (e *statsDExporter) HTTPHandler() http.Handler {
+	return http.NotFoundHandler()
+}
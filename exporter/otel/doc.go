@@ -0,0 +1,36 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package otel provides the OpenTelemetry integration of Sentinel.
+
+The integration consists of three parts:
+
+ 1. Slot: a stat slot which records the Sentinel decisions (pass or block) as span events
+    and attributes of the active span. The span is retrieved from the context.Context carried
+    by the entry (see api.WithContext; api.Execute and api.EntryWithFallback carry it automatically).
+    The slot could be added into the slot chain like this:
+
+    api.GlobalSlotChain().AddStatSlot(otel.DefaultSlot)
+
+ 2. Exporter: an implementation of the metric Exporter (exporter/metric) based on
+    the OpenTelemetry metric API, which could be created by NewExporter(meter) and registered
+    by name, then selected by the metric exporter name in Sentinel config:
+
+    metric.RegisterExporter("otel", otel.NewExporter(meter))
+
+ 3. Entry and Execute: the tracing-aware wrappers of api.Entry and api.Execute,
+    which mark the active span as blocked if the entry is blocked by Sentinel.
+*/
+package otel
@@ -0,0 +1,143 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	metric_exporter "github.com/Danceiny/sentinel-golang/exporter/metric"
+	"github.com/Danceiny/sentinel-golang/logging"
+)
+
+const namespace = "sentinel_go"
+
+var (
+	_ metric_exporter.Exporter = &Exporter{}
+
+	noopMeter = noop.NewMeterProvider().Meter("")
+)
+
+// Exporter is the metric exporter of OpenTelemetry implementation, which creates the
+// OpenTelemetry instruments from the given metric.Meter. The metrics are exported by
+// the readers of the MeterProvider, rather than the HTTP handler.
+type Exporter struct {
+	meter metric.Meter
+}
+
+// NewExporter creates an Exporter which creates the instruments from the given meter.
+func NewExporter(meter metric.Meter) *Exporter {
+	return &Exporter{meter: meter}
+}
+
+func (e *Exporter) NewCounter(name, desc string, labelNames []string) metric_exporter.Counter {
+	c, err := e.meter.Float64Counter(instrumentName(name), metric.WithDescription(desc))
+	if err != nil {
+		logging.Error(err, "[OTelExporter] Failed to create the counter, use the no-op counter instead", "name", name)
+		c, _ = noopMeter.Float64Counter(name)
+	}
+	return &counter{instrument: instrument{labelNames: labelNames}, c: c}
+}
+
+func (e *Exporter) NewGauge(name, desc string, labelNames []string) metric_exporter.Gauge {
+	g, err := e.meter.Float64Gauge(instrumentName(name), metric.WithDescription(desc))
+	if err != nil {
+		logging.Error(err, "[OTelExporter] Failed to create the gauge, use the no-op gauge instead", "name", name)
+		g, _ = noopMeter.Float64Gauge(name)
+	}
+	return &gauge{instrument: instrument{labelNames: labelNames}, g: g}
+}
+
+func (e *Exporter) NewHistogram(name, desc string, buckets []float64, labelNames []string) metric_exporter.Histogram {
+	opts := []metric.Float64HistogramOption{metric.WithDescription(desc)}
+	if len(buckets) > 0 {
+		opts = append(opts, metric.WithExplicitBucketBoundaries(buckets...))
+	}
+	h, err := e.meter.Float64Histogram(instrumentName(name), opts...)
+	if err != nil {
+		logging.Error(err, "[OTelExporter] Failed to create the histogram, use the no-op histogram instead", "name", name)
+		h, _ = noopMeter.Float64Histogram(name)
+	}
+	return &histogram{instrument: instrument{labelNames: labelNames}, h: h}
+}
+
+// HTTPHandler always responds 404, since the metrics are exported by the readers of the MeterProvider.
+func (e *Exporter) HTTPHandler() http.Handler {
+	return http.NotFoundHandler()
+}
+
+func instrumentName(name string) string {
+	return namespace + "_" + name
+}
+
+// instrument implements metric_exporter.Metric. OpenTelemetry instruments are registered
+// to the meter on creation and could not be unregistered or reset, so all the operations are no-op.
+type instrument struct {
+	labelNames []string
+}
+
+func (i *instrument) Register() error {
+	return nil
+}
+
+func (i *instrument) Unregister() bool {
+	return false
+}
+
+func (i *instrument) Reset() {
+}
+
+func (i *instrument) attributes(labelValues []string) metric.MeasurementOption {
+	n := len(i.labelNames)
+	if len(labelValues) < n {
+		n = len(labelValues)
+	}
+	attrs := make([]attribute.KeyValue, 0, n)
+	for j := 0; j < n; j++ {
+		attrs = append(attrs, attribute.String(i.labelNames[j], labelValues[j]))
+	}
+	return metric.WithAttributes(attrs...)
+}
+
+type counter struct {
+	instrument
+	c metric.Float64Counter
+}
+
+func (c *counter) Add(value float64, labelValues ...string) {
+	c.c.Add(context.Background(), value, c.attributes(labelValues))
+}
+
+type gauge struct {
+	instrument
+	g metric.Float64Gauge
+}
+
+func (g *gauge) Set(value float64, labelValues ...string) {
+	g.g.Record(context.Background(), value, g.attributes(labelValues))
+}
+
+type histogram struct {
+	instrument
+	h metric.Float64Histogram
+}
+
+func (h *histogram) Observe(value float64, labelValues ...string) {
+	h.h.Record(context.Background(), value, h.attributes(labelValues))
+}
@@ -0,0 +1,95 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func findMetric(rm metricdata.ResourceMetrics, name string) (metricdata.Metrics, bool) {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return metricdata.Metrics{}, false
+}
+
+func TestExporter(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	e := NewExporter(mp.Meter("sentinel-test"))
+
+	c := e.NewCounter("handled_total", "Total handled count", []string{"resource", "result"})
+	c.Add(2, "abc", "pass")
+	c.Add(3, "abc", "pass")
+	c.Add(1, "abc", "block")
+	g := e.NewGauge("concurrency", "Current concurrency", []string{"resource"})
+	g.Set(5, "abc")
+	g.Set(7, "abc")
+	h := e.NewHistogram("rt", "Response time", []float64{10, 100}, []string{"resource"})
+	h.Observe(5, "abc")
+	h.Observe(50, "abc")
+	// Extra label values are ignored.
+	h.Observe(500, "abc", "extra")
+
+	assert.Nil(t, c.Register())
+	assert.False(t, c.Unregister())
+	c.Reset()
+
+	rm := metricdata.ResourceMetrics{}
+	assert.Nil(t, reader.Collect(context.Background(), &rm))
+
+	m, ok := findMetric(rm, "sentinel_go_handled_total")
+	assert.True(t, ok)
+	assert.Equal(t, "Total handled count", m.Description)
+	sum := m.Data.(metricdata.Sum[float64])
+	assert.Equal(t, 2, len(sum.DataPoints))
+	for _, dp := range sum.DataPoints {
+		v, _ := dp.Attributes.Value(attribute.Key("result"))
+		if v.AsString() == "pass" {
+			assert.Equal(t, 5.0, dp.Value)
+		} else {
+			assert.Equal(t, 1.0, dp.Value)
+		}
+	}
+
+	m, ok = findMetric(rm, "sentinel_go_concurrency")
+	assert.True(t, ok)
+	gd := m.Data.(metricdata.Gauge[float64])
+	assert.Equal(t, 1, len(gd.DataPoints))
+	assert.Equal(t, 7.0, gd.DataPoints[0].Value)
+
+	m, ok = findMetric(rm, "sentinel_go_rt")
+	assert.True(t, ok)
+	hd := m.Data.(metricdata.Histogram[float64])
+	assert.Equal(t, 1, len(hd.DataPoints))
+	assert.Equal(t, []float64{10, 100}, hd.DataPoints[0].Bounds)
+	assert.Equal(t, []uint64{1, 1, 1}, hd.DataPoints[0].BucketCounts)
+
+	rec := httptest.NewRecorder()
+	e.HTTPHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,96 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+)
+
+const (
+	StatSlotOrder = 7000
+
+	// EventNamePass is the name of the span event which records the passed entry.
+	EventNamePass = "sentinel.pass"
+	// EventNameBlock is the name of the span event which records the blocked entry.
+	EventNameBlock = "sentinel.block"
+
+	ResultPass  = "pass"
+	ResultBlock = "block"
+
+	AttrResource      = attribute.Key("sentinel.resource")
+	AttrResult        = attribute.Key("sentinel.result")
+	AttrBlocked       = attribute.Key("sentinel.blocked")
+	AttrBlockType     = attribute.Key("sentinel.block_type")
+	AttrRuleID        = attribute.Key("sentinel.rule_id")
+	AttrSnapshotValue = attribute.Key("sentinel.snapshot_value")
+)
+
+var (
+	DefaultSlot = &Slot{}
+)
+
+// Slot records the Sentinel decisions into the active span of the context.Context carried by the entry.
+type Slot struct {
+}
+
+func (s *Slot) Order() uint32 {
+	return StatSlotOrder
+}
+
+func (s *Slot) OnEntryPassed(ctx *base.EntryContext) {
+	span := trace.SpanFromContext(ctx.Context())
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent(EventNamePass, trace.WithAttributes(
+		AttrResource.String(ctx.Resource.Name()),
+		AttrResult.String(ResultPass),
+	))
+}
+
+func (s *Slot) OnEntryBlocked(ctx *base.EntryContext, blockError *base.BlockError) {
+	span := trace.SpanFromContext(ctx.Context())
+	if !span.IsRecording() {
+		return
+	}
+	attrs := blockAttributes(ctx.Resource.Name(), blockError)
+	span.AddEvent(EventNameBlock, trace.WithAttributes(attrs...))
+	span.SetAttributes(attrs...)
+}
+
+func (s *Slot) OnCompleted(_ *base.EntryContext) {
+
+}
+
+func blockAttributes(resource string, blockError *base.BlockError) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		AttrResource.String(resource),
+		AttrResult.String(ResultBlock),
+		AttrBlocked.Bool(true),
+		AttrBlockType.String(blockError.BlockType().String()),
+	}
+	if r, ok := blockError.TriggeredRule().(base.IdentifiedRule); ok && r.RuleID() != "" {
+		attrs = append(attrs, AttrRuleID.String(r.RuleID()))
+	}
+	if v := blockError.TriggeredValue(); v != nil {
+		attrs = append(attrs, AttrSnapshotValue.String(fmt.Sprint(v)))
+	}
+	return attrs
+}
@@ -0,0 +1,162 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/flow"
+)
+
+// blockingSlot blocks all the entries of the resource "blocked".
+type blockingSlot struct {
+}
+
+func (s *blockingSlot) Order() uint32 {
+	return 0
+}
+
+func (s *blockingSlot) Check(ctx *base.EntryContext) *base.TokenResult {
+	if ctx.Resource.Name() != "blocked" {
+		return nil
+	}
+	rule := &flow.Rule{ID: "rule-1", Resource: "blocked"}
+	return base.NewTokenResultBlockedWithCause(base.BlockTypeFlow, "blocked by test", rule, 10.0)
+}
+
+func newTestSlotChain() *base.SlotChain {
+	sc := base.NewSlotChain()
+	sc.AddRuleCheckSlot(&blockingSlot{})
+	sc.AddStatSlot(DefaultSlot)
+	return sc
+}
+
+func newTestTracer() (*tracetest.SpanRecorder, func(ctx context.Context) (context.Context, func())) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	tracer := tp.Tracer("sentinel-test")
+	return sr, func(ctx context.Context) (context.Context, func()) {
+		ctx, span := tracer.Start(ctx, "operation")
+		return ctx, func() { span.End() }
+	}
+}
+
+func attributeMap(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestSlot(t *testing.T) {
+	sc := newTestSlotChain()
+
+	t.Run("Pass", func(t *testing.T) {
+		sr, start := newTestTracer()
+		ctx, end := start(context.Background())
+		err := sentinel.Execute(ctx, "abc", func(ctx context.Context) error {
+			return nil
+		}, sentinel.WithSlotChain(sc))
+		end()
+		assert.Nil(t, err)
+
+		spans := sr.Ended()
+		assert.Equal(t, 1, len(spans))
+		events := spans[0].Events()
+		assert.Equal(t, 1, len(events))
+		assert.Equal(t, EventNamePass, events[0].Name)
+		attrs := attributeMap(events[0].Attributes)
+		assert.Equal(t, "abc", attrs[AttrResource].AsString())
+		assert.Equal(t, ResultPass, attrs[AttrResult].AsString())
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		sr, start := newTestTracer()
+		ctx, end := start(context.Background())
+		_, blockErr := sentinel.Entry("blocked", sentinel.WithSlotChain(sc), sentinel.WithContext(ctx))
+		end()
+		assert.NotNil(t, blockErr)
+
+		spans := sr.Ended()
+		assert.Equal(t, 1, len(spans))
+		events := spans[0].Events()
+		assert.Equal(t, 1, len(events))
+		assert.Equal(t, EventNameBlock, events[0].Name)
+		attrs := attributeMap(events[0].Attributes)
+		assert.Equal(t, "blocked", attrs[AttrResource].AsString())
+		assert.Equal(t, ResultBlock, attrs[AttrResult].AsString())
+		assert.Equal(t, base.BlockTypeFlow.String(), attrs[AttrBlockType].AsString())
+		assert.Equal(t, "rule-1", attrs[AttrRuleID].AsString())
+		assert.Equal(t, "10", attrs[AttrSnapshotValue].AsString())
+		assert.True(t, attributeMap(spans[0].Attributes())[AttrBlocked].AsBool())
+		// The slot itself doesn't change the span status.
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	})
+
+	t.Run("NoSpan", func(t *testing.T) {
+		e, blockErr := sentinel.Entry("abc", sentinel.WithSlotChain(sc))
+		assert.Nil(t, blockErr)
+		e.Exit()
+	})
+}
+
+func TestTracingWrapper(t *testing.T) {
+	sc := newTestSlotChain()
+
+	t.Run("Entry", func(t *testing.T) {
+		sr, start := newTestTracer()
+		ctx, end := start(context.Background())
+		e, blockErr := Entry(ctx, "abc", sentinel.WithSlotChain(sc))
+		assert.Nil(t, blockErr)
+		e.Exit()
+		_, blockErr = Entry(ctx, "blocked", sentinel.WithSlotChain(sc))
+		assert.NotNil(t, blockErr)
+		end()
+
+		spans := sr.Ended()
+		assert.Equal(t, 1, len(spans))
+		assert.Equal(t, 2, len(spans[0].Events()))
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, blockErr.Error(), spans[0].Status().Description)
+	})
+
+	t.Run("Execute", func(t *testing.T) {
+		sr, start := newTestTracer()
+		ctx, end := start(context.Background())
+		err := Execute(ctx, "blocked", func(ctx context.Context) error {
+			t.Fatal("the blocked function should not be invoked")
+			return nil
+		}, sentinel.WithSlotChain(sc))
+		end()
+		assert.NotNil(t, err)
+
+		spans := sr.Ended()
+		assert.Equal(t, 1, len(spans))
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		attrs := attributeMap(spans[0].Attributes())
+		assert.True(t, attrs[AttrBlocked].AsBool())
+		assert.Equal(t, "rule-1", attrs[AttrRuleID].AsString())
+	})
+}
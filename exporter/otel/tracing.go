@@ -0,0 +1,58 @@
+// Copyright 1999-2020 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+)
+
+// Entry works like api.Entry, but the entry carries ctx (so that Slot could find the active span),
+// and the active span of ctx is marked as blocked if the entry is blocked.
+func Entry(ctx context.Context, resource string, opts ...sentinel.EntryOption) (*base.SentinelEntry, *base.BlockError) {
+	e, blockErr := sentinel.Entry(resource, append([]sentinel.EntryOption{sentinel.WithContext(ctx)}, opts...)...)
+	if blockErr != nil {
+		MarkBlocked(ctx, resource, blockErr)
+	}
+	return e, blockErr
+}
+
+// Execute works like api.Execute, and the active span of ctx is marked as blocked
+// if the guarded operation is blocked.
+func Execute(ctx context.Context, resource string, fn func(ctx context.Context) error, opts ...sentinel.EntryOption) error {
+	err := sentinel.Execute(ctx, resource, fn, opts...)
+	var blockErr *base.BlockError
+	if errors.As(err, &blockErr) {
+		MarkBlocked(ctx, resource, blockErr)
+	}
+	return err
+}
+
+// MarkBlocked marks the active span of ctx as blocked by Sentinel: the attributes of the block
+// are added to the span and the span status is set to error.
+func MarkBlocked(ctx context.Context, resource string, blockErr *base.BlockError) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() || blockErr == nil {
+		return
+	}
+	span.SetAttributes(blockAttributes(resource, blockErr)...)
+	span.SetStatus(codes.Error, blockErr.Error())
+}
@@ -0,0 +1,32 @@
+/*
+This package provides Sentinel integration for connect-go.
+
+Users may register the Sentinel interceptor to the connect handler or client,
+which works for both the unary and streaming invocations, like.
+
+	import (
+		sentinelPlugin "github.com/Danceiny/sentinel-golang/pkg/adapters/connect"
+		"connectrpc.com/connect"
+	)
+
+	interceptors := connect.WithInterceptors(sentinelPlugin.NewInterceptor())
+	mux.Handle(greetv1connect.NewGreetServiceHandler(greeter, interceptors))
+	client := greetv1connect.NewGreetServiceClient(http.DefaultClient, url, interceptors)
+
+The interceptor extracts the procedure as the resource name by default (e.g. /greet.v1.GreetService/Greet),
+the invocations of the client side are outbound traffic while the ones of the handler side are inbound.
+Users may provide customized resource name extractor via WithResourceExtractor option.
+
+Error tracing: the errors of CodeUnknown, CodeDeadlineExceeded, CodeInternal, CodeUnavailable and CodeDataLoss
+are traced as errors by default, so that the client errors (e.g. CodeInvalidArgument) don't trip the circuit breakers.
+The connect code of the failed invocation is recorded as the entry pair of CodePairKey.
+Users may customize the codes via WithErrorCodes option.
+
+Fallback logic: the interceptor will return the connect error of CodeResourceExhausted
+if current invocation is blocked by Sentinel rules, with the block type (e.g. "BlockTypeFlowControl")
+in the error metadata of BlockTypeMetaKey. Users may also provide customized fallback logic
+via WithBlockFallback(handler) option. If no block fallback option is provided, the interceptor consults
+the fallback handlers registered for the resource in Sentinel core fallback registry, whose returned error
+is returned to the caller instead, while the result of the handlers is discarded.
+*/
+package connect
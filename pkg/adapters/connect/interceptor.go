@@ -0,0 +1,192 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"connectrpc.com/connect"
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/fallback"
+)
+
+// BlockTypeMetaKey is the key of the error metadata which records the block type of the blocked invocation.
+const BlockTypeMetaKey = "Sentinel-Block-Type"
+
+// NewInterceptor creates the connect.Interceptor wrapped with Sentinel entry,
+// which works for both the unary and streaming invocations of the client and handler side.
+func NewInterceptor(opts ...Option) connect.Interceptor {
+	return &interceptor{options: evaluateOptions(opts)}
+}
+
+type interceptor struct {
+	options *options
+}
+
+func (i *interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		entry, err := i.entry(ctx, req.Spec())
+		if err != nil {
+			return nil, err
+		}
+		defer entry.Exit()
+
+		res, err := next(ctx, req)
+		i.traceError(entry, err)
+		return res, err
+	}
+}
+
+func (i *interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		entry, err := i.entry(ctx, spec)
+		if err != nil {
+			return &blockedClientConn{spec: spec, err: err, requestHeader: make(http.Header)}
+		}
+		return &streamingClientConn{StreamingClientConn: next(ctx, spec), interceptor: i, entry: entry}
+	}
+}
+
+func (i *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		entry, err := i.entry(ctx, conn.Spec())
+		if err != nil {
+			return err
+		}
+		defer entry.Exit()
+
+		err = next(ctx, conn)
+		i.traceError(entry, err)
+		return err
+	}
+}
+
+// entry creates the Sentinel entry of the invocation, the returned error is the error of the block fallback
+// or the connect error of CodeResourceExhausted if the invocation is blocked.
+func (i *interceptor) entry(ctx context.Context, spec connect.Spec) (*base.SentinelEntry, error) {
+	// procedure as resource name by default
+	resourceName := spec.Procedure
+	if i.options.resourceExtract != nil {
+		resourceName = i.options.resourceExtract(ctx, spec)
+	}
+	trafficType := base.Inbound
+	if spec.IsClient {
+		trafficType = base.Outbound
+	}
+	entry, blockErr := sentinel.Entry(
+		resourceName,
+		sentinel.WithResourceType(base.ResTypeRPC),
+		sentinel.WithTrafficType(trafficType),
+	)
+	if blockErr != nil {
+		if i.options.blockFallback != nil {
+			if err := i.options.blockFallback(ctx, spec, blockErr); err != nil {
+				return nil, err
+			}
+		} else if _, err := fallback.Invoke(ctx, resourceName, blockErr); err != nil && err != error(blockErr) {
+			// consult the fallback registry by default, whose handler may replace the block error
+			return nil, err
+		}
+		return nil, NewBlockError(blockErr)
+	}
+	return entry, nil
+}
+
+// traceError records the connect code of the error to the entry, and traces it as an error if required.
+func (i *interceptor) traceError(entry *base.SentinelEntry, err error) {
+	if err == nil {
+		return
+	}
+	code := connect.CodeOf(err)
+	entry.SetPair(CodePairKey, code)
+	if i.options.isErrorCode(code) {
+		sentinel.TraceError(entry, err)
+	}
+}
+
+// NewBlockError converts the *base.BlockError to the connect error of CodeResourceExhausted,
+// with the block type recorded as the error metadata of BlockTypeMetaKey.
+func NewBlockError(blockErr *base.BlockError) *connect.Error {
+	err := connect.NewError(connect.CodeResourceExhausted, blockErr)
+	err.Meta().Set(BlockTypeMetaKey, blockErr.BlockType().String())
+	return err
+}
+
+// streamingClientConn exits the entry once the response is closed.
+type streamingClientConn struct {
+	connect.StreamingClientConn
+
+	interceptor *interceptor
+	entry       *base.SentinelEntry
+	once        sync.Once
+}
+
+func (c *streamingClientConn) Send(msg any) error {
+	err := c.StreamingClientConn.Send(msg)
+	if err != nil && !errors.Is(err, io.EOF) {
+		c.interceptor.traceError(c.entry, err)
+	}
+	return err
+}
+
+func (c *streamingClientConn) Receive(msg any) error {
+	err := c.StreamingClientConn.Receive(msg)
+	if err != nil && !errors.Is(err, io.EOF) {
+		c.interceptor.traceError(c.entry, err)
+	}
+	return err
+}
+
+func (c *streamingClientConn) CloseResponse() error {
+	err := c.StreamingClientConn.CloseResponse()
+	c.once.Do(func() {
+		c.entry.Exit()
+	})
+	return err
+}
+
+// blockedClientConn fails the blocked stream with the block error without sending the request.
+type blockedClientConn struct {
+	spec          connect.Spec
+	err           error
+	requestHeader http.Header
+}
+
+func (c *blockedClientConn) Spec() connect.Spec {
+	return c.spec
+}
+
+func (c *blockedClientConn) Peer() connect.Peer {
+	return connect.Peer{}
+}
+
+func (c *blockedClientConn) RequestHeader() http.Header {
+	return c.requestHeader
+}
+
+func (c *blockedClientConn) ResponseHeader() http.Header {
+	return http.Header{}
+}
+
+func (c *blockedClientConn) ResponseTrailer() http.Header {
+	return http.Header{}
+}
+
+func (c *blockedClientConn) Send(any) error {
+	return c.err
+}
+
+func (c *blockedClientConn) Receive(any) error {
+	return c.err
+}
+
+func (c *blockedClientConn) CloseRequest() error {
+	return nil
+}
+
+func (c *blockedClientConn) CloseResponse() error {
+	return nil
+}
@@ -0,0 +1,186 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	unaryProcedure  = "/sentinel.test.v1.EchoService/Echo"
+	streamProcedure = "/sentinel.test.v1.EchoService/EchoStream"
+)
+
+func initSentinel(t *testing.T) {
+	err := sentinel.InitDefault()
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+}
+
+func errorCountOf(resource string) int64 {
+	node := stat.GetResourceNode(resource)
+	if node == nil {
+		return 0
+	}
+	return node.GetSum(base.MetricEventError)
+}
+
+func loadRule(t *testing.T, resource string, threshold float64) {
+	_, err := flow.LoadRulesOfResource(resource, []*flow.Rule{
+		{
+			Resource:               resource,
+			Threshold:              threshold,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			StatIntervalInMs:       1000,
+		},
+	})
+	assert.Nil(t, err)
+}
+
+func newServer(opts ...connect.HandlerOption) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.Handle(unaryProcedure, connect.NewUnaryHandler(unaryProcedure,
+		func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+			switch req.Msg.Value {
+			case "internal":
+				return nil, connect.NewError(connect.CodeInternal, errors.New("oops"))
+			case "invalid":
+				return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid"))
+			}
+			return connect.NewResponse(req.Msg), nil
+		}, opts...))
+	mux.Handle(streamProcedure, connect.NewServerStreamHandler(streamProcedure,
+		func(ctx context.Context, req *connect.Request[wrapperspb.StringValue], stream *connect.ServerStream[wrapperspb.StringValue]) error {
+			if req.Msg.Value == "internal" {
+				return connect.NewError(connect.CodeInternal, errors.New("oops"))
+			}
+			return stream.Send(req.Msg)
+		}, opts...))
+	return httptest.NewServer(mux)
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	initSentinel(t)
+	defer func() {
+		_ = flow.ClearRules()
+	}()
+
+	t.Run("Handler", func(t *testing.T) {
+		srv := newServer(connect.WithInterceptors(NewInterceptor()))
+		defer srv.Close()
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+unaryProcedure)
+
+		loadRule(t, unaryProcedure, 2)
+		res, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("foo")))
+		assert.Nil(t, err)
+		assert.Equal(t, "foo", res.Msg.Value)
+		_, err = client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("invalid")))
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+		assert.Equal(t, int64(0), errorCountOf(unaryProcedure))
+
+		_, err = client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("foo")))
+		assert.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+		var connectErr *connect.Error
+		assert.True(t, errors.As(err, &connectErr))
+		assert.Equal(t, base.BlockTypeFlow.String(), connectErr.Meta().Get(BlockTypeMetaKey))
+
+		loadRule(t, unaryProcedure, 100)
+		_, err = client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("internal")))
+		assert.Equal(t, connect.CodeInternal, connect.CodeOf(err))
+		assert.Equal(t, int64(1), errorCountOf(unaryProcedure))
+	})
+
+	t.Run("Client", func(t *testing.T) {
+		srv := newServer()
+		defer srv.Close()
+		const resource = "client:" + unaryProcedure
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+unaryProcedure,
+			connect.WithInterceptors(NewInterceptor(
+				WithResourceExtractor(func(ctx context.Context, spec connect.Spec) string {
+					assert.True(t, spec.IsClient)
+					return "client:" + spec.Procedure
+				}),
+				WithBlockFallback(func(ctx context.Context, spec connect.Spec, blockErr *base.BlockError) error {
+					return connect.NewError(connect.CodeUnavailable, blockErr)
+				}),
+				WithErrorCodes(connect.CodeInvalidArgument),
+			)))
+
+		loadRule(t, resource, 1)
+		_, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("invalid")))
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+		assert.Equal(t, int64(1), errorCountOf(resource))
+
+		_, err = client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("foo")))
+		assert.Equal(t, connect.CodeUnavailable, connect.CodeOf(err))
+	})
+}
+
+func TestStreamingInterceptor(t *testing.T) {
+	initSentinel(t)
+	defer func() {
+		_ = flow.ClearRules()
+	}()
+
+	t.Run("Handler", func(t *testing.T) {
+		srv := newServer(connect.WithInterceptors(NewInterceptor()))
+		defer srv.Close()
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+streamProcedure)
+
+		loadRule(t, streamProcedure, 2)
+		stream, err := client.CallServerStream(context.Background(), connect.NewRequest(wrapperspb.String("foo")))
+		assert.Nil(t, err)
+		assert.True(t, stream.Receive())
+		assert.Equal(t, "foo", stream.Msg().Value)
+		assert.False(t, stream.Receive())
+		assert.Nil(t, stream.Err())
+		assert.Nil(t, stream.Close())
+
+		stream, err = client.CallServerStream(context.Background(), connect.NewRequest(wrapperspb.String("internal")))
+		assert.Nil(t, err)
+		assert.False(t, stream.Receive())
+		assert.Equal(t, connect.CodeInternal, connect.CodeOf(stream.Err()))
+		assert.Nil(t, stream.Close())
+		assert.Equal(t, int64(1), errorCountOf(streamProcedure))
+
+		stream, err = client.CallServerStream(context.Background(), connect.NewRequest(wrapperspb.String("foo")))
+		assert.Nil(t, err)
+		assert.False(t, stream.Receive())
+		assert.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(stream.Err()))
+		assert.Nil(t, stream.Close())
+	})
+
+	t.Run("Client", func(t *testing.T) {
+		srv := newServer()
+		defer srv.Close()
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+streamProcedure,
+			connect.WithInterceptors(NewInterceptor(WithResourceExtractor(func(ctx context.Context, spec connect.Spec) string {
+				return "client:" + spec.Procedure
+			}))))
+		const resource = "client:" + streamProcedure
+
+		loadRule(t, resource, 1)
+		stream, err := client.CallServerStream(context.Background(), connect.NewRequest(wrapperspb.String("internal")))
+		assert.Nil(t, err)
+		assert.False(t, stream.Receive())
+		assert.Equal(t, connect.CodeInternal, connect.CodeOf(stream.Err()))
+		assert.Nil(t, stream.Close())
+		assert.Equal(t, int64(1), errorCountOf(resource))
+		assert.Equal(t, int32(0), stat.GetResourceNode(resource).CurrentConcurrency())
+
+		_, err = client.CallServerStream(context.Background(), connect.NewRequest(wrapperspb.String("foo")))
+		assert.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+	})
+}
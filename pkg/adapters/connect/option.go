@@ -0,0 +1,72 @@
+package connect
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/Danceiny/sentinel-golang/core/base"
+)
+
+// CodePairKey is the key of the entry pair which records the connect code of the failed invocation.
+const CodePairKey = "sentinel.connect.code"
+
+type (
+	Option  func(*options)
+	options struct {
+		resourceExtract func(context.Context, connect.Spec) string
+		blockFallback   func(context.Context, connect.Spec, *base.BlockError) error
+		isErrorCode     func(connect.Code) bool
+	}
+)
+
+func evaluateOptions(opts []Option) *options {
+	optCopy := &options{
+		isErrorCode: isServerErrorCode,
+	}
+	for _, opt := range opts {
+		opt(optCopy)
+	}
+
+	return optCopy
+}
+
+// WithResourceExtractor sets the resource extractor of the invocation,
+// the procedure (e.g. "/acme.foo.v1.FooService/Bar") is the resource name by default.
+func WithResourceExtractor(fn func(context.Context, connect.Spec) string) Option {
+	return func(opts *options) {
+		opts.resourceExtract = fn
+	}
+}
+
+// WithBlockFallback sets the block fallback handler of the invocation,
+// the returned error is returned to the caller instead, while the default block error
+// (see NewBlockError) is returned if the handler returns nil.
+func WithBlockFallback(fn func(context.Context, connect.Spec, *base.BlockError) error) Option {
+	return func(opts *options) {
+		opts.blockFallback = fn
+	}
+}
+
+// WithErrorCodes sets the connect codes which are traced as errors,
+// CodeUnknown, CodeDeadlineExceeded, CodeInternal, CodeUnavailable and CodeDataLoss by default.
+func WithErrorCodes(codes ...connect.Code) Option {
+	codeSet := make(map[connect.Code]struct{}, len(codes))
+	for _, code := range codes {
+		codeSet[code] = struct{}{}
+	}
+	return func(opts *options) {
+		opts.isErrorCode = func(code connect.Code) bool {
+			_, ok := codeSet[code]
+			return ok
+		}
+	}
+}
+
+func isServerErrorCode(code connect.Code) bool {
+	switch code {
+	case connect.CodeUnknown, connect.CodeDeadlineExceeded, connect.CodeInternal, connect.CodeUnavailable, connect.CodeDataLoss:
+		return true
+	default:
+		return false
+	}
+}
@@ -1,16 +1,22 @@
 package echo
 
 import (
+	"errors"
 	"net/http"
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httpfallback"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
 	"github.com/labstack/echo/v4"
 )
 
 // SentinelMiddleware returns new echo.HandlerFunc.
 // Default resource name pattern is {httpMethod}:{apiPath}, such as "GET:/api/:id".
-// Default block fallback is to return 429 (Too Many Requests) response.
+// Default block fallback is to respond the result of the fallback handler registered for the resource
+// (see fallback.RegisterHandler), or to return 429 (Too Many Requests) response if there is none.
+// The returned error of 5xx status code (see WithErrorStatusCodes) and the panic are traced as errors,
+// and the response status code is recorded as the entry pair of StatusCodePairKey.
 //
 // You may customize your own resource extractor and block handler by setting options.
 func SentinelMiddleware(opts ...Option) echo.MiddlewareFunc {
@@ -29,6 +35,8 @@ func SentinelMiddleware(opts ...Option) echo.MiddlewareFunc {
 			if blockErr != nil {
 				if options.blockFallback != nil {
 					err = options.blockFallback(c)
+				} else if contentType, body, ok := httpfallback.Invoke(c.Request().Context(), resourceName, blockErr); ok {
+					err = c.Blob(http.StatusOK, contentType, body)
 				} else {
 					// default error response
 					err = c.JSON(http.StatusTooManyRequests, "Blocked by Sentinel")
@@ -36,10 +44,30 @@ func SentinelMiddleware(opts ...Option) echo.MiddlewareFunc {
 				return err
 			}
 			defer entry.Exit()
+			if options.tracePanic {
+				defer func() {
+					httptrace.TracePanic(entry, recover())
+				}()
+			}
 
 			err = next(c)
+			traceResult(entry, options, c, err)
 			return err
 		}
 
 	}
 }
+
+// traceResult records the status code to the entry, and traces the error if the status code is required to be traced.
+// The returned error is handled by the HTTPErrorHandler of echo later, so the status code is deduced from it.
+func traceResult(entry *base.SentinelEntry, options *options, c echo.Context, err error) {
+	status := c.Response().Status
+	if err != nil {
+		status = http.StatusInternalServerError
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			status = he.Code
+		}
+	}
+	httptrace.TraceStatusCode(entry, status, err, options.isErrorStatus)
+}
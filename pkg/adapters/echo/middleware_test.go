@@ -1,13 +1,17 @@
 package echo
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
 	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
@@ -131,3 +135,26 @@ func TestSentinelMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestSentinelMiddlewareTraceStatusCode(t *testing.T) {
+	initSentinel(t)
+
+	router := echo.New()
+	router.Use(SentinelMiddleware())
+	router.GET("/error", func(ctx echo.Context) error {
+		return errors.New("error")
+	})
+	router.GET("/wrapped_not_found", func(ctx echo.Context) error {
+		return fmt.Errorf("wrapped: %w", echo.ErrNotFound)
+	})
+	router.GET("/unavailable", func(ctx echo.Context) error {
+		return ctx.String(http.StatusServiceUnavailable, "unavailable")
+	})
+
+	for _, path := range []string{"/error", "/wrapped_not_found", "/unavailable"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	assert.Equal(t, int64(1), stat.GetResourceNode("GET:/error").GetSum(base.MetricEventError))
+	assert.Equal(t, int64(0), stat.GetResourceNode("GET:/wrapped_not_found").GetSum(base.MetricEventError))
+	assert.Equal(t, int64(1), stat.GetResourceNode("GET:/unavailable").GetSum(base.MetricEventError))
+}
@@ -1,19 +1,28 @@
 package echo
 
 import (
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
 	"github.com/labstack/echo/v4"
 )
 
+// StatusCodePairKey is the key of the entry pair which records the response status code.
+const StatusCodePairKey = httptrace.StatusCodePairKey
+
 type (
 	Option  func(*options)
 	options struct {
 		resourceExtract func(echo.Context) string
 		blockFallback   func(echo.Context) error
+		isErrorStatus   func(int) bool
+		tracePanic      bool
 	}
 )
 
 func evaluateOptions(opts []Option) *options {
-	optCopy := &options{}
+	optCopy := &options{
+		isErrorStatus: httptrace.IsServerErrorStatus,
+		tracePanic:    true,
+	}
 	for _, opt := range opts {
 		opt(optCopy)
 	}
@@ -34,3 +43,19 @@ func WithBlockFallback(fn func(ctx echo.Context) error) Option {
 		opts.blockFallback = fn
 	}
 }
+
+// WithErrorStatusCodes sets the response status codes which are traced as errors, 5xx by default.
+func WithErrorStatusCodes(codes ...int) Option {
+	isErrorStatus := httptrace.ErrorStatusCodes(codes...)
+	return func(opts *options) {
+		opts.isErrorStatus = isErrorStatus
+	}
+}
+
+// WithPanicTrace sets whether the panic of the handler is traced as an error, true by default.
+// The panic is re-panicked after being traced.
+func WithPanicTrace(enabled bool) Option {
+	return func(opts *options) {
+		opts.tracePanic = enabled
+	}
+}
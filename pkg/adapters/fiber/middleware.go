@@ -1,16 +1,22 @@
 package fiber
 
 import (
+	"errors"
 	"net/http"
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httpfallback"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
 	"github.com/gofiber/fiber/v2"
 )
 
 // SentinelMiddleware returns new gin.HandlerFunc
 // Default resource name is {method}:{path}, such as "GET:/api/users/:id"
-// Default block fallback is returning 429 code
+// Default block fallback is responding the result of the fallback handler registered for the resource
+// (see fallback.RegisterHandler), or returning 429 code if there is none
+// The returned error of 5xx status code (see WithErrorStatusCodes) and the panic are traced as errors,
+// and the response status code is recorded as the entry pair of StatusCodePairKey.
 // Define your own behavior by setting options
 func SentinelMiddleware(opts ...Option) fiber.Handler {
 	options := evaluateOptions(opts)
@@ -30,12 +36,37 @@ func SentinelMiddleware(opts ...Option) fiber.Handler {
 		if entryErr != nil {
 			if options.blockFallback != nil {
 				return options.blockFallback(ctx)
+			} else if contentType, body, ok := httpfallback.Invoke(ctx.UserContext(), resourceName, entryErr); ok {
+				ctx.Set(fiber.HeaderContentType, contentType)
+				return ctx.Status(http.StatusOK).Send(body)
 			} else {
 				return ctx.SendStatus(http.StatusTooManyRequests)
 			}
 		}
 
 		defer entry.Exit()
-		return ctx.Next()
+		if options.tracePanic {
+			defer func() {
+				httptrace.TracePanic(entry, recover())
+			}()
+		}
+
+		err := ctx.Next()
+		traceResult(entry, options, ctx, err)
+		return err
+	}
+}
+
+// traceResult records the status code to the entry, and traces the error if the status code is required to be traced.
+// The returned error is handled by the ErrorHandler of fiber later, so the status code is deduced from it.
+func traceResult(entry *base.SentinelEntry, options *options, ctx *fiber.Ctx, err error) {
+	status := ctx.Response().StatusCode()
+	if err != nil {
+		status = fiber.StatusInternalServerError
+		var fe *fiber.Error
+		if errors.As(err, &fe) {
+			status = fe.Code
+		}
 	}
+	httptrace.TraceStatusCode(entry, status, err, options.isErrorStatus)
 }
@@ -1,13 +1,17 @@
 package fiber
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
 	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 )
@@ -132,3 +136,27 @@ func TestSentinelMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestSentinelMiddlewareTraceStatusCode(t *testing.T) {
+	initSentinel(t)
+
+	app := fiber.New()
+	app.Use(SentinelMiddleware())
+	app.Get("/error", func(ctx *fiber.Ctx) error {
+		return errors.New("error")
+	})
+	app.Get("/wrapped_not_found", func(ctx *fiber.Ctx) error {
+		return fmt.Errorf("wrapped: %w", fiber.ErrNotFound)
+	})
+	app.Get("/unavailable", func(ctx *fiber.Ctx) error {
+		return ctx.SendStatus(http.StatusServiceUnavailable)
+	})
+
+	for _, path := range []string{"/error", "/wrapped_not_found", "/unavailable"} {
+		_, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, int64(1), stat.GetResourceNode("GET:/error").GetSum(base.MetricEventError))
+	assert.Equal(t, int64(0), stat.GetResourceNode("GET:/wrapped_not_found").GetSum(base.MetricEventError))
+	assert.Equal(t, int64(1), stat.GetResourceNode("GET:/unavailable").GetSum(base.MetricEventError))
+}
@@ -16,6 +16,9 @@ The plugear extracts "HttpMethod:Router" as the resource name by default (e.g. G
 Users may provide customized resource name extractor when creating new
 SentinelMiddleware (via options).
 
+Error tracing: the response of 5xx status code (including the recovered panic of the handler)
+is traced as an error by default, see package httptrace. Users may customize the status codes via WithErrorStatusCodes option.
+
 Fallback logic: the plugear will return "429 Too Many Requests" status code
 if current request is blocked by Sentinel rules. Users may also
 provide customized fallback logic via WithBlockFallback(handler) options.
@@ -5,12 +5,18 @@ import (
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httpfallback"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
 	"github.com/teambition/gear"
 )
 
 // SentinelMiddleware returns new gear.Middleware
 // Default resource name is {method}:{path}, such as "GET:/api/users/:id"
-// Default block fallback is returning 429 code
+// Default block fallback is responding the result of the fallback handler registered for the resource
+// (see fallback.RegisterHandler), or returning 429 code if there is none
+// The response of 5xx status code (see WithErrorStatusCodes) is traced as an error,
+// including the panic of the handler which is recovered and responded with 500 by gear,
+// and the response status code is recorded as the entry pair of StatusCodePairKey.
 // Define your own behavior by setting options
 func SentinelMiddleware(opts ...Option) gear.Middleware {
 	options := evaluateOptions(opts)
@@ -30,13 +36,21 @@ func SentinelMiddleware(opts ...Option) gear.Middleware {
 		if blockErr != nil {
 			if options.blockFallback != nil {
 				err = options.blockFallback(ctx)
+			} else if contentType, body, ok := httpfallback.Invoke(ctx, resourceName, blockErr); ok {
+				ctx.SetHeader(gear.HeaderContentType, contentType)
+				err = ctx.End(http.StatusOK, body)
 			} else {
 				err = ctx.End(http.StatusTooManyRequests, []byte("Blocked by Sentinel"))
 			}
 			return err
 		}
 
-		defer entry.Exit()
+		// The gear middlewares run one after another rather than wrapping the next one, so the entry exits
+		// in the end hook, which gear runs once the request context is done, even if the handler panics.
+		ctx.OnEnd(func() {
+			httptrace.TraceStatusCode(entry, ctx.Res.Status(), nil, options.isErrorStatus)
+			entry.Exit()
+		})
 		return err
 	}
 }
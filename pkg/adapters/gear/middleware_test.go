@@ -5,9 +5,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
 	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/stretchr/testify/assert"
 	"github.com/teambition/gear"
 )
@@ -133,3 +136,23 @@ func TestSentinelMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestSentinelMiddlewareTraceStatusCode(t *testing.T) {
+	initSentinel(t)
+
+	app := gear.New()
+	router := gear.NewRouter()
+	router.Use(SentinelMiddleware())
+	router.Get("/error", func(ctx *gear.Context) error {
+		return ctx.End(http.StatusInternalServerError, []byte("error"))
+	})
+	app.UseHandler(router)
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/error", nil))
+	// the entry exits in the end hook, which runs asynchronously once the request is done
+	assert.Eventually(t, func() bool {
+		node := stat.GetResourceNode("GET:/error")
+		return node != nil && node.GetSum(base.MetricEventComplete) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(1), stat.GetResourceNode("GET:/error").GetSum(base.MetricEventError))
+}
@@ -1,19 +1,26 @@
 package gear
 
 import (
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
 	"github.com/teambition/gear"
 )
 
+// StatusCodePairKey is the key of the entry pair which records the response status code.
+const StatusCodePairKey = httptrace.StatusCodePairKey
+
 type (
 	Option  func(*options)
 	options struct {
 		resourceExtract func(*gear.Context) string
 		blockFallback   func(*gear.Context) error
+		isErrorStatus   func(int) bool
 	}
 )
 
 func evaluateOptions(opts []Option) *options {
-	optCopy := &options{}
+	optCopy := &options{
+		isErrorStatus: httptrace.IsServerErrorStatus,
+	}
 	for _, opt := range opts {
 		opt(optCopy)
 	}
@@ -34,3 +41,11 @@ func WithBlockFallback(fn func(ctx *gear.Context) error) Option {
 		opts.blockFallback = fn
 	}
 }
+
+// WithErrorStatusCodes sets the response status codes which are traced as errors, 5xx by default.
+func WithErrorStatusCodes(codes ...int) Option {
+	isErrorStatus := httptrace.ErrorStatusCodes(codes...)
+	return func(opts *options) {
+		opts.isErrorStatus = isErrorStatus
+	}
+}
@@ -15,6 +15,18 @@ The plugin extracts "HttpMethod:FullPath" as the resource name by default (e.g.
 Users may provide customized resource name extractor when creating new
 SentinelMiddleware (via options).
 
+Per-route rules: users may declare the flow and isolation rules next to the route registration
+via the Limit handler, like.
+
+	r.GET("/foo/:id", sentinelPlugin.Limit("GET", "/foo/:id",
+		routerule.WithFlowRules(&flow.Rule{Threshold: 100, StatIntervalInMs: 1000})), handler)
+
+The rules are registered once for the resource of the route (e.g. GET:/foo/:id), and the rules
+of the same resource loaded from the datasource take precedence over them.
+
+Error tracing: the response of 5xx status code and the panic of the handler are traced as errors by default,
+see package httptrace. Users may customize the behavior via WithErrorStatusCodes and WithPanicTrace options.
+
 Fallback logic: the plugin will return "429 Too Many Requests" status code
 if current request is blocked by Sentinel rules. Users may also
 provide customized fallback logic via WithBlockFallback(handler) options.
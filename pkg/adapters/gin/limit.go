@@ -0,0 +1,40 @@
+package gin
+
+import (
+	"sync"
+
+	"github.com/Danceiny/sentinel-golang/logging"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/routerule"
+	"github.com/gin-gonic/gin"
+)
+
+// Limit returns the per-route gin.HandlerFunc which declares the flow and isolation rules of the route
+// of the given method and full path, the rules are declared by routerule.WithFlowRules and
+// routerule.WithIsolationRules, like.
+//
+//	r.GET("/api/users/:id", sentinelPlugin.Limit(http.MethodGet, "/api/users/:id",
+//		routerule.WithFlowRules(&flow.Rule{Threshold: 100, StatIntervalInMs: 1000})), getUser)
+//
+// The rules with empty resource are bound to the resource of the route, which is {method}:{path},
+// the same as the default resource of SentinelMiddleware, such as "GET:/api/users/:id".
+// The path must be the full path of the route, including the prefix of the router group.
+// The rules are registered once when Limit is called, as the default rules (see flow.RegisterDefaultRules),
+// so the rules of the same resource loaded from the datasource win, and the conflicts with the rules
+// declared by other routes are logged.
+// NOTICE: Limit only declares the rules, the route must be guarded by SentinelMiddleware as well.
+func Limit(method, path string, opts ...routerule.Option) gin.HandlerFunc {
+	resource := method + ":" + path
+	if err := routerule.Register(resource, opts...); err != nil {
+		logging.Warn("[Sentinel] Failed to register the rules of the route", "resource", resource, "reason", err.Error())
+	}
+	mismatchOnce := sync.Once{}
+	return func(c *gin.Context) {
+		if routeResource := c.Request.Method + ":" + c.FullPath(); routeResource != resource {
+			mismatchOnce.Do(func() {
+				logging.Warn("[Sentinel] The rules declared by Limit don't match the resource of the route",
+					"declaredResource", resource, "routeResource", routeResource)
+			})
+		}
+		c.Next()
+	}
+}
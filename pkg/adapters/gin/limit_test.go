@@ -0,0 +1,52 @@
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/isolation"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/routerule"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimit(t *testing.T) {
+	initSentinel(t)
+	defer func() {
+		flow.ClearDefaultRules()
+		isolation.ClearDefaultRules()
+		_ = flow.ClearRules()
+	}()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(SentinelMiddleware())
+	api := router.Group("/api")
+	api.GET("/articles/:slug", Limit(http.MethodGet, "/api/articles/:slug",
+		routerule.WithFlowRules(&flow.Rule{
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			Threshold:              2,
+			StatIntervalInMs:       1000,
+		}),
+		routerule.WithIsolationRules(&isolation.Rule{MetricType: isolation.Concurrency, Threshold: 10}),
+	), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	// the rules are registered on declaration
+	const resource = "GET:/api/articles/:slug"
+	assert.Equal(t, 1, len(flow.GetRulesOfResource(resource)))
+	assert.Equal(t, 1, len(isolation.GetRulesOfResource(resource)))
+
+	for _, slug := range []string{"foo", "bar"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles/"+slug, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles/baz", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
@@ -5,12 +5,17 @@ import (
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httpfallback"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
 	"github.com/gin-gonic/gin"
 )
 
 // SentinelMiddleware returns new gin.HandlerFunc
 // Default resource name is {method}:{path}, such as "GET:/api/users/:id"
-// Default block fallback is returning 429 code
+// Default block fallback is responding the result of the fallback handler registered for the resource
+// (see fallback.RegisterHandler), or returning 429 code if there is none
+// The response of 5xx status code (see WithErrorStatusCodes) and the panic are traced as errors,
+// and the response status code is recorded as the entry pair of StatusCodePairKey.
 // Define your own behavior by setting options
 func SentinelMiddleware(opts ...Option) gin.HandlerFunc {
 	options := evaluateOptions(opts)
@@ -30,6 +35,9 @@ func SentinelMiddleware(opts ...Option) gin.HandlerFunc {
 		if err != nil {
 			if options.blockFallback != nil {
 				options.blockFallback(c)
+			} else if contentType, body, ok := httpfallback.Invoke(c.Request.Context(), resourceName, err); ok {
+				c.Data(http.StatusOK, contentType, body)
+				c.Abort()
 			} else {
 				c.AbortWithStatus(http.StatusTooManyRequests)
 			}
@@ -37,6 +45,12 @@ func SentinelMiddleware(opts ...Option) gin.HandlerFunc {
 		}
 
 		defer entry.Exit()
+		if options.tracePanic {
+			defer func() {
+				httptrace.TracePanic(entry, recover())
+			}()
+		}
 		c.Next()
+		httptrace.TraceStatusCode(entry, c.Writer.Status(), nil, options.isErrorStatus)
 	}
 }
@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
 	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -131,3 +133,16 @@ func TestSentinelMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestSentinelMiddlewareTraceStatusCode(t *testing.T) {
+	initSentinel(t)
+
+	router := gin.New()
+	router.Use(SentinelMiddleware())
+	router.GET("/error", func(ctx *gin.Context) {
+		ctx.String(http.StatusInternalServerError, "error")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/error", nil))
+	assert.Equal(t, int64(1), stat.GetResourceNode("GET:/error").GetSum(base.MetricEventError))
+}
@@ -19,6 +19,9 @@ The plugin extracts service FullMethod as the resource name by default.
 Users may provide customized resource name extractor when creating new
 Sentinel interceptors (via options).
 
+Error tracing: the response of 5xx status code and the panic of the handler are traced as errors by default,
+see package httptrace. Users may customize the behavior via WithErrorStatusCodes and WithPanicTrace options.
+
 Fallback logic: the plugin will return the BlockError by default
 if current request is blocked by Sentinel rules. Users may also
 provide customized fallback logic via WithXxxBlockFallback(handler) options.
@@ -5,12 +5,17 @@ import (
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httpfallback"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
 	"github.com/zeromicro/go-zero/rest"
 )
 
 // SentinelMiddleware returns new echo.HandlerFunc.
 // Default resource name pattern is {httpMethod}:{apiPath}, such as "GET:/api/:id".
-// Default block fallback is to return 429 (Too Many Requests) response.
+// Default block fallback is to respond the result of the fallback handler registered for the resource
+// (see fallback.RegisterHandler), or to return 429 (Too Many Requests) response if there is none.
+// The response of 5xx status code (see WithErrorStatusCodes) and the panic are traced as errors,
+// and the response status code is recorded as the entry pair of StatusCodePairKey.
 //
 // You may customize your own resource extractor and block handler by setting options.
 func SentinelMiddleware(opts ...Option) rest.Middleware {
@@ -30,6 +35,8 @@ func SentinelMiddleware(opts ...Option) rest.Middleware {
 				if options.blockFallback != nil {
 					status, msg := options.blockFallback(r)
 					http.Error(w, msg, status)
+				} else if contentType, body, ok := httpfallback.Invoke(r.Context(), resourceName, blockErr); ok {
+					writeFallbackResponse(w, contentType, body)
 				} else {
 					// default error response
 					http.Error(w, "Blocked by Sentinel", http.StatusTooManyRequests)
@@ -37,8 +44,49 @@ func SentinelMiddleware(opts ...Option) rest.Middleware {
 				return
 			}
 			defer entry.Exit()
+			if options.tracePanic {
+				defer func() {
+					httptrace.TracePanic(entry, recover())
+				}()
+			}
 
-			next(w, r)
+			sw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+			next(sw, r)
+			httptrace.TraceStatusCode(entry, sw.status, nil, options.isErrorStatus)
 		}
 	}
 }
+
+// statusResponseWriter records the status code written to the http.ResponseWriter.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// writeFallbackResponse responds the result of the fallback handler with http.StatusOK.
+func writeFallbackResponse(w http.ResponseWriter, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
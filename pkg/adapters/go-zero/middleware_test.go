@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -13,7 +14,9 @@ import (
 	"github.com/zeromicro/go-zero/rest/httpx"
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
 	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -356,6 +359,16 @@ func TestSentinelMiddlewareRouting(t *testing.T) {
 
 }
 
+func TestSentinelMiddlewareTraceStatusCode(t *testing.T) {
+	initSentinel(t)
+
+	handler := SentinelMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/error", nil))
+	assert.Equal(t, int64(1), stat.GetResourceNode("GET:/error").GetSum(base.MetricEventError))
+}
+
 func getAvailablePort(init int) (int, error) {
 	for p := init; p < 65536; p++ {
 		conn, _ := net.DialTimeout("tcp", net.JoinHostPort("", fmt.Sprint(p)), time.Second)
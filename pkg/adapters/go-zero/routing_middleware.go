@@ -20,6 +20,7 @@ import (
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httpfallback"
 )
 
 type SentinelRouteMiddleware struct {
@@ -38,7 +39,11 @@ func (m *SentinelRouteMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc
 			sentinel.WithTrafficType(base.Inbound),
 		)
 		if blockErr != nil {
-			http.Error(w, "Blocked by Sentinel", http.StatusTooManyRequests)
+			if contentType, body, ok := httpfallback.Invoke(r.Context(), resourceName, blockErr); ok {
+				writeFallbackResponse(w, contentType, body)
+			} else {
+				http.Error(w, "Blocked by Sentinel", http.StatusTooManyRequests)
+			}
 			return
 		}
 		defer entry.Exit()
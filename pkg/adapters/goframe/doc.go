@@ -14,6 +14,9 @@
 // The plugin extracts "HttpMethod:FullPath" as the resource name by default (e.g. GET:/foo/:id).
 // Users may provide a customized resource name extractor when creating new SentinelMiddleware (via options).
 //
+// Error tracing: the response of 5xx status code, the error and the panic of the handler are traced as errors by default,
+// see package httptrace. Users may customize the behavior via WithErrorStatusCodes and WithPanicTrace options.
+//
 // Fallback logic: the plugin will return "429 Too Many Requests" status code if the current request is blocked by Sentinel rules.
 // Users may also provide customized fallback logic via WithBlockFallback(handler) options.
 package goframe
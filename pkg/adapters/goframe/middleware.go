@@ -1,15 +1,21 @@
 package goframe
 
 import (
+	"net/http"
+
 	"github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httpfallback"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
 	"github.com/gogf/gf/v2/net/ghttp"
-	"net/http"
 )
 
 // SentinelMiddleware returns new ghttp.HandlerFunc
 // Default resource name is {method}:{path}, such as "GET:/api/users/:id"
-// Default block fallback is returning 429 status code
+// Default block fallback is responding the result of the fallback handler registered for the resource
+// (see fallback.RegisterHandler), or returning 429 status code if there is none
+// The response of 5xx status code (see WithErrorStatusCodes), the error and the panic of the handler are traced as errors,
+// and the response status code is recorded as the entry pair of StatusCodePairKey.
 // Define your own behavior by setting options
 func SentinelMiddleware(opts ...Option) ghttp.HandlerFunc {
 	options := evaluateOptions(opts)
@@ -29,6 +35,10 @@ func SentinelMiddleware(opts ...Option) ghttp.HandlerFunc {
 		if err != nil {
 			if options.blockFallback != nil {
 				options.blockFallback(r)
+			} else if contentType, body, ok := httpfallback.Invoke(r.Context(), resourceName, err); ok {
+				r.Response.Header().Set("Content-Type", contentType)
+				r.Response.WriteHeader(http.StatusOK)
+				r.Response.Write(body)
 			} else {
 				r.Response.WriteHeader(http.StatusTooManyRequests)
 				r.Response.Writeln("Too Many Requests")
@@ -37,7 +47,27 @@ func SentinelMiddleware(opts ...Option) ghttp.HandlerFunc {
 		}
 
 		defer entry.Exit()
+		if options.tracePanic {
+			defer func() {
+				httptrace.TracePanic(entry, recover())
+			}()
+		}
 
 		r.Middleware.Next()
+		traceResult(entry, options, r)
+	}
+}
+
+// traceResult records the status code to the entry, and traces the error if the status code is required to be traced.
+// The error (including the recovered panic) of the handler is responded with 500 status code by goframe later.
+func traceResult(entry *base.SentinelEntry, options *options, r *ghttp.Request) {
+	status := r.Response.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	err := r.GetError()
+	if err != nil && status < http.StatusInternalServerError {
+		status = http.StatusInternalServerError
 	}
+	httptrace.TraceStatusCode(entry, status, err, options.isErrorStatus)
 }
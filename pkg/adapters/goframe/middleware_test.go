@@ -2,7 +2,9 @@ package goframe
 
 import (
 	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
 	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/stretchr/testify/assert"
@@ -233,3 +235,21 @@ func TestSentinelMiddlewareFallback(t *testing.T) {
 		})
 	}
 }
+
+// go test -run ^TestSentinelMiddlewareTraceError -v
+func TestSentinelMiddlewareTraceStatusCode(t *testing.T) {
+	initSentinel(t)
+
+	s := g.Server()
+	s.SetRouteOverWrite(true)
+	s.Group("/", func(group *ghttp.RouterGroup) {
+		group.Middleware(SentinelMiddleware())
+		group.ALL("/error", func(r *ghttp.Request) {
+			r.Response.WriteStatus(http.StatusInternalServerError, "error")
+		})
+	})
+	s.Start()
+
+	s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/error", nil))
+	assert.Equal(t, int64(1), stat.GetResourceNode("GET:/error").GetSum(base.MetricEventError))
+}
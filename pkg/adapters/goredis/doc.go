@@ -0,0 +1,33 @@
+/*
+This package provides Sentinel integration for go-redis.
+
+Users may add the Sentinel hook to the redis client, like.
+
+	import (
+		sentinelPlugin "github.com/Danceiny/sentinel-golang/pkg/adapters/goredis"
+		"github.com/redis/go-redis/v9"
+	)
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	client.AddHook(sentinelPlugin.NewHook())
+
+Each command executed by the client creates an outbound entry of base.ResTypeCache.
+The plugin names the resource by the upper cased command by default (e.g. "GET").
+Users may name the resource by the command and the key pattern (e.g. "GET:user:*")
+via WithResourceNaming(ByKeyPattern) option, or provide customized resource name
+extractor via WithResourceExtractor option.
+
+The first key of the command is passed to the entry as the args of hotspot parameter flow control,
+so that the hot keys could be limited by the hotspot rules with ParamIndex 0,
+which could be disabled via WithoutArgs option. The key position depends on the command,
+e.g. the first key following numkeys of EVAL and the key following the subcommand of OBJECT ENCODING,
+and the commands without key (e.g. PING and PUBLISH) pass no args. The redis errors except redis.Nil are traced as errors.
+
+The commands of the pipeline (or the transaction) sharing the same resource and key are guarded
+by one entry with the batch count of the commands. The whole pipeline is blocked once any of the entries is blocked.
+
+Fallback logic: the plugin will set the *base.BlockError as the error of the command by default
+if current command is blocked by Sentinel rules. Users may also
+provide customized fallback logic via WithBlockFallback(handler) options.
+*/
+package goredis
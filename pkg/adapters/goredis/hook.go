@@ -0,0 +1,150 @@
+package goredis
+
+import (
+	"context"
+	"net"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/redis/go-redis/v9"
+)
+
+type hook struct {
+	options *options
+}
+
+// NewHook returns new redis.Hook which guards the commands with Sentinel entries, like.
+//
+//	client := redis.NewClient(&redis.Options{Addr: addr})
+//	client.AddHook(NewHook())
+func NewHook(opts ...Option) redis.Hook {
+	return &hook{options: evaluateOptions(opts)}
+}
+
+func (h *hook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h *hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		key, hasKey := keyOf(cmd)
+		entry, blockErr := h.options.entry(h.options.resourceNameOf(ctx, cmd), key, hasKey, 1)
+		if blockErr != nil {
+			return h.options.fallback(ctx, cmd, blockErr)
+		}
+		defer entry.Exit()
+
+		err := next(ctx, cmd)
+		traceError(entry, err)
+		return err
+	}
+}
+
+// batch is the commands of the pipeline which share the same resource and key.
+type batch struct {
+	resource string
+	key      string
+	hasKey   bool
+	cmds     []redis.Cmder
+	entry    *base.SentinelEntry
+}
+
+func (h *hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		batches := h.batchesOf(ctx, cmds)
+		for i, b := range batches {
+			entry, blockErr := h.options.entry(b.resource, b.key, b.hasKey, uint32(len(b.cmds)))
+			if blockErr != nil {
+				for _, acquired := range batches[:i] {
+					acquired.entry.Exit()
+				}
+				// the whole pipeline is blocked once any batch of it is blocked
+				var err error
+				for _, cmd := range cmds {
+					if cmdErr := h.options.fallback(ctx, cmd, blockErr); err == nil {
+						err = cmdErr
+					}
+				}
+				return err
+			}
+			b.entry = entry
+		}
+
+		err := next(ctx, cmds)
+		for _, b := range batches {
+			for _, cmd := range b.cmds {
+				if cmdErr := cmd.Err(); cmdErr != nil && cmdErr != redis.Nil {
+					traceError(b.entry, cmdErr)
+					break
+				}
+			}
+			b.entry.Exit()
+		}
+		return err
+	}
+}
+
+// batchesOf groups the commands of the pipeline by the resource and the key in order,
+// the MULTI and EXEC commands of the transaction are omitted.
+func (h *hook) batchesOf(ctx context.Context, cmds []redis.Cmder) []*batch {
+	type batchKey struct {
+		resource string
+		key      string
+	}
+	batches := make([]*batch, 0, len(cmds))
+	indexes := make(map[batchKey]*batch, len(cmds))
+	for _, cmd := range cmds {
+		if name := cmd.Name(); name == "multi" || name == "exec" {
+			continue
+		}
+		resource := h.options.resourceNameOf(ctx, cmd)
+		key, hasKey := keyOf(cmd)
+		if h.options.disableArgs {
+			key, hasKey = "", false
+		}
+		bk := batchKey{resource: resource, key: key}
+		b, ok := indexes[bk]
+		if !ok {
+			b = &batch{resource: resource, key: key, hasKey: hasKey}
+			indexes[bk] = b
+			batches = append(batches, b)
+		}
+		b.cmds = append(b.cmds, cmd)
+	}
+	return batches
+}
+
+// entry creates the outbound entry of the command.
+func (o *options) entry(resource, key string, hasKey bool, batchCount uint32) (*base.SentinelEntry, *base.BlockError) {
+	entryOpts := []sentinel.EntryOption{
+		sentinel.WithResourceType(base.ResTypeCache),
+		sentinel.WithTrafficType(base.Outbound),
+		sentinel.WithBatchCount(batchCount),
+	}
+	if !o.disableArgs && hasKey {
+		entryOpts = append(entryOpts, sentinel.WithArgs(key))
+	}
+	return sentinel.Entry(resource, entryOpts...)
+}
+
+// fallback sets the error of the blocked command and returns it, the command is skipped
+// without error if the block fallback returns nil.
+func (o *options) fallback(ctx context.Context, cmd redis.Cmder, blockErr *base.BlockError) error {
+	var err error = blockErr
+	if o.blockFallback != nil {
+		err = o.blockFallback(ctx, cmd, blockErr)
+	}
+	if err != nil {
+		cmd.SetErr(err)
+	}
+	return err
+}
+
+// traceError traces the redis error, except redis.Nil which indicates the absence of the key.
+func traceError(entry *base.SentinelEntry, err error) {
+	if err != nil && err != redis.Nil {
+		sentinel.TraceError(entry, err)
+	}
+}
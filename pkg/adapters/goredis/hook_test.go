@@ -0,0 +1,190 @@
+package goredis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/hotspot"
+	"github.com/Danceiny/sentinel-golang/core/stat"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func initSentinel(t *testing.T) {
+	err := sentinel.InitDefault()
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	_, err = flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "DEL",
+			Threshold:              0,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+		},
+		{
+			Resource:               "LPUSH",
+			Threshold:              2,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	_, err = hotspot.LoadRules([]*hotspot.Rule{
+		{
+			Resource:        "HGET:user:*",
+			MetricType:      hotspot.QPS,
+			ControlBehavior: hotspot.Reject,
+			ParamIndex:      0,
+			Threshold:       100,
+			DurationInSec:   1,
+			SpecificItems:   map[interface{}]int64{"user:1": 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+}
+
+func newClient(t *testing.T, opts ...Option) *redis.Client {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	client.AddHook(NewHook(opts...))
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return client
+}
+
+func TestKeyPattern(t *testing.T) {
+	assert.Equal(t, "user:*:profile", KeyPattern("user:1024:profile"))
+	assert.Equal(t, "order:*", KeyPattern("order:a1b2"))
+	assert.Equal(t, "config", KeyPattern("config"))
+}
+
+func TestKeyOf(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		args    []interface{}
+		wantKey string
+		wantOk  bool
+	}{
+		{[]interface{}{"get", "user:1"}, "user:1", true},
+		{[]interface{}{"eval", "return 1", 2, "k1", "k2", "arg"}, "k1", true},
+		{[]interface{}{"evalsha", "sha", 0, "arg"}, "", false},
+		{[]interface{}{"fcall", "fn", "1", "k1"}, "k1", true},
+		{[]interface{}{"object", "encoding", "k1"}, "k1", true},
+		{[]interface{}{"object", "help"}, "", false},
+		{[]interface{}{"memory", "usage", "k1"}, "k1", true},
+		{[]interface{}{"zunion", 2, "z1", "z2"}, "z1", true},
+		{[]interface{}{"blmpop", 1, 1, "l1", "left"}, "l1", true},
+		{[]interface{}{"bitop", "and", "dest", "k1"}, "dest", true},
+		{[]interface{}{"xread", "count", 1, "streams", "s1", "0"}, "s1", true},
+		{[]interface{}{"ping"}, "", false},
+		{[]interface{}{"publish", "channel", "msg"}, "", false},
+		{[]interface{}{"scan", 0}, "", false},
+	}
+	for _, tt := range tests {
+		key, ok := keyOf(redis.NewCmd(ctx, tt.args...))
+		assert.Equal(t, tt.wantOk, ok, tt.args)
+		assert.Equal(t, tt.wantKey, key, tt.args)
+	}
+}
+
+func TestNewHook(t *testing.T) {
+	initSentinel(t)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		client := newClient(t)
+		assert.Nil(t, client.Set(ctx, "foo", "bar", 0).Err())
+		v, err := client.Get(ctx, "foo").Result()
+		assert.Nil(t, err)
+		assert.Equal(t, "bar", v)
+		assert.Equal(t, int64(1), stat.GetResourceNode("SET").GetSum(base.MetricEventPass))
+		assert.Equal(t, int64(1), stat.GetResourceNode("GET").GetSum(base.MetricEventPass))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		client := newClient(t)
+		assert.Equal(t, redis.Nil, client.Get(ctx, "absent").Err())
+		assert.Equal(t, int64(0), stat.GetResourceNode("GET").GetSum(base.MetricEventError))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		client := newClient(t)
+		assert.Nil(t, client.Set(ctx, "str", "bar", 0).Err())
+		assert.NotNil(t, client.Incr(ctx, "str").Err())
+		assert.Equal(t, int64(1), stat.GetResourceNode("INCR").GetSum(base.MetricEventError))
+	})
+
+	t.Run("block", func(t *testing.T) {
+		client := newClient(t)
+		err := client.Del(ctx, "foo").Err()
+		assert.IsType(t, &base.BlockError{}, err)
+	})
+
+	t.Run("block fallback", func(t *testing.T) {
+		errFallback := errors.New("fallback")
+		client := newClient(t, WithBlockFallback(func(ctx context.Context, cmd redis.Cmder, blockErr *base.BlockError) error {
+			return errFallback
+		}))
+		assert.Equal(t, errFallback, client.Del(ctx, "foo").Err())
+	})
+
+	t.Run("hot key", func(t *testing.T) {
+		client := newClient(t, WithResourceNaming(ByKeyPattern))
+		assert.Nil(t, client.HSet(ctx, "user:1", "name", "a").Err())
+		assert.Nil(t, client.HSet(ctx, "user:2", "name", "b").Err())
+		for i := 0; i < 3; i++ {
+			assert.Nil(t, client.HGet(ctx, "user:2", "name").Err())
+		}
+		assert.Nil(t, client.HGet(ctx, "user:1", "name").Err())
+		assert.IsType(t, &base.BlockError{}, client.HGet(ctx, "user:1", "name").Err())
+	})
+}
+
+func TestNewHook_Pipeline(t *testing.T) {
+	initSentinel(t)
+	ctx := context.Background()
+
+	t.Run("batch", func(t *testing.T) {
+		client := newClient(t)
+		cmds, err := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SAdd(ctx, "set", "a")
+			pipe.SAdd(ctx, "set", "b")
+			pipe.SAdd(ctx, "set", "c")
+			pipe.SCard(ctx, "set")
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), cmds[3].(*redis.IntCmd).Val())
+		assert.Equal(t, int64(3), stat.GetResourceNode("SADD").GetSum(base.MetricEventPass))
+		assert.Equal(t, int64(1), stat.GetResourceNode("SCARD").GetSum(base.MetricEventPass))
+		assert.Nil(t, stat.GetResourceNode("MULTI"))
+		assert.Nil(t, stat.GetResourceNode("EXEC"))
+	})
+
+	t.Run("block", func(t *testing.T) {
+		client := newClient(t)
+		cmds, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, "foo", "bar", 0)
+			pipe.LPush(ctx, "list", "a")
+			pipe.LPush(ctx, "list", "b")
+			pipe.LPush(ctx, "list", "c")
+			return nil
+		})
+		assert.IsType(t, &base.BlockError{}, err)
+		for _, cmd := range cmds {
+			assert.IsType(t, &base.BlockError{}, cmd.Err())
+		}
+		assert.Equal(t, int64(0), client.Exists(ctx, "foo").Val())
+	})
+}
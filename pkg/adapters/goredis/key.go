@@ -0,0 +1,122 @@
+package goredis
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyPattern returns the pattern of the key: the segments separated by ":" which contain digits
+// are replaced with "*", e.g. "user:1024:profile" turns into "user:*:profile".
+func KeyPattern(key string) string {
+	segments := strings.Split(key, ":")
+	for i, segment := range segments {
+		if strings.IndexFunc(segment, unicode.IsDigit) >= 0 {
+			segments[i] = "*"
+		}
+	}
+	return strings.Join(segments, ":")
+}
+
+var (
+	// keylessCommands are the commands without key, or whose first argument is not a key, e.g. the channel of PUBLISH.
+	keylessCommands = toSet(
+		"acl", "auth", "bgrewriteaof", "bgsave", "client", "cluster", "command", "config", "dbsize", "debug",
+		"discard", "echo", "exec", "failover", "flushall", "flushdb", "function", "hello", "info", "keys",
+		"lastsave", "latency", "lolwut", "module", "monitor", "multi", "ping", "psubscribe", "psync", "publish",
+		"pubsub", "punsubscribe", "quit", "randomkey", "readonly", "readwrite", "replicaof", "reset", "role",
+		"save", "scan", "script", "select", "shutdown", "slaveof", "slowlog", "spublish", "ssubscribe",
+		"subscribe", "sunsubscribe", "swapdb", "sync", "time", "unsubscribe", "unwatch", "wait", "waitaof",
+	)
+	// keySubcommands are the subcommands of the container commands which take the key following the subcommand,
+	// e.g. OBJECT ENCODING key, the other subcommands (e.g. OBJECT HELP) have no key.
+	keySubcommands = map[string]map[string]struct{}{
+		"object": toSet("encoding", "freq", "idletime", "refcount"),
+		"memory": toSet("usage"),
+		"xinfo":  toSet("consumers", "groups", "stream"),
+	}
+	// numKeysPositions are the positions of the numkeys argument of the commands,
+	// which is followed by the keys, e.g. EVAL script numkeys key [key ...] arg [arg ...].
+	numKeysPositions = map[string]int{
+		"eval": 2, "eval_ro": 2, "evalsha": 2, "evalsha_ro": 2, "fcall": 2, "fcall_ro": 2,
+		"lmpop": 1, "sintercard": 1, "zdiff": 1, "zinter": 1, "zintercard": 1, "zmpop": 1, "zunion": 1,
+		"blmpop": 2, "bzmpop": 2,
+	}
+)
+
+// keyOf returns the first key of the command, false if the command has no key.
+func keyOf(cmd redis.Cmder) (string, bool) {
+	args := cmd.Args()
+	pos := firstKeyPos(args)
+	if pos <= 0 || pos >= len(args) {
+		return "", false
+	}
+	key, ok := args[pos].(string)
+	return key, ok
+}
+
+// firstKeyPos returns the position of the first key in the args of the command, 0 if the command has no key.
+// The key follows the command name by default.
+func firstKeyPos(args []interface{}) int {
+	if len(args) == 0 {
+		return 0
+	}
+	name, _ := args[0].(string)
+	name = strings.ToLower(name)
+	if _, ok := keylessCommands[name]; ok {
+		return 0
+	}
+	if subcommands, ok := keySubcommands[name]; ok {
+		if len(args) < 2 {
+			return 0
+		}
+		subcommand, _ := args[1].(string)
+		if _, ok := subcommands[strings.ToLower(subcommand)]; !ok {
+			return 0
+		}
+		return 2
+	}
+	if pos, ok := numKeysPositions[name]; ok {
+		if len(args) <= pos || numKeysOf(args[pos]) <= 0 {
+			return 0
+		}
+		return pos + 1
+	}
+	switch name {
+	case "bitop":
+		// BITOP operation destkey key [key ...]
+		return 2
+	case "xread", "xreadgroup":
+		// XREAD [COUNT count] [BLOCK milliseconds] STREAMS key [key ...] id [id ...]
+		for i, arg := range args {
+			if s, ok := arg.(string); ok && strings.EqualFold(s, "streams") {
+				return i + 1
+			}
+		}
+		return 0
+	}
+	return 1
+}
+
+func numKeysOf(arg interface{}) int {
+	switch n := arg.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case string:
+		v, _ := strconv.Atoi(n)
+		return v
+	}
+	return 0
+}
+
+func toSet(items ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		set[item] = struct{}{}
+	}
+	return set
+}
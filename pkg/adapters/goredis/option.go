@@ -0,0 +1,93 @@
+package goredis
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/redis/go-redis/v9"
+)
+
+// ResourceNaming indicates how the resource name of the command is generated.
+type ResourceNaming int32
+
+const (
+	// ByCommand names the resource by the upper cased command, e.g. "GET".
+	ByCommand ResourceNaming = iota
+	// ByKeyPattern names the resource by the command and the pattern of the key, e.g. "GET:user:*".
+	// The command without key is named by the command only.
+	ByKeyPattern
+)
+
+type (
+	Option  func(*options)
+	options struct {
+		resourceNaming  ResourceNaming
+		resourceExtract func(ctx context.Context, cmd redis.Cmder) string
+		keyPattern      func(key string) string
+		blockFallback   func(ctx context.Context, cmd redis.Cmder, blockErr *base.BlockError) error
+		disableArgs     bool
+	}
+)
+
+func evaluateOptions(opts []Option) *options {
+	optCopy := &options{
+		resourceNaming: ByCommand,
+		keyPattern:     KeyPattern,
+	}
+	for _, opt := range opts {
+		opt(optCopy)
+	}
+	return optCopy
+}
+
+// WithResourceNaming sets how the resource name of the command is generated, ByCommand by default.
+func WithResourceNaming(naming ResourceNaming) Option {
+	return func(opts *options) {
+		opts.resourceNaming = naming
+	}
+}
+
+// WithResourceExtractor sets the resource extractor of the command, which takes precedence over WithResourceNaming.
+func WithResourceExtractor(fn func(ctx context.Context, cmd redis.Cmder) string) Option {
+	return func(opts *options) {
+		opts.resourceExtract = fn
+	}
+}
+
+// WithKeyPattern sets the function which turns the key into the pattern of ByKeyPattern naming,
+// KeyPattern by default.
+func WithKeyPattern(fn func(key string) string) Option {
+	return func(opts *options) {
+		opts.keyPattern = fn
+	}
+}
+
+// WithBlockFallback sets the fallback handler when the command is blocked,
+// the returned error is set as the error of the command and the command is skipped
+// without error if nil is returned. The *base.BlockError is used by default.
+func WithBlockFallback(fn func(ctx context.Context, cmd redis.Cmder, blockErr *base.BlockError) error) Option {
+	return func(opts *options) {
+		opts.blockFallback = fn
+	}
+}
+
+// WithoutArgs disables passing the key to the entry as the args of hotspot parameter flow control.
+func WithoutArgs() Option {
+	return func(opts *options) {
+		opts.disableArgs = true
+	}
+}
+
+func (o *options) resourceNameOf(ctx context.Context, cmd redis.Cmder) string {
+	if o.resourceExtract != nil {
+		return o.resourceExtract(ctx, cmd)
+	}
+	name := strings.ToUpper(cmd.Name())
+	if o.resourceNaming == ByKeyPattern {
+		if key, ok := keyOf(cmd); ok {
+			return name + ":" + o.keyPattern(key)
+		}
+	}
+	return name
+}
@@ -0,0 +1,267 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/outlier"
+	"github.com/Danceiny/sentinel-golang/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/roundrobin"
+	"google.golang.org/grpc/resolver"
+)
+
+const (
+	// OutlierBalancerNamePrefix is the name prefix of the outlier ejection balancer wrapping the child balancer.
+	OutlierBalancerNamePrefix = "sentinel_outlier_"
+	// OutlierRoundRobinName is the name of the outlier ejection balancer wrapping round_robin.
+	OutlierRoundRobinName = OutlierBalancerNamePrefix + roundrobin.Name
+	// OutlierPickFirstName is the name of the outlier ejection balancer wrapping pick_first.
+	OutlierPickFirstName = OutlierBalancerNamePrefix + grpc.PickFirstBalancerName
+)
+
+// outlierSlotChain only includes the outlier ejection slots, the other rules of the request
+// are checked by the interceptors, so that the picker never rejects the request.
+var outlierSlotChain = newOutlierSlotChain()
+
+func newOutlierSlotChain() *base.SlotChain {
+	sc := base.NewSlotChain()
+	sc.AddRuleCheckSlot(outlier.DefaultSlot)
+	sc.AddStatSlot(outlier.DefaultMetricStatSlot)
+	return sc
+}
+
+func init() {
+	balancer.Register(NewOutlierBalancerBuilder(roundrobin.Name))
+	balancer.Register(NewOutlierBalancerBuilder(grpc.PickFirstBalancerName))
+}
+
+type outlierBalancerBuilder struct {
+	childName string
+	options   *options
+}
+
+// NewOutlierBalancerBuilder returns the balancer.Builder which wraps the registered balancer of childName
+// with outlier ejection, the returned builder is named by OutlierBalancerNamePrefix + childName.
+// The builders wrapping round_robin and pick_first are registered already, users may enable them
+// by the service config, like.
+//
+//	grpc.Dial(target, grpc.WithDefaultServiceConfig(`{"loadBalancingConfig": [{"sentinel_outlier_round_robin":{}}]}`))
+//
+// The resource of the outlier rule is the service of the full method by default, e.g. "helloworld.Greeter".
+func NewOutlierBalancerBuilder(childName string, opts ...Option) balancer.Builder {
+	return &outlierBalancerBuilder{childName: childName, options: evaluateOptions(opts)}
+}
+
+func (b *outlierBalancerBuilder) Name() string {
+	return OutlierBalancerNamePrefix + b.childName
+}
+
+func (b *outlierBalancerBuilder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	childBuilder := balancer.Get(b.childName)
+	if childBuilder == nil {
+		logging.Warn("[Sentinel gRPC] The child balancer is not registered, round_robin is used instead", "childName", b.childName)
+		childBuilder = balancer.Get(roundrobin.Name)
+	}
+	ob := &outlierBalancer{
+		builder:   b,
+		addresses: make(map[balancer.SubConn]string),
+	}
+	ob.Balancer = childBuilder.Build(&outlierClientConn{ClientConn: cc, balancer: ob}, opts)
+	return ob
+}
+
+func (b *outlierBalancerBuilder) resourceNameOf(ctx context.Context, fullMethod string) string {
+	if b.options.outlierResourceExtract != nil {
+		return b.options.outlierResourceExtract(ctx, fullMethod)
+	}
+	// "/helloworld.Greeter/SayHello" -> "helloworld.Greeter"
+	service := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(service, "/"); i >= 0 {
+		service = service[:i]
+	}
+	return service
+}
+
+// outlierBalancer is the child balancer which records the address of the sub connections.
+type outlierBalancer struct {
+	balancer.Balancer
+	builder *outlierBalancerBuilder
+
+	mux       sync.RWMutex
+	addresses map[balancer.SubConn]string
+}
+
+func (b *outlierBalancer) ExitIdle() {
+	if ei, ok := b.Balancer.(balancer.ExitIdler); ok {
+		ei.ExitIdle()
+	}
+}
+
+func (b *outlierBalancer) setAddress(sc balancer.SubConn, addrs []resolver.Address) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if len(addrs) == 0 {
+		delete(b.addresses, sc)
+		return
+	}
+	b.addresses[sc] = addrs[0].Addr
+}
+
+func (b *outlierBalancer) removeAddress(sc balancer.SubConn) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	delete(b.addresses, sc)
+}
+
+func (b *outlierBalancer) addressOf(sc balancer.SubConn) string {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return b.addresses[sc]
+}
+
+func (b *outlierBalancer) subConnCount() int {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return len(b.addresses)
+}
+
+// outlierClientConn intercepts the sub connections and the pickers of the child balancer.
+type outlierClientConn struct {
+	balancer.ClientConn
+	balancer *outlierBalancer
+}
+
+func (c *outlierClientConn) NewSubConn(addrs []resolver.Address, opts balancer.NewSubConnOptions) (balancer.SubConn, error) {
+	sc, err := c.ClientConn.NewSubConn(addrs, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.balancer.setAddress(sc, addrs)
+	return sc, nil
+}
+
+func (c *outlierClientConn) RemoveSubConn(sc balancer.SubConn) {
+	c.balancer.removeAddress(sc)
+	c.ClientConn.RemoveSubConn(sc)
+}
+
+func (c *outlierClientConn) UpdateAddresses(sc balancer.SubConn, addrs []resolver.Address) {
+	c.balancer.setAddress(sc, addrs)
+	c.ClientConn.UpdateAddresses(sc, addrs)
+}
+
+func (c *outlierClientConn) UpdateState(state balancer.State) {
+	state.Picker = &outlierPicker{Picker: state.Picker, balancer: c.balancer}
+	c.ClientConn.UpdateState(state)
+}
+
+// outlierPicker picks the sub connection of the child picker, skipping the ejected addresses.
+type outlierPicker struct {
+	balancer.Picker
+	balancer *outlierBalancer
+}
+
+func (p *outlierPicker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	result, err := p.Picker.Pick(info)
+	if err != nil {
+		return result, err
+	}
+	entry, blockErr := sentinel.Entry(
+		p.balancer.builder.resourceNameOf(info.Ctx, info.FullMethodName),
+		sentinel.WithResourceType(base.ResTypeRPC),
+		sentinel.WithTrafficType(base.Outbound),
+		sentinel.WithSlotChain(outlierSlotChain),
+	)
+	if blockErr != nil {
+		// the picker only skips the ejected addresses, it leaves the rejection to the interceptors,
+		// as the error returned by the picker other than the status error makes the wait-for-ready RPC wait forever.
+		return result, nil
+	}
+
+	result = p.pickAvailable(info, result, entry.Context().FilterNodes(), entry.Context().HalfOpenNodes())
+	address := p.balancer.addressOf(result.SubConn)
+	done := result.Done
+	result.Done = func(doneInfo balancer.DoneInfo) {
+		if done != nil {
+			done(doneInfo)
+		}
+		sentinel.TraceCallee(entry, address)
+		if doneInfo.Err != nil {
+			sentinel.TraceError(entry, doneInfo.Err)
+		}
+		entry.Exit()
+	}
+	return result, nil
+}
+
+// pickAvailable picks again until the address of the picked sub connection is available:
+// the half-open addresses are preferred for probing if any, otherwise the filtered addresses are skipped.
+// The first address not filtered (or the first picked one) is used if no available address is picked.
+func (p *outlierPicker) pickAvailable(info balancer.PickInfo, first balancer.PickResult, filters, halfOpens []string) balancer.PickResult {
+	if len(filters) == 0 && len(halfOpens) == 0 {
+		return first
+	}
+	filterSet := toSet(filters)
+	halfOpenSet := toSet(halfOpens)
+	isAvailable := func(address string) bool {
+		if len(halfOpenSet) > 0 {
+			_, ok := halfOpenSet[address]
+			return ok
+		}
+		_, ok := filterSet[address]
+		return !ok
+	}
+	isFiltered := func(address string) bool {
+		_, ok := filterSet[address]
+		return ok
+	}
+
+	fallback := first
+	fallbackFiltered := isFiltered(p.balancer.addressOf(first.SubConn))
+	result := first
+	for attempts := p.balancer.subConnCount(); ; attempts-- {
+		address := p.balancer.addressOf(result.SubConn)
+		if isAvailable(address) {
+			if result.SubConn != fallback.SubConn {
+				release(fallback)
+			}
+			return result
+		}
+		if result.SubConn != fallback.SubConn {
+			if fallbackFiltered && !isFiltered(address) {
+				release(fallback)
+				fallback, fallbackFiltered = result, false
+			} else {
+				release(result)
+			}
+		}
+		if attempts <= 1 {
+			return fallback
+		}
+		next, err := p.Picker.Pick(info)
+		if err != nil {
+			return fallback
+		}
+		result = next
+	}
+}
+
+// release finishes the pick result which is not used.
+func release(result balancer.PickResult) {
+	if result.Done != nil {
+		result.Done(balancer.DoneInfo{})
+	}
+}
+
+func toSet(items []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		set[item] = struct{}{}
+	}
+	return set
+}
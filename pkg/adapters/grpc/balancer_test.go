@@ -0,0 +1,156 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/circuitbreaker"
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/outlier"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+// startHealthServer starts the health server, the check of the service fails if not serving.
+func startHealthServer(t *testing.T, serving bool) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	hs := health.NewServer()
+	if serving {
+		hs.SetServingStatus("svc", healthpb.HealthCheckResponse_SERVING)
+	}
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, hs)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
+
+func TestOutlierBalancer(t *testing.T) {
+	good1, good2, bad := startHealthServer(t, true), startHealthServer(t, true), startHealthServer(t, false)
+	_, err := outlier.LoadRules([]*outlier.Rule{
+		{
+			Rule: &circuitbreaker.Rule{
+				Resource:         "grpc.health.v1.Health",
+				Strategy:         circuitbreaker.ErrorCount,
+				RetryTimeoutMs:   60000,
+				MinRequestAmount: 1,
+				StatIntervalMs:   10000,
+				Threshold:        1.0,
+			},
+			MaxEjectionPercent: 0.5,
+		},
+	})
+	assert.Nil(t, err)
+	defer func() {
+		_ = outlier.ClearRules()
+	}()
+
+	r := manual.NewBuilderWithScheme("sentinel-outlier")
+	r.InitialState(resolver.State{Addresses: []resolver.Address{{Addr: good1}, {Addr: good2}, {Addr: bad}}})
+	conn, err := grpc.Dial(r.Scheme()+":///test",
+		grpc.WithResolvers(r),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingConfig": [{"`+OutlierRoundRobinName+`":{}}]}`),
+	)
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	failures := 0
+	for i := 0; i < 30; i++ {
+		_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "svc"})
+		if err != nil {
+			failures++
+		}
+	}
+	// the bad address is ejected once its first failure is reported
+	assert.Equal(t, 1, failures)
+}
+
+// blockSlot blocks all the requests.
+type blockSlot struct{}
+
+func (s *blockSlot) Order() uint32 {
+	return 0
+}
+
+func (s *blockSlot) Check(_ *base.EntryContext) *base.TokenResult {
+	return base.NewTokenResultBlocked(base.BlockTypeFlow)
+}
+
+func TestOutlierBalancerNotReject(t *testing.T) {
+	addr := startHealthServer(t, true)
+	dial := func() healthpb.HealthClient {
+		r := manual.NewBuilderWithScheme("sentinel-outlier-not-reject")
+		r.InitialState(resolver.State{Addresses: []resolver.Address{{Addr: addr}}})
+		conn, err := grpc.Dial(r.Scheme()+":///test",
+			grpc.WithResolvers(r),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultServiceConfig(`{"loadBalancingConfig": [{"`+OutlierRoundRobinName+`":{}}]}`),
+		)
+		assert.Nil(t, err)
+		t.Cleanup(func() {
+			_ = conn.Close()
+		})
+		return healthpb.NewHealthClient(conn)
+	}
+	check := func(client healthpb.HealthClient) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "svc"}, grpc.WaitForReady(true))
+		return err
+	}
+
+	t.Run("FlowRules", func(t *testing.T) {
+		_, err := flow.LoadRules([]*flow.Rule{
+			{
+				Resource:               "grpc.health.v1.Health",
+				Threshold:              0.0,
+				TokenCalculateStrategy: flow.Direct,
+				ControlBehavior:        flow.Reject,
+			},
+		})
+		assert.Nil(t, err)
+		defer func() {
+			_ = flow.ClearRules()
+		}()
+		// the flow rules are left to the interceptors
+		assert.Nil(t, check(dial()))
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		sc := newOutlierSlotChain()
+		sc.AddRuleCheckSlot(&blockSlot{})
+		outlierSlotChain, sc = sc, outlierSlotChain
+		defer func() {
+			outlierSlotChain = sc
+		}()
+		// the blocked pick falls back to the pick of the child balancer instead of failing the wait-for-ready RPC
+		assert.Nil(t, check(dial()))
+	})
+}
+
+func TestOutlierBalancerBuilder_resourceNameOf(t *testing.T) {
+	b := NewOutlierBalancerBuilder("round_robin").(*outlierBalancerBuilder)
+	assert.Equal(t, OutlierRoundRobinName, b.Name())
+	assert.Equal(t, "helloworld.Greeter", b.resourceNameOf(context.Background(), "/helloworld.Greeter/SayHello"))
+
+	b = NewOutlierBalancerBuilder("pick_first", WithOutlierResourceExtractor(func(ctx context.Context, method string) string {
+		return "custom"
+	})).(*outlierBalancerBuilder)
+	assert.Equal(t, OutlierPickFirstName, b.Name())
+	assert.Equal(t, "custom", b.resourceNameOf(context.Background(), "/helloworld.Greeter/SayHello"))
+}
@@ -5,6 +5,7 @@ import (
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/fallback"
 	"google.golang.org/grpc"
 )
 
@@ -34,7 +35,10 @@ func NewUnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
 			if options.unaryClientBlockFallback != nil {
 				return options.unaryClientBlockFallback(ctx, method, req, cc, blockErr)
 			}
-			return blockErr
+			// consult the fallback registry by default, the result of the handler is discarded
+			// as the reply could not be filled generically
+			_, err := fallback.Invoke(ctx, resourceName, blockErr)
+			return err
 		}
 		defer entry.Exit()
 
@@ -72,7 +76,13 @@ func NewStreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
 			if options.streamClientBlockFallback != nil {
 				return options.streamClientBlockFallback(ctx, desc, cc, method, blockErr)
 			}
-			return nil, blockErr
+			// consult the fallback registry by default, whose handler may provide the grpc.ClientStream
+			result, err := fallback.Invoke(ctx, resourceName, blockErr)
+			if err != nil {
+				return nil, err
+			}
+			cs, _ := result.(grpc.ClientStream)
+			return cs, nil
 		}
 		defer entry.Exit()
 
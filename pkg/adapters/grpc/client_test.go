@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/fallback"
 	"github.com/Danceiny/sentinel-golang/core/flow"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
@@ -53,6 +54,27 @@ func TestUnaryClientIntercept(t *testing.T) {
 		err = interceptor(nil, method, nil, nil, nil, invoker)
 		assert.IsType(t, &base.BlockError{}, err)
 	})
+
+	t.Run("registered fallback", func(t *testing.T) {
+		var _, err = flow.LoadRules([]*flow.Rule{
+			{
+				Resource:               "client:" + method,
+				Threshold:              0.0,
+				TokenCalculateStrategy: flow.Direct,
+				ControlBehavior:        flow.Reject,
+			},
+		})
+		assert.Nil(t, err)
+		errFallback := errors.New("fallback error")
+		err = fallback.RegisterHandler("client:"+method, func(ctx context.Context, resource string, err error) (interface{}, error) {
+			return nil, errFallback
+		})
+		assert.Nil(t, err)
+		defer fallback.ClearHandlers()
+
+		err = interceptor(nil, method, nil, nil, nil, invoker)
+		assert.Equal(t, errFallback, err)
+	})
 }
 
 func TestStreamClientIntercept(t *testing.T) {
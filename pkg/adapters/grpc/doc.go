@@ -16,8 +16,23 @@ The plugin extracts service FullMethod as the resource name by default.
 Users may provide customized resource name extractor when creating new
 Sentinel interceptors (via options).
 
+For client side, users may also enable outlier ejection via the balancer wrapping round_robin
+or pick_first, which skips the addresses ejected by the outlier rules when picking:
+
+	conn, err := grpc.Dial(target, grpc.WithDefaultServiceConfig(
+		`{"loadBalancingConfig": [{"sentinel_outlier_round_robin":{}}]}`))
+
+The balancer reports the result of each call to the outlier statistic of the picked address,
+the resource of the outlier rules is the service of the full method (e.g. "helloworld.Greeter") by default.
+Users may wrap other registered balancers via NewOutlierBalancerBuilder.
+The balancer only checks the outlier rules and never rejects the request, the other rules are left to the interceptors.
+
 Fallback logic: the plugin will return the BlockError by default
 if current request is blocked by Sentinel rules. Users may also
 provide customized fallback logic via WithXxxBlockFallback(handler) options.
+If no block fallback option is provided, the interceptors consult the fallback handlers
+registered for the resource in Sentinel core fallback registry. The result of the handlers is returned
+as the response by the unary server interceptor and as the grpc.ClientStream by the stream client interceptor,
+and is discarded by the others.
 */
 package grpc
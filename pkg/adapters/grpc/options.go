@@ -22,6 +22,8 @@ type (
 
 		streamClientBlockFallback func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, *base.BlockError) (grpc.ClientStream, error)
 		streamServerBlockFallback func(interface{}, grpc.ServerStream, *grpc.StreamServerInfo, *base.BlockError) error
+
+		outlierResourceExtract func(context.Context, string) string
 	}
 )
 
@@ -83,6 +85,14 @@ func WithStreamServerBlockFallback(fn func(interface{}, grpc.ServerStream, *grpc
 	}
 }
 
+// WithOutlierResourceExtractor sets the resource extractor of the outlier ejection balancer.
+// The second string parameter is the full method name of current invocation.
+func WithOutlierResourceExtractor(fn func(context.Context, string) string) Option {
+	return func(opts *options) {
+		opts.outlierResourceExtract = fn
+	}
+}
+
 func evaluateOptions(opts []Option) *options {
 	optCopy := &options{}
 	for _, o := range opts {
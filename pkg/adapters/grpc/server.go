@@ -5,6 +5,7 @@ import (
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/fallback"
 	"google.golang.org/grpc"
 )
 
@@ -31,7 +32,8 @@ func NewUnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
 			if options.unaryServerBlockFallback != nil {
 				return options.unaryServerBlockFallback(ctx, req, info, blockErr)
 			}
-			return nil, blockErr
+			// consult the fallback registry by default
+			return fallback.Invoke(ctx, resourceName, blockErr)
 		}
 		defer entry.Exit()
 
@@ -66,7 +68,13 @@ func NewStreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 			if options.streamServerBlockFallback != nil {
 				return options.streamServerBlockFallback(srv, ss, info, blockErr)
 			}
-			return blockErr
+			// consult the fallback registry by default, the result of the handler is discarded
+			ctx := context.Background()
+			if ss != nil {
+				ctx = ss.Context()
+			}
+			_, err := fallback.Invoke(ctx, resourceName, blockErr)
+			return err
 		}
 		defer entry.Exit()
 
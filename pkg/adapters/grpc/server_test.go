@@ -8,6 +8,7 @@ import (
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/fallback"
 	"github.com/Danceiny/sentinel-golang/core/flow"
 	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/Danceiny/sentinel-golang/util"
@@ -115,4 +116,25 @@ func TestUnaryServerIntercept(t *testing.T) {
 		assert.IsType(t, &base.BlockError{}, err)
 		assert.Nil(t, rep)
 	})
+
+	t.Run("registered fallback", func(t *testing.T) {
+		var _, err = flow.LoadRules([]*flow.Rule{
+			{
+				Resource:               "/grpc.testing.TestService/UnaryCall",
+				Threshold:              0.0,
+				TokenCalculateStrategy: flow.Direct,
+				ControlBehavior:        flow.Reject,
+			},
+		})
+		assert.Nil(t, err)
+		err = fallback.RegisterHandler("/grpc.testing.TestService/UnaryCall", func(ctx context.Context, resource string, err error) (interface{}, error) {
+			return "fallback", nil
+		})
+		assert.Nil(t, err)
+		defer fallback.ClearHandlers()
+
+		rep, err := interceptor(nil, nil, info, successHandler)
+		assert.Nil(t, err)
+		assert.Equal(t, "fallback", rep)
+	})
 }
@@ -2,8 +2,10 @@ package hertz
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"testing"
+	"time"
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/flow"
@@ -102,16 +104,23 @@ func TestClientSentinelMiddleware(t *testing.T) {
 			},
 		},
 	}
-	go func() {
-		h := server.New(server.WithHostPorts(":3000"))
-		h.GET("/client_ping", func(c context.Context, ctx *app.RequestContext) {
-			ctx.String(200, "pong")
-		})
-		h.GET("/api/users/:id", func(c context.Context, ctx *app.RequestContext) {
-			ctx.String(200, "pong")
-		})
-		h.Spin()
-	}()
+	h := server.New(server.WithHostPorts(":3000"))
+	h.GET("/client_ping", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(200, "pong")
+	})
+	h.GET("/api/users/:id", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(200, "pong")
+	})
+	go h.Spin()
+	// Wait for the server to be ready to accept connections.
+	assert.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", "localhost:3000")
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
 	initSentinelForClient(t)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
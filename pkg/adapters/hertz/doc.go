@@ -27,6 +27,9 @@ The plugin extracts service FullMethod as the resource name by default.
 Users may provide customized resource name extractor when creating new
 Sentinel middlewares (via options).
 
+Error tracing: for server side, the response of 5xx status code and the panic of the handler are traced as errors
+by default, see package httptrace. Users may customize the behavior via WithServerErrorStatusCodes and WithServerPanicTrace options.
+
 Fallback logic: the plugin will stop by default if
 current request is blocked by Sentinel rules. Users may also provide
 customized fallback logic via WithClientBlockFallback(handler) options
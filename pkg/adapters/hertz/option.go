@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/protocol"
 )
 
+// StatusCodePairKey is the key of the entry pair which records the response status code.
+const StatusCodePairKey = httptrace.StatusCodePairKey
+
 type (
 	ServerOption struct {
 		Fn func(*serverOptions)
@@ -16,6 +20,8 @@ type (
 	serverOptions struct {
 		resourceExtract func(context.Context, *app.RequestContext) string
 		blockFallback   func(context.Context, *app.RequestContext)
+		isErrorStatus   func(int) bool
+		tracePanic      bool
 	}
 )
 
@@ -46,9 +52,10 @@ func newServerOptions(opts []ServerOption) *serverOptions {
 		resourceExtract: func(c context.Context, ctx *app.RequestContext) string {
 			return fmt.Sprintf("%v:%v", string(ctx.Request.Method()), ctx.FullPath())
 		},
-		blockFallback: func(c context.Context, ctx *app.RequestContext) {
-			ctx.AbortWithStatus(http.StatusTooManyRequests)
+		isErrorStatus: func(code int) bool {
+			return httptrace.IsServerErrorStatus(code)
 		},
+		tracePanic: true,
 	}
 	options.Apply(opts)
 	return options
@@ -86,6 +93,26 @@ func WithServerBlockFallback(fn func(context.Context, *app.RequestContext)) Serv
 	}
 }
 
+// WithServerErrorStatusCodes sets the response status codes which are traced as errors for server side, 5xx by default.
+func WithServerErrorStatusCodes(codes ...int) ServerOption {
+	isErrorStatus := httptrace.ErrorStatusCodes(codes...)
+	return ServerOption{
+		Fn: func(o *serverOptions) {
+			o.isErrorStatus = isErrorStatus
+		},
+	}
+}
+
+// WithServerPanicTrace sets whether the panic of the handler is traced as an error for server side, true by default.
+// The panic is re-panicked after being traced.
+func WithServerPanicTrace(enabled bool) ServerOption {
+	return ServerOption{
+		Fn: func(o *serverOptions) {
+			o.tracePanic = enabled
+		},
+	}
+}
+
 // WithClientResourceExtractor sets the resource extractor of the web requests for client side.
 func WithClientResourceExtractor(fn func(context.Context, *protocol.Request,
 	*protocol.Response) string,
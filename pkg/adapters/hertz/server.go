@@ -2,15 +2,21 @@ package hertz
 
 import (
 	"context"
+	"net/http"
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httpfallback"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
 	"github.com/cloudwego/hertz/pkg/app"
 )
 
 // SentinelServerMiddleware returns new app.HandlerFunc
 // Default resource name is {method}:{path}, such as "GET:/api/users/:id"
-// Default block fallback is returning 429 code
+// Default block fallback is responding the result of the fallback handler registered for the resource
+// (see fallback.RegisterHandler), or returning 429 code if there is none
+// The response of 5xx status code (see WithServerErrorStatusCodes) and the panic are traced as errors,
+// and the response status code is recorded as the entry pair of StatusCodePairKey.
 // Define your own behavior by setting serverOptions
 func SentinelServerMiddleware(opts ...ServerOption) app.HandlerFunc {
 	options := newServerOptions(opts)
@@ -23,10 +29,24 @@ func SentinelServerMiddleware(opts ...ServerOption) app.HandlerFunc {
 			sentinel.WithTrafficType(base.Inbound),
 		)
 		if err != nil {
-			options.blockFallback(c, ctx)
+			if options.blockFallback != nil {
+				options.blockFallback(c, ctx)
+			} else if contentType, body, ok := httpfallback.Invoke(c, resourceName, err); ok {
+				ctx.Data(http.StatusOK, contentType, body)
+				ctx.Abort()
+			} else {
+				ctx.AbortWithStatus(http.StatusTooManyRequests)
+			}
 			return
 		}
 		defer entry.Exit()
+		if options.tracePanic {
+			defer func() {
+				httptrace.TracePanic(entry, recover())
+			}()
+		}
+
 		ctx.Next(c)
+		httptrace.TraceStatusCode(entry, ctx.Response.StatusCode(), nil, options.isErrorStatus)
 	}
 }
@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
 	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/common/config"
 	"github.com/cloudwego/hertz/pkg/common/ut"
@@ -125,3 +127,16 @@ func TestServerSentinelMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestServerSentinelMiddlewareTraceStatusCode(t *testing.T) {
+	initServerSentinel(t)
+
+	router := route.NewEngine(config.NewOptions([]config.Option{}))
+	router.Use(SentinelServerMiddleware())
+	router.GET("/server_error", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(http.StatusInternalServerError, "error")
+	})
+
+	ut.PerformRequest(router, http.MethodGet, "/server_error", nil)
+	assert.Equal(t, int64(1), stat.GetResourceNode("GET:/server_error").GetSum(base.MetricEventError))
+}
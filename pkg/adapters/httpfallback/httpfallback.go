@@ -0,0 +1,45 @@
+// Package httpfallback provides the default block response of the HTTP adapters based on the fallback registry,
+// which is used by the adapters if no block fallback option is provided.
+package httpfallback
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/fallback"
+	"github.com/Danceiny/sentinel-golang/logging"
+)
+
+// ContentTypeText and ContentTypeJSON are the content types of the fallback responses.
+const (
+	ContentTypeText = "text/plain; charset=utf-8"
+	ContentTypeJSON = "application/json; charset=utf-8"
+)
+
+// Invoke invokes the fallback handler registered for the resource (see fallback.RegisterHandler) with the block error,
+// and returns the content type and the body of the response, which is responded with http.StatusOK by the adapters.
+// The result of the handler is written as is if it is a string or []byte, or as JSON otherwise.
+// ok is false if no handler is registered or the handler returns an error, in which case the adapters respond
+// their default block response.
+func Invoke(ctx context.Context, resource string, blockErr *base.BlockError) (contentType string, body []byte, ok bool) {
+	result, err := fallback.Invoke(ctx, resource, blockErr)
+	if err != nil {
+		return "", nil, false
+	}
+	switch r := result.(type) {
+	case nil:
+		return ContentTypeText, nil, true
+	case string:
+		return ContentTypeText, []byte(r), true
+	case []byte:
+		return ContentTypeText, r, true
+	default:
+		body, err = json.Marshal(r)
+		if err != nil {
+			logging.Error(err, "Failed to marshal the fallback result in httpfallback.Invoke()", "resource", resource)
+			return "", nil, false
+		}
+		return ContentTypeJSON, body, true
+	}
+}
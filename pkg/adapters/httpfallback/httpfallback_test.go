@@ -0,0 +1,56 @@
+package httpfallback
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/fallback"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvoke(t *testing.T) {
+	defer fallback.ClearHandlers()
+
+	blockErr := base.NewBlockErrorWithMessage(base.BlockTypeFlow, "")
+	results := map[string]interface{}{
+		"nil":    nil,
+		"string": "degraded",
+		"bytes":  []byte("degraded"),
+		"json":   map[string]int{"code": 1},
+		"chan":   make(chan int),
+	}
+	for resource, result := range results {
+		result := result
+		assert.NoError(t, fallback.RegisterHandler(resource, func(context.Context, string, error) (interface{}, error) {
+			return result, nil
+		}))
+	}
+	assert.NoError(t, fallback.RegisterHandler("error", func(context.Context, string, error) (interface{}, error) {
+		return nil, errors.New("fallback error")
+	}))
+
+	tests := []struct {
+		resource    string
+		contentType string
+		body        string
+		ok          bool
+	}{
+		{resource: "nil", contentType: ContentTypeText, body: "", ok: true},
+		{resource: "string", contentType: ContentTypeText, body: "degraded", ok: true},
+		{resource: "bytes", contentType: ContentTypeText, body: "degraded", ok: true},
+		{resource: "json", contentType: ContentTypeJSON, body: `{"code":1}`, ok: true},
+		{resource: "chan"},
+		{resource: "error"},
+		{resource: "unregistered"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resource, func(t *testing.T) {
+			contentType, body, ok := Invoke(context.Background(), tt.resource, blockErr)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.contentType, contentType)
+			assert.Equal(t, tt.body, string(body))
+		})
+	}
+}
@@ -0,0 +1,10 @@
+/*
+Package httptrace provides the error tracing shared by the HTTP adapters.
+
+The adapters record the response status code as the entry pair of StatusCodePairKey,
+and trace the response of 5xx status code and the panic of the handler as errors by default,
+so that the error metrics and circuit breakers work out of the box.
+The adapters provide the options to customize the traced status codes and to disable the panic tracing,
+e.g. WithErrorStatusCodes and WithPanicTrace.
+*/
+package httptrace
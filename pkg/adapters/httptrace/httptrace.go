@@ -0,0 +1,55 @@
+package httptrace
+
+import (
+	"net/http"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/pkg/errors"
+)
+
+// StatusCodePairKey is the key of the entry pair which records the response status code.
+const StatusCodePairKey = "sentinel.http.statusCode"
+
+// IsServerErrorStatus indicates whether the status code is 5xx, which is traced as an error by default.
+func IsServerErrorStatus(code int) bool {
+	return code >= http.StatusInternalServerError
+}
+
+// ErrorStatusCodes returns the function which indicates whether the status code is one of the given codes.
+func ErrorStatusCodes(codes ...int) func(int) bool {
+	codeSet := make(map[int]struct{}, len(codes))
+	for _, code := range codes {
+		codeSet[code] = struct{}{}
+	}
+	return func(code int) bool {
+		_, ok := codeSet[code]
+		return ok
+	}
+}
+
+// TraceStatusCode records the status code to the entry, and traces err as an error if isErrorStatus
+// reports the status code as an error. The error of the status code is traced instead if err is nil.
+func TraceStatusCode(entry *base.SentinelEntry, status int, err error, isErrorStatus func(int) bool) {
+	entry.SetPair(StatusCodePairKey, status)
+	if !isErrorStatus(status) {
+		return
+	}
+	if err == nil {
+		err = errors.Errorf("http status %d", status)
+	}
+	sentinel.TraceError(entry, err)
+}
+
+// TracePanic traces the recovered panic as an error and re-panics, e is the result of recover()
+// which must be called directly by the deferred function, like.
+//
+//	defer func() {
+//		httptrace.TracePanic(entry, recover())
+//	}()
+func TracePanic(entry *base.SentinelEntry, e interface{}) {
+	if e != nil {
+		sentinel.TraceError(entry, errors.Errorf("panic: %v", e))
+		panic(e)
+	}
+}
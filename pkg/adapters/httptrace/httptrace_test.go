@@ -0,0 +1,67 @@
+package httptrace
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorStatusCodes(t *testing.T) {
+	assert.True(t, IsServerErrorStatus(http.StatusBadGateway))
+	assert.False(t, IsServerErrorStatus(http.StatusNotFound))
+
+	isErrorStatus := ErrorStatusCodes(http.StatusNotFound, http.StatusBadGateway)
+	assert.True(t, isErrorStatus(http.StatusNotFound))
+	assert.True(t, isErrorStatus(http.StatusBadGateway))
+	assert.False(t, isErrorStatus(http.StatusInternalServerError))
+}
+
+func TestTraceStatusCode(t *testing.T) {
+	errHandle := errors.New("handle error")
+	tests := []struct {
+		name    string
+		status  int
+		err     error
+		wantErr string
+	}{
+		{name: "ok", status: http.StatusOK},
+		{name: "status", status: http.StatusServiceUnavailable, wantErr: "http status 503"},
+		{name: "error", status: http.StatusInternalServerError, err: errHandle, wantErr: errHandle.Error()},
+		{name: "client error", status: http.StatusBadRequest, err: errHandle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, blockErr := sentinel.Entry("httptrace-" + tt.name)
+			assert.Nil(t, blockErr)
+			defer entry.Exit()
+
+			TraceStatusCode(entry, tt.status, tt.err, IsServerErrorStatus)
+			assert.Equal(t, tt.status, entry.Context().GetPair(StatusCodePairKey))
+			if tt.wantErr == "" {
+				assert.Nil(t, entry.Context().Err())
+			} else {
+				assert.EqualError(t, entry.Context().Err(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTracePanic(t *testing.T) {
+	entry, blockErr := sentinel.Entry("httptrace-panic")
+	assert.Nil(t, blockErr)
+	defer entry.Exit()
+
+	TracePanic(entry, nil)
+	assert.Nil(t, entry.Context().Err())
+
+	assert.PanicsWithValue(t, "oops", func() {
+		defer func() {
+			TracePanic(entry, recover())
+		}()
+		panic("oops")
+	})
+	assert.EqualError(t, entry.Context().Err(), "panic: oops")
+}
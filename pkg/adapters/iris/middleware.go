@@ -5,9 +5,18 @@ import (
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httpfallback"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
 	"github.com/kataras/iris/v12"
 )
 
+// SentinelMiddleware returns new iris.Handler
+// Default resource name is {method}:{url}, such as "GET:/api/users/1"
+// Default block fallback is responding the result of the fallback handler registered for the resource
+// (see fallback.RegisterHandler), or returning 429 code if there is none
+// The response of 5xx status code (see WithErrorStatusCodes) and the panic are traced as errors,
+// and the response status code is recorded as the entry pair of StatusCodePairKey.
+// Define your own behavior by setting options
 func SentinelMiddleware(opts ...Option) iris.Handler {
 	options := evaluateOptions(opts)
 	return func(c iris.Context) {
@@ -26,6 +35,11 @@ func SentinelMiddleware(opts ...Option) iris.Handler {
 		if err != nil {
 			if options.blockFallback != nil {
 				options.blockFallback(c)
+			} else if contentType, body, ok := httpfallback.Invoke(c.Request().Context(), resourceName, err); ok {
+				c.ContentType(contentType)
+				c.StatusCode(http.StatusOK)
+				_, _ = c.Write(body)
+				c.StopExecution()
 			} else {
 				c.StatusCode(http.StatusTooManyRequests)
 				c.StopExecution()
@@ -34,6 +48,12 @@ func SentinelMiddleware(opts ...Option) iris.Handler {
 		}
 
 		defer entry.Exit()
+		if options.tracePanic {
+			defer func() {
+				httptrace.TracePanic(entry, recover())
+			}()
+		}
 		c.Next()
+		httptrace.TraceStatusCode(entry, c.GetStatusCode(), nil, options.isErrorStatus)
 	}
 }
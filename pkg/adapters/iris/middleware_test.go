@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
 	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/httptest"
 )
@@ -141,3 +143,19 @@ func TestSentinelMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestSentinelMiddlewareTraceStatusCode(t *testing.T) {
+	initSentinel(t)
+
+	router := iris.New()
+	router.Use(SentinelMiddleware())
+	router.Get("/error", func(ctx iris.Context) {
+		ctx.StatusCode(http.StatusInternalServerError)
+	})
+
+	e := httptest.New(t, router)
+	e.GET("/error").Expect().Status(http.StatusInternalServerError)
+	if got := stat.GetResourceNode("GET:/error").GetSum(base.MetricEventError); got != 1 {
+		t.Errorf("Unexpected error count of GET:/error: got %d, want 1", got)
+	}
+}
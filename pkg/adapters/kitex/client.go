@@ -10,7 +10,6 @@ import (
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
-	"github.com/Danceiny/sentinel-golang/core/outlier"
 )
 
 var filterNodes []string
@@ -41,16 +40,16 @@ func SentinelClientMiddleware(opts ...Option) func(endpoint.Endpoint) endpoint.E
 				}
 				return err
 			} else { // returns new client middleware specifically for outlier ejection.
-				slotChain := sentinel.BuildDefaultSlotChain()
-				slotChain.AddRuleCheckSlot(outlier.DefaultSlot)
-				slotChain.AddStatSlot(outlier.DefaultMetricStatSlot)
+				// the outlier ejection slots are included in the global slot chain
 				resourceName := ServiceNameExtract(ctx)
-				entry, _ := sentinel.Entry(
+				entry, blockErr := sentinel.Entry(
 					resourceName,
 					sentinel.WithResourceType(base.ResTypeRPC),
 					sentinel.WithTrafficType(base.Outbound),
-					sentinel.WithSlotChain(slotChain),
 				)
+				if blockErr != nil {
+					return options.BlockFallback(ctx, req, resp, blockErr)
+				}
 				defer entry.Exit()
 				filterNodes = entry.Context().FilterNodes()
 				halfNodes = entry.Context().HalfOpenNodes()
@@ -10,7 +10,6 @@ import (
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
-	"github.com/Danceiny/sentinel-golang/core/outlier"
 )
 
 const filterNodesKey = "filterNodes"
@@ -59,15 +58,15 @@ func SentinelClientMiddleware(opts ...Option) middleware.Middleware {
 				return resp, err
 			} else { // returns new client middleware specifically for outlier ejection.
 				resourceName := ServiceNameExtract(ctx)
-				slotChain := sentinel.BuildDefaultSlotChain()
-				slotChain.AddRuleCheckSlot(outlier.DefaultSlot)
-				slotChain.AddStatSlot(outlier.DefaultMetricStatSlot)
-				entry, _ := sentinel.Entry(
+				// the outlier ejection slots are included in the global slot chain
+				entry, blockErr := sentinel.Entry(
 					resourceName,
 					sentinel.WithResourceType(base.ResTypeRPC),
 					sentinel.WithTrafficType(base.Outbound),
-					sentinel.WithSlotChain(slotChain),
 				)
+				if blockErr != nil {
+					return options.BlockFallback(ctx, req, blockErr)
+				}
 				defer entry.Exit()
 
 				if v, ok := metadata.FromClientContext(ctx); ok {
@@ -7,7 +7,6 @@ import (
 
 	sentinel "github.com/Danceiny/sentinel-golang/api"
 	"github.com/Danceiny/sentinel-golang/core/base"
-	"github.com/Danceiny/sentinel-golang/core/outlier"
 )
 
 type clientWrapper struct {
@@ -42,15 +41,18 @@ func (c *clientWrapper) Call(ctx context.Context, req client.Request, rsp interf
 		}
 		return err
 	} else { // returns new client middleware specifically for outlier ejection.
-		slotChain := sentinel.BuildDefaultSlotChain()
-		slotChain.AddRuleCheckSlot(outlier.DefaultSlot)
-		slotChain.AddStatSlot(outlier.DefaultMetricStatSlot)
-		entry, _ := sentinel.Entry(
+		// the outlier ejection slots are included in the global slot chain
+		entry, blockErr := sentinel.Entry(
 			req.Service(),
 			sentinel.WithResourceType(base.ResTypeRPC),
 			sentinel.WithTrafficType(base.Outbound),
-			sentinel.WithSlotChain(slotChain),
 		)
+		if blockErr != nil {
+			if options.clientBlockFallback != nil {
+				return options.clientBlockFallback(ctx, req, blockErr)
+			}
+			return blockErr
+		}
 		defer entry.Exit()
 		opts = append(opts, WithSelectOption(entry))
 		opts = append(opts, WithCallWrapper(entry))
@@ -85,15 +87,18 @@ func (c *clientWrapper) Stream(ctx context.Context, req client.Request, opts ...
 		}
 		return stream, err
 	} else {
-		slotChain := sentinel.GlobalSlotChain()
-		slotChain.AddRuleCheckSlot(outlier.DefaultSlot)
-		slotChain.AddStatSlot(outlier.DefaultMetricStatSlot)
-		entry, _ := sentinel.Entry(
+		// the outlier ejection slots are included in the global slot chain
+		entry, blockErr := sentinel.Entry(
 			req.Service(),
 			sentinel.WithResourceType(base.ResTypeRPC),
 			sentinel.WithTrafficType(base.Outbound),
-			sentinel.WithSlotChain(slotChain),
 		)
+		if blockErr != nil {
+			if options.streamClientBlockFallback != nil {
+				return options.streamClientBlockFallback(ctx, req, blockErr)
+			}
+			return nil, blockErr
+		}
 		defer entry.Exit()
 		opts = append(opts, WithSelectOption(entry))
 		opts = append(opts, WithCallWrapper(entry))
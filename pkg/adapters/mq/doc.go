@@ -0,0 +1,35 @@
+/*
+This package provides Sentinel integration for the message queue consumers.
+
+Users may wrap the handler of the messages with Sentinel, like.
+
+	import (
+		sentinelPlugin "github.com/Danceiny/sentinel-golang/pkg/adapters/mq"
+	)
+
+	handler := sentinelPlugin.WrapHandler(func(ctx context.Context, msg *sentinelPlugin.Message) error {
+		// handle the message
+		return nil
+	})
+
+Each message handled by the wrapped handler creates an inbound entry of base.ResTypeMQ.
+The plugin names the resource by the topic of the message by default, users may provide
+customized resource name extractor via WithResourceExtractor option. The errors returned
+by the handler are traced as errors.
+
+The key of the message is passed to the entry as the args of hotspot parameter flow control,
+followed by the values of the headers given by WithHeaderArgs option, so that the hot keys
+and headers (e.g. the tenant) could be limited by the hotspot rules. Users may also provide
+customized args extractor via WithArgsExtractor option.
+
+Rejecting the message makes little sense for the consumer, so the consumption is recommended
+to be paced by the flow rules with flow.Throttling control behavior (see NewThrottlingRule),
+which makes the message wait in queue until the pace allows.
+
+Block policy: the plugin will pause the consumer and retry the entry of the blocked message
+by default (see Wait). Users may also requeue the blocked message with delay (see Requeue),
+send it to the dead letter queue (see DeadLetter) or provide customized policy via WithBlockPolicy option.
+
+The sub package kafka provides the bindings for github.com/segmentio/kafka-go.
+*/
+package mq
@@ -0,0 +1,63 @@
+package mq
+
+import (
+	"context"
+	"time"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/flow"
+)
+
+// WrapHandler returns new Handler which guards the handler with Sentinel entries.
+// Default resource name is the topic of the message.
+// Default block policy is Wait(DefaultWaitInterval).
+func WrapHandler(handler Handler, opts ...Option) Handler {
+	options := evaluateOptions(opts)
+	return func(ctx context.Context, msg *Message) error {
+		resource := options.resourceNameOf(ctx, msg)
+		args := options.argsOf(ctx, msg)
+		for {
+			entry, blockErr := sentinel.Entry(
+				resource,
+				sentinel.WithResourceType(base.ResTypeMQ),
+				sentinel.WithTrafficType(base.Inbound),
+				sentinel.WithArgs(args...),
+			)
+			if blockErr != nil {
+				err := options.blockPolicyOf()(ctx, msg, blockErr)
+				if err == errRetry {
+					continue
+				}
+				return err
+			}
+
+			return handle(ctx, msg, handler, entry)
+		}
+	}
+}
+
+// handle handles the message passed by the entry, the entry exits even if the handler panics.
+func handle(ctx context.Context, msg *Message, handler Handler, entry *base.SentinelEntry) error {
+	defer entry.Exit()
+
+	err := handler(ctx, msg)
+	if err != nil {
+		sentinel.TraceError(entry, err)
+	}
+	return err
+}
+
+// NewThrottlingRule returns the flow rule which paces the consumption of the resource
+// at most messagesPerSec messages per second. The message waits in queue until the pace allows,
+// and it's blocked if the estimated queueing time exceeds maxQueueingTime.
+func NewThrottlingRule(resource string, messagesPerSec float64, maxQueueingTime time.Duration) *flow.Rule {
+	return &flow.Rule{
+		Resource:               resource,
+		TokenCalculateStrategy: flow.Direct,
+		ControlBehavior:        flow.Throttling,
+		Threshold:              messagesPerSec,
+		MaxQueueingTimeMs:      uint32(maxQueueingTime / time.Millisecond),
+		StatIntervalInMs:       1000,
+	}
+}
@@ -0,0 +1,173 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+// argsSlot records the args of the entries.
+type argsSlot struct {
+	mux  sync.Mutex
+	args map[string][]interface{}
+}
+
+func (s *argsSlot) Order() uint32 {
+	return 9000
+}
+
+func (s *argsSlot) OnEntryPassed(ctx *base.EntryContext) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.args[ctx.Resource.Name()] = ctx.Input.Args
+}
+
+func (s *argsSlot) OnEntryBlocked(_ *base.EntryContext, _ *base.BlockError) {
+}
+
+func (s *argsSlot) OnCompleted(_ *base.EntryContext) {
+}
+
+func (s *argsSlot) argsOf(resource string) []interface{} {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.args[resource]
+}
+
+var testArgsSlot = &argsSlot{args: make(map[string][]interface{})}
+
+func init() {
+	sentinel.GlobalSlotChain().AddStatSlot(testArgsSlot)
+}
+
+func initSentinel(t *testing.T) {
+	err := sentinel.InitDefault()
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	_, err = flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "blocked",
+			Threshold:              0,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+		},
+		{
+			Resource:               "limited",
+			Threshold:              1,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+		},
+		NewThrottlingRule("throttled", 20, time.Second),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+}
+
+func TestWrapHandler(t *testing.T) {
+	initSentinel(t)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		handler := WrapHandler(func(ctx context.Context, msg *Message) error {
+			return nil
+		}, WithHeaderArgs("tenant", "absent"))
+		msg := &Message{Topic: "orders", Key: "order-1", Headers: map[string]string{"tenant": "t1"}}
+		assert.Nil(t, handler(ctx, msg))
+		assert.Equal(t, []interface{}{"order-1", "t1", ""}, testArgsSlot.argsOf("orders"))
+		assert.Equal(t, int64(1), stat.GetResourceNode("orders").GetSum(base.MetricEventPass))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		errHandle := errors.New("handle error")
+		handler := WrapHandler(func(ctx context.Context, msg *Message) error {
+			return errHandle
+		}, WithResourceExtractor(func(ctx context.Context, msg *Message) string {
+			return "consumer:" + msg.Topic
+		}))
+		assert.Equal(t, errHandle, handler(ctx, &Message{Topic: "payments"}))
+		assert.Equal(t, int64(1), stat.GetResourceNode("consumer:payments").GetSum(base.MetricEventError))
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		handler := WrapHandler(func(ctx context.Context, msg *Message) error {
+			panic("oops")
+		})
+		assert.Panics(t, func() {
+			_ = handler(ctx, &Message{Topic: "panics"})
+		})
+		assert.Equal(t, int32(0), stat.GetResourceNode("panics").CurrentConcurrency())
+		assert.Equal(t, int64(1), stat.GetResourceNode("panics").GetSum(base.MetricEventComplete))
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		handled := 0
+		handler := WrapHandler(func(ctx context.Context, msg *Message) error {
+			handled++
+			return nil
+		}, WithBlockPolicy(Wait(10*time.Millisecond)))
+		for i := 0; i < 2; i++ {
+			assert.Nil(t, handler(ctx, &Message{Topic: "limited"}))
+		}
+		assert.Equal(t, 2, handled)
+	})
+
+	t.Run("wait canceled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		handler := WrapHandler(func(ctx context.Context, msg *Message) error {
+			t.Fatal("the blocked message should not be handled")
+			return nil
+		}, WithBlockPolicy(Wait(10*time.Millisecond)))
+		assert.Equal(t, context.DeadlineExceeded, handler(ctx, &Message{Topic: "blocked"}))
+	})
+
+	t.Run("requeue", func(t *testing.T) {
+		var requeued *Message
+		handler := WrapHandler(func(ctx context.Context, msg *Message) error {
+			return nil
+		}, WithBlockPolicy(Requeue(func(ctx context.Context, msg *Message, delay time.Duration) error {
+			requeued = msg
+			assert.Equal(t, time.Second, delay)
+			return nil
+		}, time.Second)))
+		msg := &Message{Topic: "blocked"}
+		assert.Nil(t, handler(ctx, msg))
+		assert.Equal(t, msg, requeued)
+	})
+
+	t.Run("dead letter", func(t *testing.T) {
+		errDeadLetter := errors.New("dead letter error")
+		handler := WrapHandler(func(ctx context.Context, msg *Message) error {
+			return nil
+		}, WithBlockPolicy(DeadLetter(func(ctx context.Context, msg *Message, blockErr *base.BlockError) error {
+			assert.Equal(t, base.BlockTypeFlow, blockErr.BlockType())
+			return errDeadLetter
+		})))
+		assert.Equal(t, errDeadLetter, handler(ctx, &Message{Topic: "blocked"}))
+	})
+
+	t.Run("throttling", func(t *testing.T) {
+		handler := WrapHandler(func(ctx context.Context, msg *Message) error {
+			return nil
+		}, WithBlockPolicy(DeadLetter(func(ctx context.Context, msg *Message, blockErr *base.BlockError) error {
+			t.Fatal("the throttled message should not be blocked")
+			return nil
+		})))
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			assert.Nil(t, handler(ctx, &Message{Topic: "throttled"}))
+		}
+		// 20 messages per second, the interval of two messages is 50ms
+		assert.True(t, time.Since(start) >= 150*time.Millisecond)
+	})
+}
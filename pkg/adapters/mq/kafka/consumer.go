@@ -0,0 +1,161 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/mq"
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+const (
+	// RetryAfterHeader is the header of the requeued message, which is the unix milliseconds
+	// before which the message should not be handled.
+	RetryAfterHeader = "sentinel-retry-after"
+	// BlockReasonHeader is the header of the dead letter message, which is the error of the block.
+	BlockReasonHeader = "sentinel-block-reason"
+)
+
+// Reader fetches and commits the messages, which is satisfied by *kafka.Reader.
+type Reader interface {
+	FetchMessage(ctx context.Context) (kafkago.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafkago.Message) error
+}
+
+// Writer writes the messages, which is satisfied by *kafka.Writer.
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafkago.Message) error
+}
+
+// Handler handles the kafka message.
+type Handler func(ctx context.Context, msg kafkago.Message) error
+
+// NewMessage converts the kafka message to the mq.Message.
+func NewMessage(msg kafkago.Message) *mq.Message {
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	return &mq.Message{
+		Topic:   msg.Topic,
+		Key:     string(msg.Key),
+		Headers: headers,
+		Value:   msg.Value,
+		Raw:     msg,
+	}
+}
+
+// WrapHandler returns new Handler which guards the handler with Sentinel entries, see mq.WrapHandler.
+func WrapHandler(handler Handler, opts ...mq.Option) Handler {
+	guarded := mq.WrapHandler(func(ctx context.Context, msg *mq.Message) error {
+		return handler(ctx, msg.Raw.(kafkago.Message))
+	}, opts...)
+	return func(ctx context.Context, msg kafkago.Message) error {
+		return guarded(ctx, NewMessage(msg))
+	}
+}
+
+// Consume fetches the messages from the reader, handles them with the handler guarded by Sentinel
+// and commits them one by one, until the context is done. Consume returns the first error of fetching
+// or committing the message, and the error of handling the message unless it's handled by WithErrorHandler.
+//
+// The requeued message waits until its RetryAfterHeader before being handled, which blocks the messages
+// behind it in the same partition. So the blocked messages are expected to be requeued to a dedicated retry topic
+// (see RequeueTo) consumed by another Consume, where the messages requeued with the same delay are in order
+// of their retry time, rather than to the topic of the reader.
+func Consume(ctx context.Context, reader Reader, handler Handler, opts ...Option) error {
+	options := evaluateOptions(opts)
+	guarded := WrapHandler(handler, options.handlerOpts...)
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			return err
+		}
+		if err = waitRetryAfter(ctx, msg); err != nil {
+			return err
+		}
+		if err = guarded(ctx, msg); err != nil {
+			if options.onError == nil {
+				return err
+			}
+			if err = options.onError(ctx, msg, err); err != nil {
+				return err
+			}
+		}
+		if err = reader.CommitMessages(ctx, msg); err != nil {
+			return err
+		}
+	}
+}
+
+// RequeueTo returns the requeue function of mq.Requeue, which writes the blocked message
+// to the topic with the RetryAfterHeader. The topic of the writer is used if topic is empty.
+// The topic is expected to be a dedicated retry topic, see Consume.
+func RequeueTo(writer Writer, topic string) func(ctx context.Context, msg *mq.Message, delay time.Duration) error {
+	return func(ctx context.Context, msg *mq.Message, delay time.Duration) error {
+		retryAfter := strconv.FormatInt(time.Now().Add(delay).UnixMilli(), 10)
+		return writer.WriteMessages(ctx, forward(msg, topic, RetryAfterHeader, retryAfter))
+	}
+}
+
+// DeadLetterTo returns the dead letter function of mq.DeadLetter, which writes the blocked message
+// to the topic with the BlockReasonHeader. The topic of the writer is used if topic is empty.
+func DeadLetterTo(writer Writer, topic string) func(ctx context.Context, msg *mq.Message, blockErr *base.BlockError) error {
+	return func(ctx context.Context, msg *mq.Message, blockErr *base.BlockError) error {
+		return writer.WriteMessages(ctx, forward(msg, topic, BlockReasonHeader, blockErr.Error()))
+	}
+}
+
+// forward returns the message written to the topic, with the header set to the value.
+func forward(msg *mq.Message, topic, header, value string) kafkago.Message {
+	headers := make([]kafkago.Header, 0, len(msg.Headers)+1)
+	if raw, ok := msg.Raw.(kafkago.Message); ok {
+		// keep the order of the headers
+		for _, h := range raw.Headers {
+			if h.Key != header {
+				headers = append(headers, h)
+			}
+		}
+	} else {
+		for k, v := range msg.Headers {
+			if k != header {
+				headers = append(headers, kafkago.Header{Key: k, Value: []byte(v)})
+			}
+		}
+	}
+	headers = append(headers, kafkago.Header{Key: header, Value: []byte(value)})
+	return kafkago.Message{
+		Topic:   topic,
+		Key:     []byte(msg.Key),
+		Value:   msg.Value,
+		Headers: headers,
+	}
+}
+
+// waitRetryAfter waits until the RetryAfterHeader of the message.
+func waitRetryAfter(ctx context.Context, msg kafkago.Message) error {
+	for _, h := range msg.Headers {
+		if h.Key != RetryAfterHeader {
+			continue
+		}
+		retryAfter, err := strconv.ParseInt(string(h.Value), 10, 64)
+		if err != nil {
+			return nil
+		}
+		wait := time.Until(time.UnixMilli(retryAfter))
+		if wait <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/mq"
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBroker is the in-memory Reader and Writer, the reader returns io.EOF once drained.
+type fakeBroker struct {
+	mux       sync.Mutex
+	messages  []kafkago.Message
+	committed []kafkago.Message
+	written   []kafkago.Message
+}
+
+func (b *fakeBroker) FetchMessage(context.Context) (kafkago.Message, error) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if len(b.messages) == 0 {
+		return kafkago.Message{}, io.EOF
+	}
+	msg := b.messages[0]
+	b.messages = b.messages[1:]
+	return msg, nil
+}
+
+func (b *fakeBroker) CommitMessages(_ context.Context, msgs ...kafkago.Message) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.committed = append(b.committed, msgs...)
+	return nil
+}
+
+func (b *fakeBroker) WriteMessages(_ context.Context, msgs ...kafkago.Message) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.written = append(b.written, msgs...)
+	return nil
+}
+
+func initSentinel(t *testing.T) {
+	err := sentinel.InitDefault()
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	_, err = flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "blocked",
+			Threshold:              0,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+}
+
+func TestConsume(t *testing.T) {
+	initSentinel(t)
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		broker := &fakeBroker{messages: []kafkago.Message{
+			{Topic: "orders", Key: []byte("1"), Offset: 1},
+			{Topic: "orders", Key: []byte("2"), Offset: 2},
+		}}
+		var keys []string
+		err := Consume(ctx, broker, func(ctx context.Context, msg kafkago.Message) error {
+			keys = append(keys, string(msg.Key))
+			return nil
+		})
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, []string{"1", "2"}, keys)
+		assert.Len(t, broker.committed, 2)
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		errHandle := errors.New("handle error")
+		newBroker := func() *fakeBroker {
+			return &fakeBroker{messages: []kafkago.Message{
+				{Topic: "orders", Key: []byte("bad"), Offset: 1},
+				{Topic: "orders", Key: []byte("1"), Offset: 2},
+			}}
+		}
+		handler := func(ctx context.Context, msg kafkago.Message) error {
+			if string(msg.Key) == "bad" {
+				return errHandle
+			}
+			return nil
+		}
+
+		// stop on the first error by default
+		broker := newBroker()
+		err := Consume(ctx, broker, handler)
+		assert.Equal(t, errHandle, err)
+		assert.Empty(t, broker.committed)
+
+		// skip the bad message
+		broker = newBroker()
+		var skipped []string
+		err = Consume(ctx, broker, handler, WithErrorHandler(func(ctx context.Context, msg kafkago.Message, err error) error {
+			skipped = append(skipped, string(msg.Key))
+			return nil
+		}))
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, []string{"bad"}, skipped)
+		assert.Len(t, broker.committed, 2)
+
+		// stop with the error of the error handler
+		broker = newBroker()
+		errStop := errors.New("stop")
+		err = Consume(ctx, broker, handler, WithErrorHandler(func(ctx context.Context, msg kafkago.Message, err error) error {
+			return errStop
+		}))
+		assert.Equal(t, errStop, err)
+		assert.Empty(t, broker.committed)
+	})
+
+	t.Run("dead letter", func(t *testing.T) {
+		broker := &fakeBroker{messages: []kafkago.Message{
+			{Topic: "blocked", Key: []byte("1"), Headers: []kafkago.Header{{Key: "tenant", Value: []byte("t1")}}},
+		}}
+		err := Consume(ctx, broker, func(ctx context.Context, msg kafkago.Message) error {
+			t.Fatal("the blocked message should not be handled")
+			return nil
+		}, WithHandlerOptions(mq.WithBlockPolicy(mq.DeadLetter(DeadLetterTo(broker, "blocked-dlq")))))
+		assert.Equal(t, io.EOF, err)
+		assert.Len(t, broker.committed, 1)
+		assert.Len(t, broker.written, 1)
+		dead := broker.written[0]
+		assert.Equal(t, "blocked-dlq", dead.Topic)
+		assert.Equal(t, []byte("1"), dead.Key)
+		assert.Equal(t, "tenant", dead.Headers[0].Key)
+		assert.Equal(t, BlockReasonHeader, dead.Headers[1].Key)
+	})
+
+	t.Run("requeue", func(t *testing.T) {
+		broker := &fakeBroker{messages: []kafkago.Message{{Topic: "blocked"}}}
+		err := Consume(ctx, broker, func(ctx context.Context, msg kafkago.Message) error {
+			return nil
+		}, WithHandlerOptions(mq.WithBlockPolicy(mq.Requeue(RequeueTo(broker, "blocked-retry"), time.Minute))))
+		assert.Equal(t, io.EOF, err)
+		assert.Len(t, broker.written, 1)
+		requeued := broker.written[0]
+		assert.Equal(t, "blocked-retry", requeued.Topic)
+		assert.Equal(t, RetryAfterHeader, requeued.Headers[0].Key)
+		retryAfter, err := strconv.ParseInt(string(requeued.Headers[0].Value), 10, 64)
+		assert.Nil(t, err)
+		assert.True(t, retryAfter > time.Now().Add(59*time.Second).UnixMilli())
+	})
+
+	t.Run("retry after", func(t *testing.T) {
+		retryAfter := strconv.FormatInt(time.Now().Add(100*time.Millisecond).UnixMilli(), 10)
+		broker := &fakeBroker{messages: []kafkago.Message{
+			{Topic: "orders-retry", Headers: []kafkago.Header{{Key: RetryAfterHeader, Value: []byte(retryAfter)}}},
+		}}
+		start := time.Now()
+		err := Consume(ctx, broker, func(ctx context.Context, msg kafkago.Message) error {
+			return nil
+		})
+		assert.Equal(t, io.EOF, err)
+		assert.True(t, time.Since(start) >= 90*time.Millisecond)
+		assert.Len(t, broker.committed, 1)
+	})
+}
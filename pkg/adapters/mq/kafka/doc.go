@@ -0,0 +1,30 @@
+/*
+This package provides Sentinel integration for github.com/segmentio/kafka-go consumers.
+
+Users may consume the messages of the *kafka.Reader with Sentinel, like.
+
+	import (
+		"github.com/Danceiny/sentinel-golang/pkg/adapters/mq"
+		sentinelPlugin "github.com/Danceiny/sentinel-golang/pkg/adapters/mq/kafka"
+		"github.com/segmentio/kafka-go"
+	)
+
+	reader := kafka.NewReader(kafka.ReaderConfig{Brokers: brokers, GroupID: "group", Topic: "orders"})
+	writer := &kafka.Writer{Addr: kafka.TCP(brokers...)}
+	err := sentinelPlugin.Consume(ctx, reader, func(ctx context.Context, msg kafka.Message) error {
+		// handle the message
+		return nil
+	}, sentinelPlugin.WithHandlerOptions(
+		mq.WithBlockPolicy(mq.Requeue(sentinelPlugin.RequeueTo(writer, "orders-retry"), time.Second)),
+	))
+
+The blocked message is written to the topic with RetryAfterHeader by RequeueTo, and Consume
+waits until the RetryAfterHeader of the message before handling it. As the waiting message blocks
+the messages behind it in the same partition, the retry topic (e.g. "orders-retry") should be dedicated
+and consumed by another Consume, rather than the topic of the reader. DeadLetterTo writes the blocked
+message to the dead letter topic with BlockReasonHeader.
+
+Consume stops with the first error of the handler by default. Users may decide whether to go on
+per error via WithErrorHandler, e.g. skip the message which fails to be parsed.
+*/
+package kafka
@@ -0,0 +1,40 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/mq"
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+type (
+	Option  func(*options)
+	options struct {
+		handlerOpts []mq.Option
+		onError     func(ctx context.Context, msg kafkago.Message, err error) error
+	}
+)
+
+func evaluateOptions(opts []Option) *options {
+	optCopy := &options{}
+	for _, opt := range opts {
+		opt(optCopy)
+	}
+	return optCopy
+}
+
+// WithHandlerOptions sets the options of the handler guarded by Sentinel, see mq.WrapHandler.
+func WithHandlerOptions(handlerOpts ...mq.Option) Option {
+	return func(opts *options) {
+		opts.handlerOpts = handlerOpts
+	}
+}
+
+// WithErrorHandler sets the handler of the error returned by the message handler. The message is committed
+// and the consumption goes on if the error handler returns nil, otherwise Consume stops with the returned error.
+// Consume stops with the first error of the message handler by default.
+func WithErrorHandler(fn func(ctx context.Context, msg kafkago.Message, err error) error) Option {
+	return func(opts *options) {
+		opts.onError = fn
+	}
+}
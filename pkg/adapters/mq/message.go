@@ -0,0 +1,18 @@
+package mq
+
+import (
+	"context"
+)
+
+// Message is the message consumed from the message queue.
+type Message struct {
+	Topic   string
+	Key     string
+	Headers map[string]string
+	Value   []byte
+	// Raw is the underlying message of the message queue client, e.g. kafka.Message.
+	Raw interface{}
+}
+
+// Handler handles the consumed message, the message is considered consumed if nil is returned.
+type Handler func(ctx context.Context, msg *Message) error
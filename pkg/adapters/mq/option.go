@@ -0,0 +1,86 @@
+package mq
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultWaitInterval is the interval of retrying the entry of the blocked message of the Wait policy.
+const DefaultWaitInterval = 100 * time.Millisecond
+
+type (
+	Option  func(*options)
+	options struct {
+		resourceExtract func(ctx context.Context, msg *Message) string
+		argsExtract     func(ctx context.Context, msg *Message) []interface{}
+		headerArgs      []string
+		onBlock         BlockPolicy
+	}
+)
+
+func evaluateOptions(opts []Option) *options {
+	optCopy := &options{
+		onBlock: Wait(DefaultWaitInterval),
+	}
+	for _, opt := range opts {
+		opt(optCopy)
+	}
+	return optCopy
+}
+
+// WithResourceExtractor sets the resource extractor of the message, the topic by default.
+func WithResourceExtractor(fn func(ctx context.Context, msg *Message) string) Option {
+	return func(opts *options) {
+		opts.resourceExtract = fn
+	}
+}
+
+// WithHeaderArgs appends the values of the headers to the args of hotspot parameter flow control
+// in order, following the key of the message. The absent header is passed as empty string.
+func WithHeaderArgs(headers ...string) Option {
+	return func(opts *options) {
+		opts.headerArgs = headers
+	}
+}
+
+// WithArgsExtractor sets the args extractor of hotspot parameter flow control,
+// which takes precedence over the key and WithHeaderArgs.
+func WithArgsExtractor(fn func(ctx context.Context, msg *Message) []interface{}) Option {
+	return func(opts *options) {
+		opts.argsExtract = fn
+	}
+}
+
+// WithBlockPolicy sets the policy of the blocked message, Wait(DefaultWaitInterval) by default.
+func WithBlockPolicy(policy BlockPolicy) Option {
+	return func(opts *options) {
+		opts.onBlock = policy
+	}
+}
+
+func (o *options) resourceNameOf(ctx context.Context, msg *Message) string {
+	if o.resourceExtract != nil {
+		return o.resourceExtract(ctx, msg)
+	}
+	return msg.Topic
+}
+
+func (o *options) argsOf(ctx context.Context, msg *Message) []interface{} {
+	if o.argsExtract != nil {
+		return o.argsExtract(ctx, msg)
+	}
+	args := make([]interface{}, 0, len(o.headerArgs)+1)
+	args = append(args, msg.Key)
+	for _, header := range o.headerArgs {
+		args = append(args, msg.Headers[header])
+	}
+	return args
+}
+
+// blockPolicyOf returns the block policy, which is never nil.
+func (o *options) blockPolicyOf() BlockPolicy {
+	if o.onBlock == nil {
+		return Wait(DefaultWaitInterval)
+	}
+	return o.onBlock
+}
@@ -0,0 +1,47 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+)
+
+// errRetry indicates that the entry of the blocked message should be retried.
+var errRetry = errors.New("retry the entry of the blocked message")
+
+// BlockPolicy handles the message blocked by Sentinel rules, the returned error
+// is returned by the guarded handler. The message is considered consumed if nil is returned.
+type BlockPolicy func(ctx context.Context, msg *Message, blockErr *base.BlockError) error
+
+// Wait returns the BlockPolicy which pauses the consumer and retries the entry of the blocked message
+// every interval until it passes, the error of the context is returned if the context is done.
+func Wait(interval time.Duration) BlockPolicy {
+	return func(ctx context.Context, _ *Message, _ *base.BlockError) error {
+		timer := time.NewTimer(interval)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return errRetry
+		}
+	}
+}
+
+// Requeue returns the BlockPolicy which requeues the blocked message via the requeue function,
+// which is expected to redeliver the message after the delay (e.g. via the delayed message of the broker).
+func Requeue(requeue func(ctx context.Context, msg *Message, delay time.Duration) error, delay time.Duration) BlockPolicy {
+	return func(ctx context.Context, msg *Message, _ *base.BlockError) error {
+		return requeue(ctx, msg, delay)
+	}
+}
+
+// DeadLetter returns the BlockPolicy which sends the blocked message to the dead letter queue
+// via the deadLetter function.
+func DeadLetter(deadLetter func(ctx context.Context, msg *Message, blockErr *base.BlockError) error) BlockPolicy {
+	return func(ctx context.Context, msg *Message, blockErr *base.BlockError) error {
+		return deadLetter(ctx, msg, blockErr)
+	}
+}
@@ -0,0 +1,43 @@
+/*
+This package provides Sentinel integration for the standard library net/http.
+
+For server side, users may wrap the http.Handler with SentinelMiddleware, like.
+
+	import (
+		sentinelPlugin "github.com/Danceiny/sentinel-golang/pkg/adapters/nethttp"
+	)
+
+	mux := http.NewServeMux()
+	http.ListenAndServe(":8080", sentinelPlugin.SentinelMiddleware()(mux))
+
+The middleware extracts "HttpMethod:Path" as the resource name by default (e.g. GET:/foo),
+where the identifier segments of the path are masked (e.g. GET:/users/:id) by resname.NormalizePath
+to avoid high cardinality resources. Users may provide customized resource name extractor
+via WithResourceExtractor option, e.g. matching the route templates via resname.NewPathNormalizer.
+The response of 5xx status code and the panic of the handler (see WithPanicTrace) are traced as errors,
+and the response status code is recorded as the entry pair of StatusCodePairKey.
+
+Users may declare the flow and isolation rules next to the route registration via Limit, like.
+
+	mux.Handle("GET /foo/{id}", sentinelPlugin.Limit("GET", "/foo/{id}",
+		routerule.WithFlowRules(&flow.Rule{Threshold: 100, StatIntervalInMs: 1000}))(sentinelPlugin.SentinelMiddleware()(fooHandler)))
+
+The rules are registered once for the resource of the route (e.g. GET:/foo/{id}), which is used by the inner
+SentinelMiddleware, and the rules of the same resource loaded from the datasource take precedence over them.
+
+For client side, users may wrap the http.RoundTripper with NewRoundTripper,
+which also works with httputil.ReverseProxy, like.
+
+	client := &http.Client{Transport: sentinelPlugin.NewRoundTripper(http.DefaultTransport)}
+
+The RoundTripper extracts "HttpMethod:HostPath" as the resource name by default (e.g. GET:example.com/foo),
+where the path is normalized as well.
+The transport error and the response of the status codes set by WithErrorStatusCodes (5xx by default)
+are traced as errors, and the host of the request is traced via api.TraceCallee for outlier ejection.
+
+Fallback logic: the middleware will return "429 Too Many Requests" status code
+and the RoundTripper will return the *base.BlockError if current request is blocked by Sentinel rules.
+Users may also provide customized fallback logic via WithBlockFallback(handler)
+and WithClientBlockFallback(handler) options.
+*/
+package nethttp
@@ -0,0 +1,44 @@
+package nethttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Danceiny/sentinel-golang/logging"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/routerule"
+)
+
+type routeResourceKey struct{}
+
+// Limit returns the per-route middleware which declares the flow and isolation rules of the route
+// of the given method and pattern, the rules are declared by routerule.WithFlowRules and
+// routerule.WithIsolationRules, like.
+//
+//	mux.Handle("GET /articles/{slug}", nethttp.Limit("GET", "/articles/{slug}",
+//		routerule.WithFlowRules(&flow.Rule{Threshold: 100, StatIntervalInMs: 1000}))(nethttp.SentinelMiddleware()(articleHandler)))
+//
+// The rules with empty resource are bound to the resource of the route, which is {method}:{pattern},
+// such as "GET:/articles/{slug}". The rules are registered once when Limit is called, as the default rules
+// (see flow.RegisterDefaultRules), so the rules of the same resource loaded from the datasource win,
+// and the conflicts with the rules declared by other routes are logged.
+// The resource of the route is used by the inner SentinelMiddleware of the route as the resource name,
+// so that all the requests of the route share the same resource. If the routes are guarded by a single
+// SentinelMiddleware wrapping the mux instead, which runs before Limit, the route patterns should be
+// matched by its resource extractor, e.g. via resname.NewPathNormalizer.
+func Limit(method, pattern string, opts ...routerule.Option) func(http.Handler) http.Handler {
+	resource := method + ":" + pattern
+	if err := routerule.Register(resource, opts...); err != nil {
+		logging.Warn("[Sentinel] Failed to register the rules of the route", "resource", resource, "reason", err.Error())
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), routeResourceKey{}, resource)))
+		})
+	}
+}
+
+// routeResourceOf returns the resource of the route declared by Limit.
+func routeResourceOf(r *http.Request) (string, bool) {
+	resource, ok := r.Context().Value(routeResourceKey{}).(string)
+	return resource, ok
+}
@@ -0,0 +1,82 @@
+package nethttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/isolation"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/routerule"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimit(t *testing.T) {
+	initSentinel(t)
+	defer func() {
+		flow.ClearDefaultRules()
+		isolation.ClearDefaultRules()
+		_ = flow.ClearRules()
+	}()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("pong"))
+	})
+	newRule := func(threshold float64) routerule.Option {
+		return routerule.WithFlowRules(&flow.Rule{
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			Threshold:              threshold,
+			StatIntervalInMs:       1000,
+		})
+	}
+
+	t.Run("RouteRules", func(t *testing.T) {
+		const resource = "GET:/articles/{slug}"
+		mux := http.NewServeMux()
+		mux.Handle("GET /articles/{slug}", Limit(http.MethodGet, "/articles/{slug}",
+			newRule(2),
+			routerule.WithIsolationRules(&isolation.Rule{MetricType: isolation.Concurrency, Threshold: 10}),
+		)(SentinelMiddleware()(ok)))
+		// the rules are registered on declaration
+		assert.Equal(t, 1, len(flow.GetRulesOfResource(resource)))
+		assert.Equal(t, 1, len(isolation.GetRulesOfResource(resource)))
+
+		for _, slug := range []string{"foo", "bar"} {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles/"+slug, nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+		// the requests of distinct paths share the resource of the route
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles/baz", nil))
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, 1, len(flow.GetRulesOfResource(resource)))
+	})
+
+	t.Run("LoadedRulesWin", func(t *testing.T) {
+		const resource = "GET:/loaded"
+		_, err := flow.LoadRulesOfResource(resource, []*flow.Rule{
+			{
+				Resource:               resource,
+				TokenCalculateStrategy: flow.Direct,
+				ControlBehavior:        flow.Reject,
+				Threshold:              0,
+				StatIntervalInMs:       1000,
+			},
+		})
+		assert.Nil(t, err)
+		h := Limit(http.MethodGet, "/loaded", newRule(100))(SentinelMiddleware()(ok))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/loaded", nil))
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, 0.0, flow.GetRulesOfResource(resource)[0].Threshold)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		Limit(http.MethodGet, "/conflict", newRule(1))
+		// the conflicting rules are logged and the existing ones are kept
+		Limit(http.MethodGet, "/conflict", newRule(2))
+		assert.Equal(t, 1.0, flow.GetRulesOfResource("GET:/conflict")[0].Threshold)
+	})
+}
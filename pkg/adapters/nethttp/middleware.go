@@ -0,0 +1,94 @@
+package nethttp
+
+import (
+	"net/http"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httpfallback"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/resname"
+)
+
+// SentinelMiddleware returns the middleware which wraps the http.Handler with Sentinel entry.
+// Default resource name is {method}:{path}, such as "GET:/api/users", where the identifier segments
+// of the path are masked by resname.NormalizePath, such as "GET:/api/users/:id",
+// or the resource of the route declared by Limit if any.
+// Default block fallback is responding the result of the fallback handler registered for the resource
+// (see fallback.RegisterHandler), or returning 429 code if there is none
+// The response of 5xx status code (see WithErrorStatusCodes) and the panic are traced as errors,
+// and the response status code is recorded as the entry pair of StatusCodePairKey.
+// Define your own behavior by setting options
+func SentinelMiddleware(opts ...Option) func(http.Handler) http.Handler {
+	options := evaluateOptions(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resourceName := r.Method + ":" + resname.NormalizePath(r.URL.Path)
+			if routeResource, ok := routeResourceOf(r); ok {
+				resourceName = routeResource
+			}
+			if options.resourceExtract != nil {
+				resourceName = options.resourceExtract(r)
+			}
+			entry, blockErr := sentinel.Entry(
+				resourceName,
+				sentinel.WithResourceType(base.ResTypeWeb),
+				sentinel.WithTrafficType(base.Inbound),
+			)
+			if blockErr != nil {
+				if options.blockFallback != nil {
+					options.blockFallback(w, r, blockErr)
+				} else if contentType, body, ok := httpfallback.Invoke(r.Context(), resourceName, blockErr); ok {
+					w.Header().Set("Content-Type", contentType)
+					w.WriteHeader(http.StatusOK)
+					_, _ = w.Write(body)
+				} else {
+					http.Error(w, "Blocked by Sentinel", http.StatusTooManyRequests)
+				}
+				return
+			}
+			defer entry.Exit()
+			if options.tracePanic {
+				defer func() {
+					httptrace.TracePanic(entry, recover())
+				}()
+			}
+
+			sw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(sw, r)
+			httptrace.TraceStatusCode(entry, sw.status, nil, options.isErrorStatus)
+		})
+	}
+}
+
+// statusResponseWriter records the status code written to the http.ResponseWriter.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying http.ResponseWriter, which is used by http.ResponseController.
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,41 @@
+package nethttp
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+)
+
+func Example() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	// Proxy the requests to the backend with the Sentinel RoundTripper.
+	backend, _ := url.Parse("http://127.0.0.1:8081")
+	proxy := httputil.NewSingleHostReverseProxy(backend)
+	proxy.Transport = NewRoundTripper(http.DefaultTransport,
+		// trace 502 and 503 as errors
+		WithErrorStatusCodes(http.StatusBadGateway, http.StatusServiceUnavailable),
+	)
+	mux.Handle("/backend/", proxy)
+
+	handler := SentinelMiddleware(
+		// customize resource extractor if required
+		// method_path by default
+		WithResourceExtractor(func(r *http.Request) string {
+			return r.Header.Get("X-Real-IP")
+		}),
+		// customize block fallback if required
+		// abort with status 429 by default
+		WithBlockFallback(func(w http.ResponseWriter, r *http.Request, blockErr *base.BlockError) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"err":"too many requests; the quota used up","code":10222}`))
+		}),
+	)(mux)
+
+	_ = http.ListenAndServe(":1323", handler)
+}
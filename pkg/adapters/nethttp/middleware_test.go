@@ -0,0 +1,196 @@
+package nethttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/fallback"
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httpfallback"
+	"github.com/stretchr/testify/assert"
+)
+
+func initSentinel(t *testing.T) {
+	err := sentinel.InitDefault()
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	_, err = flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "GET:/ping",
+			Threshold:              1.0,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			StatIntervalInMs:       1000,
+		},
+		{
+			Resource:               "/api/users",
+			Threshold:              0.0,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			StatIntervalInMs:       1000,
+		},
+		{
+			Resource:               "GET:blocked.example.com/foo",
+			Threshold:              0.0,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			StatIntervalInMs:       1000,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+		return
+	}
+}
+
+// statusCodeSlot records the status code pairs of the completed entries.
+type statusCodeSlot struct {
+	mux         sync.Mutex
+	statusCodes map[string]interface{}
+}
+
+func (s *statusCodeSlot) Order() uint32 {
+	return 9000
+}
+
+func (s *statusCodeSlot) OnEntryPassed(_ *base.EntryContext) {
+}
+
+func (s *statusCodeSlot) OnEntryBlocked(_ *base.EntryContext, _ *base.BlockError) {
+}
+
+func (s *statusCodeSlot) OnCompleted(ctx *base.EntryContext) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.statusCodes[ctx.Resource.Name()] = ctx.GetPair(StatusCodePairKey)
+}
+
+func (s *statusCodeSlot) statusCodeOf(resource string) interface{} {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.statusCodes[resource]
+}
+
+var testStatusCodeSlot = &statusCodeSlot{statusCodes: make(map[string]interface{})}
+
+func init() {
+	sentinel.GlobalSlotChain().AddStatSlot(testStatusCodeSlot)
+}
+
+func errorCountOf(resource string) int64 {
+	node := stat.GetResourceNode(resource)
+	if node == nil {
+		return 0
+	}
+	return node.GetSum(base.MetricEventError)
+}
+
+func TestSentinelMiddleware(t *testing.T) {
+	initSentinel(t)
+	defer func() {
+		_ = flow.ClearRules()
+	}()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("pong"))
+	})
+
+	t.Run("DefaultResource", func(t *testing.T) {
+		h := SentinelMiddleware()(ok)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "pong", w.Body.String())
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+
+	t.Run("NormalizedResource", func(t *testing.T) {
+		h := SentinelMiddleware()(ok)
+		for _, path := range []string{"/users/1", "/users/2"} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+		assert.Equal(t, int64(2), stat.GetResourceNode("GET:/users/:id").GetSum(base.MetricEventPass))
+		assert.Nil(t, stat.GetResourceNode("GET:/users/1"))
+	})
+
+	t.Run("CustomizedResourceAndFallback", func(t *testing.T) {
+		h := SentinelMiddleware(
+			WithResourceExtractor(func(r *http.Request) string {
+				return r.URL.Path
+			}),
+			WithBlockFallback(func(w http.ResponseWriter, r *http.Request, blockErr *base.BlockError) {
+				w.WriteHeader(http.StatusBadRequest)
+			}),
+		)(ok)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/users", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("RegisteredFallback", func(t *testing.T) {
+		assert.NoError(t, fallback.RegisterHandler("/api/users", func(ctx context.Context, resource string, err error) (interface{}, error) {
+			return map[string]string{"resource": resource}, nil
+		}))
+		defer fallback.ClearHandlers()
+
+		h := SentinelMiddleware(WithResourceExtractor(func(r *http.Request) string {
+			return r.URL.Path
+		}))(ok)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, httpfallback.ContentTypeJSON, w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"resource":"/api/users"}`, w.Body.String())
+	})
+
+	t.Run("TraceErrorStatus", func(t *testing.T) {
+		h := SentinelMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("fail") != "" {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/status", nil))
+		assert.Equal(t, int64(0), errorCountOf("GET:/status"))
+		assert.Equal(t, http.StatusNotFound, testStatusCodeSlot.statusCodeOf("GET:/status"))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/status?fail=1", nil))
+		assert.Equal(t, int64(1), errorCountOf("GET:/status"))
+		assert.Equal(t, http.StatusBadGateway, testStatusCodeSlot.statusCodeOf("GET:/status"))
+
+		h = SentinelMiddleware(WithErrorStatusCodes(http.StatusNotFound))(h)
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/status", nil))
+		assert.Equal(t, int64(1), errorCountOf("PUT:/status"))
+	})
+
+	t.Run("TracePanic", func(t *testing.T) {
+		h := SentinelMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("oops")
+		}))
+		assert.PanicsWithValue(t, "oops", func() {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+		})
+		assert.Equal(t, int64(1), errorCountOf("GET:/panic"))
+
+		h = SentinelMiddleware(WithPanicTrace(false))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("oops")
+		}))
+		assert.Panics(t, func() {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic_untraced", nil))
+		})
+		assert.Equal(t, int64(0), errorCountOf("GET:/panic_untraced"))
+	})
+}
@@ -0,0 +1,82 @@
+package nethttp
+
+import (
+	"net/http"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
+)
+
+// StatusCodePairKey is the key of the entry pair which records the response status code.
+const StatusCodePairKey = httptrace.StatusCodePairKey
+
+type (
+	Option  func(*options)
+	options struct {
+		resourceExtract func(*http.Request) string
+		blockFallback   func(http.ResponseWriter, *http.Request, *base.BlockError)
+
+		clientResourceExtract func(*http.Request) string
+		clientBlockFallback   func(*http.Request, *base.BlockError) (*http.Response, error)
+
+		isErrorStatus func(int) bool
+		tracePanic    bool
+	}
+)
+
+func evaluateOptions(opts []Option) *options {
+	optCopy := &options{
+		isErrorStatus: httptrace.IsServerErrorStatus,
+		tracePanic:    true,
+	}
+	for _, opt := range opts {
+		opt(optCopy)
+	}
+
+	return optCopy
+}
+
+// WithResourceExtractor sets the resource extractor of the server side request.
+func WithResourceExtractor(fn func(*http.Request) string) Option {
+	return func(opts *options) {
+		opts.resourceExtract = fn
+	}
+}
+
+// WithBlockFallback sets the block fallback handler of the server side request.
+func WithBlockFallback(fn func(http.ResponseWriter, *http.Request, *base.BlockError)) Option {
+	return func(opts *options) {
+		opts.blockFallback = fn
+	}
+}
+
+// WithClientResourceExtractor sets the resource extractor of the client side request.
+func WithClientResourceExtractor(fn func(*http.Request) string) Option {
+	return func(opts *options) {
+		opts.clientResourceExtract = fn
+	}
+}
+
+// WithClientBlockFallback sets the block fallback handler of the client side request,
+// the returned response and error are returned by the RoundTripper instead.
+func WithClientBlockFallback(fn func(*http.Request, *base.BlockError) (*http.Response, error)) Option {
+	return func(opts *options) {
+		opts.clientBlockFallback = fn
+	}
+}
+
+// WithErrorStatusCodes sets the response status codes which are traced as errors, 5xx by default.
+func WithErrorStatusCodes(codes ...int) Option {
+	isErrorStatus := httptrace.ErrorStatusCodes(codes...)
+	return func(opts *options) {
+		opts.isErrorStatus = isErrorStatus
+	}
+}
+
+// WithPanicTrace sets whether the panic of the handler is traced as an error, true by default.
+// The panic is re-panicked after being traced.
+func WithPanicTrace(enabled bool) Option {
+	return func(opts *options) {
+		opts.tracePanic = enabled
+	}
+}
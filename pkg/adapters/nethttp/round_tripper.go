@@ -0,0 +1,70 @@
+package nethttp
+
+import (
+	"net/http"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/fallback"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/httptrace"
+	"github.com/Danceiny/sentinel-golang/pkg/adapters/resname"
+)
+
+type roundTripper struct {
+	next    http.RoundTripper
+	options *options
+}
+
+// NewRoundTripper wraps the http.RoundTripper with Sentinel entry, http.DefaultTransport is used if next is nil.
+// Default resource name is {method}:{host}{path}, such as "GET:example.com/api/users", where the identifier
+// segments of the path are masked by resname.NormalizePath, such as "GET:example.com/api/users/:id"
+// Default block fallback is returning the *base.BlockError
+// The transport error and the response of 5xx status code (see WithErrorStatusCodes) are traced as errors,
+// and the host of the request is traced as the callee for outlier ejection.
+// Note that the entry exits once the response header is received, rather than the response body is closed.
+func NewRoundTripper(next http.RoundTripper, opts ...Option) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &roundTripper{
+		next:    next,
+		options: evaluateOptions(opts),
+	}
+}
+
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resourceName := req.Method + ":" + req.URL.Host + resname.NormalizePath(req.URL.Path)
+	if t.options.clientResourceExtract != nil {
+		resourceName = t.options.clientResourceExtract(req)
+	}
+	entry, blockErr := sentinel.Entry(
+		resourceName,
+		sentinel.WithResourceType(base.ResTypeWeb),
+		sentinel.WithTrafficType(base.Outbound),
+	)
+	if blockErr != nil {
+		if t.options.clientBlockFallback != nil {
+			return t.options.clientBlockFallback(req, blockErr)
+		}
+		// consult the fallback registry by default, whose handler may provide the *http.Response
+		result, err := fallback.Invoke(req.Context(), resourceName, blockErr)
+		if err != nil {
+			return nil, err
+		}
+		resp, ok := result.(*http.Response)
+		if !ok {
+			return nil, blockErr
+		}
+		return resp, nil
+	}
+	defer entry.Exit()
+
+	resp, err := t.next.RoundTrip(req)
+	sentinel.TraceCallee(entry, req.URL.Host)
+	if err != nil {
+		sentinel.TraceError(entry, err)
+	} else {
+		httptrace.TraceStatusCode(entry, resp.StatusCode, nil, t.options.isErrorStatus)
+	}
+	return resp, err
+}
@@ -0,0 +1,89 @@
+package nethttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRoundTripper(t *testing.T) {
+	initSentinel(t)
+	defer func() {
+		_ = flow.ClearRules()
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	host := server.Listener.Addr().String()
+
+	t.Run("Pass", func(t *testing.T) {
+		client := &http.Client{Transport: NewRoundTripper(nil)}
+		resp, err := client.Get(server.URL + "/ok")
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int64(0), errorCountOf("GET:"+host+"/ok"))
+
+		resp, err = client.Get(server.URL + "/fail")
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, int64(1), errorCountOf("GET:"+host+"/fail"))
+		assert.Equal(t, http.StatusServiceUnavailable, testStatusCodeSlot.statusCodeOf("GET:"+host+"/fail"))
+	})
+
+	t.Run("TransportError", func(t *testing.T) {
+		transportErr := errors.New("connection refused")
+		rt := NewRoundTripper(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, transportErr
+		}))
+		_, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "http://unreachable.example.com/foo", nil))
+		assert.Equal(t, transportErr, err)
+		assert.Equal(t, int64(1), errorCountOf("GET:unreachable.example.com/foo"))
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		rt := NewRoundTripper(nil)
+		req := &http.Request{Method: http.MethodGet, URL: &url.URL{Scheme: "http", Host: "blocked.example.com", Path: "/foo"}}
+		_, err := rt.RoundTrip(req)
+		assert.IsType(t, &base.BlockError{}, err)
+
+		rt = NewRoundTripper(nil, WithClientBlockFallback(func(req *http.Request, blockErr *base.BlockError) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusTooManyRequests, Request: req}, nil
+		}))
+		resp, err := rt.RoundTrip(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	})
+
+	t.Run("CustomizedResourceAndErrorStatus", func(t *testing.T) {
+		client := &http.Client{Transport: NewRoundTripper(nil,
+			WithClientResourceExtractor(func(req *http.Request) string {
+				return "downstream"
+			}),
+			WithErrorStatusCodes(http.StatusOK),
+		)}
+		resp, err := client.Get(server.URL + "/ok")
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, int64(1), errorCountOf("downstream"))
+	})
+}
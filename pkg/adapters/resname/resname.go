@@ -0,0 +1,128 @@
+package resname
+
+import (
+	"strings"
+)
+
+// Mask is the placeholder of the masked path segments.
+const Mask = ":id"
+
+// NormalizePath returns the path with the query stripped and the segments which look like identifiers
+// (numbers, UUIDs and long hexadecimal strings) replaced with Mask, e.g. "/users/1024/orders"
+// turns into "/users/:id/orders", so that the paths of the same route share the same resource.
+func NormalizePath(path string) string {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isIdentifier(segment) {
+			segments[i] = Mask
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isIdentifier indicates whether the segment is a number, UUID or hexadecimal string of at least 16 chars
+// which contains digits.
+func isIdentifier(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	digits, hyphens := 0, 0
+	for _, c := range segment {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F':
+		case c == '-':
+			hyphens++
+		default:
+			return false
+		}
+	}
+	switch {
+	case digits == len(segment):
+		return true
+	case hyphens == 0:
+		return digits > 0 && len(segment) >= 16
+	default:
+		return isUUID(segment)
+	}
+}
+
+// isUUID indicates whether the hexadecimal segment is in the form of 8-4-4-4-12.
+func isUUID(segment string) bool {
+	if len(segment) != 36 {
+		return false
+	}
+	for _, i := range []int{8, 13, 18, 23} {
+		if segment[i] != '-' {
+			return false
+		}
+	}
+	return strings.Count(segment, "-") == 4
+}
+
+// PathNormalizer normalizes the path by the route templates, like "/users/:id", "/users/{id}"
+// and "/static/*filepath". The segment of ":name", "{name}" or "*" matches any single segment,
+// and the last segment of "*name" or "{name...}" matches the rest of the path.
+type PathNormalizer struct {
+	templates []routeTemplate
+}
+
+type routeTemplate struct {
+	template string
+	segments []string
+}
+
+// NewPathNormalizer returns new PathNormalizer of the route templates, which are matched in order.
+func NewPathNormalizer(templates ...string) *PathNormalizer {
+	n := &PathNormalizer{templates: make([]routeTemplate, 0, len(templates))}
+	for _, template := range templates {
+		n.templates = append(n.templates, routeTemplate{
+			template: template,
+			segments: strings.Split(template, "/"),
+		})
+	}
+	return n
+}
+
+// Normalize returns the first route template matching the path, or NormalizePath(path) if none matches.
+func (n *PathNormalizer) Normalize(path string) string {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(path, "/")
+	for _, t := range n.templates {
+		if t.match(segments) {
+			return t.template
+		}
+	}
+	return NormalizePath(path)
+}
+
+func (t routeTemplate) match(segments []string) bool {
+	for i, ts := range t.segments {
+		if isCatchAll(ts) && i == len(t.segments)-1 {
+			return len(segments) >= i
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if ts != segments[i] && !isParam(ts) {
+			return false
+		}
+	}
+	return len(segments) == len(t.segments)
+}
+
+func isParam(segment string) bool {
+	return segment == "*" || strings.HasPrefix(segment, ":") ||
+		(strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"))
+}
+
+func isCatchAll(segment string) bool {
+	return (strings.HasPrefix(segment, "*") && len(segment) > 1) ||
+		(strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "...}"))
+}
@@ -0,0 +1,34 @@
+package resname
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePath(t *testing.T) {
+	cases := map[string]string{
+		"/":                        "/",
+		"/users":                   "/users",
+		"/users/1024/orders":       "/users/:id/orders",
+		"/users/1024?verbose=true": "/users/:id",
+		"/orders/3f2504e0-4f89-11d3-9a0c-0305e82c3301": "/orders/:id",
+		"/blobs/5d41402abc4b2a76b9719d911017c592":      "/blobs/:id",
+		"/api/v1/users": "/api/v1/users",
+		"/feed/facade":  "/feed/facade",
+		"/tags/go-1":    "/tags/go-1",
+	}
+	for path, expected := range cases {
+		assert.Equal(t, expected, NormalizePath(path), path)
+	}
+}
+
+func TestPathNormalizer(t *testing.T) {
+	n := NewPathNormalizer("/users/:name", "/users/{name}/posts/{id}", "/static/*filepath", "/files/{path...}")
+	assert.Equal(t, "/users/:name", n.Normalize("/users/alice"))
+	assert.Equal(t, "/users/{name}/posts/{id}", n.Normalize("/users/alice/posts/hello-world?page=2"))
+	assert.Equal(t, "/static/*filepath", n.Normalize("/static/css/site.css"))
+	assert.Equal(t, "/files/{path...}", n.Normalize("/files/a/b/c"))
+	// fall back to NormalizePath if no template matches
+	assert.Equal(t, "/orders/:id/items", n.Normalize("/orders/42/items"))
+}
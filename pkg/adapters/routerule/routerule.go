@@ -0,0 +1,86 @@
+package routerule
+
+import (
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/isolation"
+	"github.com/pkg/errors"
+)
+
+type (
+	// Option declares the rules of the route.
+	Option  func(*options)
+	options struct {
+		flowRules      []*flow.Rule
+		isolationRules []*isolation.Rule
+	}
+)
+
+// WithFlowRules declares the flow rules of the route.
+func WithFlowRules(rules ...*flow.Rule) Option {
+	return func(opts *options) {
+		opts.flowRules = append(opts.flowRules, rules...)
+	}
+}
+
+// WithIsolationRules declares the isolation rules of the route.
+func WithIsolationRules(rules ...*isolation.Rule) Option {
+	return func(opts *options) {
+		opts.isolationRules = append(opts.isolationRules, rules...)
+	}
+}
+
+func evaluateOptions(opts []Option) *options {
+	optCopy := &options{}
+	for _, opt := range opts {
+		opt(optCopy)
+	}
+	return optCopy
+}
+
+// Register registers the flow and isolation rules declared for the route as the default rules
+// (see flow.RegisterDefaultRules), so that the rules of the same resource loaded from the datasource win.
+// The rules with empty resource are bound to the given resource of the route, the declared rules are not modified.
+// The error is returned if the rules conflict with the rules declared before, e.g. by other routes of the same resource.
+func Register(resource string, opts ...Option) error {
+	options := evaluateOptions(opts)
+	flowRules := make([]*flow.Rule, 0, len(options.flowRules))
+	for _, r := range options.flowRules {
+		if r == nil {
+			return errors.New("nil flow rule of the route")
+		}
+		flowRules = append(flowRules, bindFlowRule(*r, resource))
+	}
+	isolationRules := make([]*isolation.Rule, 0, len(options.isolationRules))
+	for _, r := range options.isolationRules {
+		if r == nil {
+			return errors.New("nil isolation rule of the route")
+		}
+		isolationRules = append(isolationRules, bindIsolationRule(*r, resource))
+	}
+
+	if len(flowRules) > 0 {
+		if err := flow.RegisterDefaultRules(flowRules); err != nil {
+			return errors.Wrap(err, "failed to register the flow rules of the route")
+		}
+	}
+	if len(isolationRules) > 0 {
+		if err := isolation.RegisterDefaultRules(isolationRules); err != nil {
+			return errors.Wrap(err, "failed to register the isolation rules of the route")
+		}
+	}
+	return nil
+}
+
+func bindFlowRule(r flow.Rule, resource string) *flow.Rule {
+	if len(r.Resource) == 0 {
+		r.Resource = resource
+	}
+	return &r
+}
+
+func bindIsolationRule(r isolation.Rule, resource string) *isolation.Rule {
+	if len(r.Resource) == 0 {
+		r.Resource = resource
+	}
+	return &r
+}
@@ -0,0 +1,47 @@
+package routerule
+
+import (
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/isolation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	defer func() {
+		flow.ClearDefaultRules()
+		isolation.ClearDefaultRules()
+	}()
+
+	rule := &flow.Rule{
+		TokenCalculateStrategy: flow.Direct,
+		ControlBehavior:        flow.Reject,
+		Threshold:              10,
+		StatIntervalInMs:       1000,
+	}
+	assert.Nil(t, Register("GET:/users/:id",
+		WithFlowRules(rule, &flow.Rule{
+			Resource:               "explicit",
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			Threshold:              1,
+			StatIntervalInMs:       1000,
+		}),
+		WithIsolationRules(&isolation.Rule{MetricType: isolation.Concurrency, Threshold: 5}),
+	))
+	assert.Equal(t, 10.0, flow.GetRulesOfResource("GET:/users/:id")[0].Threshold)
+	assert.Equal(t, 1.0, flow.GetRulesOfResource("explicit")[0].Threshold)
+	assert.Equal(t, uint32(5), isolation.GetRulesOfResource("GET:/users/:id")[0].Threshold)
+	// the declared rule is not modified
+	assert.Empty(t, rule.Resource)
+
+	// registering the same rules again is fine, while the conflicting rules are reported
+	assert.Nil(t, Register("GET:/users/:id", WithFlowRules(rule)))
+	rule.Threshold = 20
+	assert.NotNil(t, Register("GET:/users/:id", WithFlowRules(rule)))
+	assert.Equal(t, 10.0, flow.GetRulesOfResource("GET:/users/:id")[0].Threshold)
+
+	assert.NotNil(t, Register("GET:/users/:id", WithFlowRules(nil)))
+	assert.NotNil(t, Register("GET:/users/:id", WithIsolationRules(nil)))
+}
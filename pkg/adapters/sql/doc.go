@@ -0,0 +1,32 @@
+/*
+This package provides Sentinel integration for database/sql.
+
+Users may wrap the driver.Driver or driver.Connector with Sentinel, like.
+
+	import (
+		sentinelPlugin "github.com/Danceiny/sentinel-golang/pkg/adapters/sql"
+		"github.com/mattn/go-sqlite3"
+	)
+
+	sentinelPlugin.Register("sentinel-sqlite3", &sqlite3.SQLiteDriver{})
+	db, err := sql.Open("sentinel-sqlite3", ":memory:")
+
+	// or wrap the connector
+	db := sentinelPlugin.OpenDB(connector)
+
+Each query executed by the wrapped driver creates an outbound entry of base.ResTypeDBSQL.
+The plugin names the resource by the fingerprint of the statement by default
+(e.g. "select * from users where id = ?"). Users may name the resource by the table
+or the operation of the statement via WithResourceNaming option, or provide customized
+resource name extractor via WithResourceExtractor option.
+
+The args of the query are passed to the entry as the args of hotspot parameter flow control,
+which could be disabled via WithoutArgs option. The driver errors are traced as errors,
+so that the error metrics and circuit breakers work out of the box.
+Note that the entry of the query exits once the rows are returned, the iteration of the rows is not included.
+
+Fallback logic: the plugin will return the *base.BlockError by default
+if current query is blocked by Sentinel rules. Users may also
+provide customized fallback logic via WithBlockFallback(handler) options.
+*/
+package sql
@@ -0,0 +1,118 @@
+package sql
+
+import (
+	"context"
+	stdsql "database/sql"
+	"database/sql/driver"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+)
+
+type wrappedDriver struct {
+	driver.Driver
+	options *options
+}
+
+// wrappedDriverContext is the wrappedDriver whose underlying driver implements driver.DriverContext.
+type wrappedDriverContext struct {
+	*wrappedDriver
+}
+
+type wrappedConnector struct {
+	driver.Connector
+	options *options
+}
+
+// Wrap wraps the driver.Driver with Sentinel, the queries of the connections opened
+// by the returned driver are guarded by Sentinel entries.
+func Wrap(d driver.Driver, opts ...Option) driver.Driver {
+	return wrapDriver(d, evaluateOptions(opts))
+}
+
+// WrapConnector wraps the driver.Connector with Sentinel, the queries of the connections opened
+// by the returned connector are guarded by Sentinel entries.
+func WrapConnector(c driver.Connector, opts ...Option) driver.Connector {
+	return &wrappedConnector{Connector: c, options: evaluateOptions(opts)}
+}
+
+// Register registers the driver wrapped with Sentinel under the given name, like.
+//
+//	Register("sentinel-mysql", &mysql.MySQLDriver{})
+//	db, err := sql.Open("sentinel-mysql", dsn)
+func Register(name string, d driver.Driver, opts ...Option) {
+	stdsql.Register(name, Wrap(d, opts...))
+}
+
+// OpenDB opens the *sql.DB with the driver.Connector wrapped with Sentinel.
+func OpenDB(c driver.Connector, opts ...Option) *stdsql.DB {
+	return stdsql.OpenDB(WrapConnector(c, opts...))
+}
+
+func wrapDriver(d driver.Driver, options *options) driver.Driver {
+	w := &wrappedDriver{Driver: d, options: options}
+	if _, ok := d.(driver.DriverContext); ok {
+		return &wrappedDriverContext{w}
+	}
+	return w
+}
+
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedConn{Conn: c, options: d.options}, nil
+}
+
+func (d *wrappedDriverContext) OpenConnector(name string) (driver.Connector, error) {
+	c, err := d.Driver.(driver.DriverContext).OpenConnector(name)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedConnector{Connector: c, options: d.options}, nil
+}
+
+func (c *wrappedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedConn{Conn: conn, options: c.options}, nil
+}
+
+func (c *wrappedConnector) Driver() driver.Driver {
+	return wrapDriver(c.Connector.Driver(), c.options)
+}
+
+// entry creates the outbound entry of the query, the returned error is not nil if blocked.
+func (o *options) entry(ctx context.Context, query string, args []driver.NamedValue) (*base.SentinelEntry, error) {
+	entryOpts := []sentinel.EntryOption{
+		sentinel.WithResourceType(base.ResTypeDBSQL),
+		sentinel.WithTrafficType(base.Outbound),
+	}
+	if !o.disableArgs && len(args) > 0 {
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			values[i] = arg.Value
+		}
+		entryOpts = append(entryOpts, sentinel.WithArgs(values...))
+	}
+	entry, blockErr := sentinel.Entry(o.resourceNameOf(ctx, query), entryOpts...)
+	if blockErr != nil {
+		if o.blockFallback != nil {
+			if err := o.blockFallback(ctx, query, blockErr); err != nil {
+				return nil, err
+			}
+		}
+		return nil, blockErr
+	}
+	return entry, nil
+}
+
+// traceError traces the driver error, except driver.ErrSkip which indicates the fallback of database/sql.
+func traceError(entry *base.SentinelEntry, err error) {
+	if err != nil && err != driver.ErrSkip {
+		sentinel.TraceError(entry, err)
+	}
+}
@@ -0,0 +1,311 @@
+package sql
+
+import (
+	"context"
+	stdsql "database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+var errFakeDriver = errors.New("fake driver error")
+
+// fakeDriver is an in-memory driver, the query containing "fail" fails.
+type fakeDriver struct {
+	// directQuery indicates whether the connection executes the queries directly,
+	// otherwise database/sql prepares the statements.
+	directQuery bool
+}
+
+type fakeConn struct {
+	directQuery bool
+}
+
+type fakeDirectConn struct {
+	fakeConn
+}
+
+type fakeStmt struct {
+	query string
+}
+
+type fakeRows struct {
+	done bool
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	if d.directQuery {
+		return &fakeDirectConn{}, nil
+	}
+	return &fakeConn{}, nil
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeDirectConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	return execute(query)
+}
+
+func (c *fakeDirectConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if _, err := execute(query); err != nil {
+		return nil, err
+	}
+	return &fakeRows{}, nil
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return execute(s.query)
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	if _, err := execute(s.query); err != nil {
+		return nil, err
+	}
+	return &fakeRows{}, nil
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"id"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+func execute(query string) (driver.Result, error) {
+	if Operation(query) == "FAIL" {
+		return nil, errFakeDriver
+	}
+	return driver.RowsAffected(1), nil
+}
+
+// argsSlot records the args of the entries.
+type argsSlot struct {
+	mux  sync.Mutex
+	args map[string][]interface{}
+}
+
+func (s *argsSlot) Order() uint32 {
+	return 9000
+}
+
+func (s *argsSlot) OnEntryPassed(ctx *base.EntryContext) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.args[ctx.Resource.Name()] = ctx.Input.Args
+}
+
+func (s *argsSlot) OnEntryBlocked(_ *base.EntryContext, _ *base.BlockError) {
+}
+
+func (s *argsSlot) OnCompleted(_ *base.EntryContext) {
+}
+
+func (s *argsSlot) argsOf(resource string) []interface{} {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.args[resource]
+}
+
+var testArgsSlot = &argsSlot{args: make(map[string][]interface{})}
+
+func init() {
+	sentinel.GlobalSlotChain().AddStatSlot(testArgsSlot)
+	Register("sentinel-fake", &fakeDriver{directQuery: true})
+	Register("sentinel-fake-stmt", &fakeDriver{})
+}
+
+func initSentinel(t *testing.T) {
+	err := sentinel.InitDefault()
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	_, err = flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "select id from users where id = ?",
+			Threshold:              1.0,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			StatIntervalInMs:       1000,
+		},
+		{
+			Resource:               "DELETE:orders",
+			Threshold:              0.0,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			StatIntervalInMs:       1000,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	initSentinel(t)
+	defer func() {
+		_ = flow.ClearRules()
+	}()
+
+	for _, driverName := range []string{"sentinel-fake", "sentinel-fake-stmt"} {
+		t.Run(driverName, func(t *testing.T) {
+			db, err := stdsql.Open(driverName, "")
+			assert.NoError(t, err)
+			defer db.Close()
+
+			resource := Fingerprint("update users set name = ? where id = ? -- " + driverName)
+			_, err = db.Exec("UPDATE users SET name = ? WHERE id = ? -- "+driverName, "foo", 1)
+			assert.NoError(t, err)
+			assert.Equal(t, []interface{}{"foo", int64(1)}, testArgsSlot.argsOf(resource))
+
+			_, err = db.Exec("FAIL " + driverName)
+			assert.Equal(t, errFakeDriver, err)
+			assert.Equal(t, int64(1), stat.GetResourceNode(Fingerprint("FAIL "+driverName)).GetSum(base.MetricEventError))
+		})
+	}
+
+	t.Run("BlockedQuery", func(t *testing.T) {
+		db, err := stdsql.Open("sentinel-fake", "")
+		assert.NoError(t, err)
+		defer db.Close()
+
+		var id int64
+		assert.NoError(t, db.QueryRow("SELECT id FROM users WHERE id = ?", 1).Scan(&id))
+		assert.Equal(t, int64(1), id)
+		err = db.QueryRow("SELECT id FROM users WHERE id = ?", 2).Scan(&id)
+		assert.IsType(t, &base.BlockError{}, err)
+	})
+
+	t.Run("BlockFallbackWithConnector", func(t *testing.T) {
+		errBlocked := errors.New("too many queries")
+		connector := &fakeConnector{driver: &fakeDriver{}}
+		db := OpenDB(connector,
+			WithResourceNaming(ByTable),
+			WithBlockFallback(func(ctx context.Context, query string, blockErr *base.BlockError) error {
+				return errBlocked
+			}),
+		)
+		defer db.Close()
+
+		_, err := db.Exec("DELETE FROM orders WHERE id = ?", 1)
+		assert.Equal(t, errBlocked, err)
+		_, err = db.Exec("DELETE FROM users WHERE id = ?", 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("NilBlockFallback", func(t *testing.T) {
+		db := OpenDB(&fakeConnector{driver: &fakeDriver{}},
+			WithResourceNaming(ByTable),
+			WithBlockFallback(func(ctx context.Context, query string, blockErr *base.BlockError) error {
+				return nil
+			}),
+		)
+		defer db.Close()
+
+		_, err := db.Exec("DELETE FROM orders WHERE id = ?", 1)
+		assert.IsType(t, &base.BlockError{}, err)
+	})
+}
+
+type fakeConnector struct {
+	driver driver.Driver
+}
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open("")
+}
+
+func (c *fakeConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type cents int64
+
+// checkerConn converts cents into int64.
+type checkerConn struct {
+	fakeConn
+}
+
+func (c *checkerConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if v, ok := nv.Value.(cents); ok {
+		nv.Value = int64(v)
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+// converterStmt converts the first arg into string.
+type converterStmt struct {
+	fakeStmt
+}
+
+func (s *converterStmt) NumInput() int {
+	return 1
+}
+
+func (s *converterStmt) ColumnConverter(int) driver.ValueConverter {
+	return stringConverter{}
+}
+
+type stringConverter struct{}
+
+func (stringConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	return fmt.Sprint(v), nil
+}
+
+func TestWrappedStmtCheckNamedValue(t *testing.T) {
+	stmt := &wrappedStmt{Stmt: &converterStmt{}, conn: &checkerConn{}}
+
+	nv := &driver.NamedValue{Ordinal: 1, Value: cents(100)}
+	assert.NoError(t, stmt.CheckNamedValue(nv))
+	assert.Equal(t, int64(100), nv.Value)
+
+	nv = &driver.NamedValue{Ordinal: 1, Value: 1}
+	assert.NoError(t, stmt.CheckNamedValue(nv))
+	assert.Equal(t, "1", nv.Value)
+
+	// beyond the inputs of the statement
+	nv = &driver.NamedValue{Ordinal: 2, Value: 1}
+	assert.NoError(t, stmt.CheckNamedValue(nv))
+	assert.Equal(t, 1, nv.Value)
+
+	stmt = &wrappedStmt{Stmt: &fakeStmt{}, conn: &fakeConn{}}
+	assert.Equal(t, driver.ErrSkip, stmt.CheckNamedValue(&driver.NamedValue{Ordinal: 1, Value: 1}))
+}
@@ -0,0 +1,99 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+)
+
+// ResourceNaming indicates how the resource name of the query is generated.
+type ResourceNaming int32
+
+const (
+	// ByFingerprint names the resource by the fingerprint of the statement, e.g. "select * from users where id = ?".
+	ByFingerprint ResourceNaming = iota
+	// ByTable names the resource by the operation and the first table of the statement, e.g. "SELECT:users".
+	ByTable
+	// ByOperation names the resource by the operation of the statement, e.g. "SELECT".
+	ByOperation
+)
+
+type (
+	Option  func(*options)
+	options struct {
+		resourceNaming  ResourceNaming
+		resourceExtract func(ctx context.Context, query string) string
+		blockFallback   func(ctx context.Context, query string, blockErr *base.BlockError) error
+		disableArgs     bool
+		// resourceNames caches the resource names generated by resourceNaming.
+		resourceNames *resourceNameCache
+	}
+)
+
+func evaluateOptions(opts []Option) *options {
+	optCopy := &options{
+		resourceNaming: ByFingerprint,
+	}
+	for _, opt := range opts {
+		opt(optCopy)
+	}
+	optCopy.resourceNames = newResourceNameCache(resourceNameCacheSize)
+	return optCopy
+}
+
+// WithResourceNaming sets how the resource name of the query is generated, ByFingerprint by default.
+func WithResourceNaming(naming ResourceNaming) Option {
+	return func(opts *options) {
+		opts.resourceNaming = naming
+	}
+}
+
+// WithResourceExtractor sets the resource extractor of the query, which takes precedence over WithResourceNaming.
+func WithResourceExtractor(fn func(ctx context.Context, query string) string) Option {
+	return func(opts *options) {
+		opts.resourceExtract = fn
+	}
+}
+
+// WithBlockFallback sets the fallback handler when the query is blocked,
+// the returned error is returned to the caller. The *base.BlockError is returned by default,
+// as well as when the handler returns nil.
+func WithBlockFallback(fn func(ctx context.Context, query string, blockErr *base.BlockError) error) Option {
+	return func(opts *options) {
+		opts.blockFallback = fn
+	}
+}
+
+// WithoutArgs disables passing the query args to the entry as the args of hotspot parameter flow control.
+func WithoutArgs() Option {
+	return func(opts *options) {
+		opts.disableArgs = true
+	}
+}
+
+func (o *options) resourceNameOf(ctx context.Context, query string) string {
+	if o.resourceExtract != nil {
+		return o.resourceExtract(ctx, query)
+	}
+	if name, ok := o.resourceNames.get(query); ok {
+		return name
+	}
+	name := o.generateResourceName(query)
+	o.resourceNames.add(query, name)
+	return name
+}
+
+func (o *options) generateResourceName(query string) string {
+	switch o.resourceNaming {
+	case ByTable:
+		op := Operation(query)
+		if table := Table(query); table != "" {
+			return op + ":" + table
+		}
+		return op
+	case ByOperation:
+		return Operation(query)
+	default:
+		return Fingerprint(query)
+	}
+}
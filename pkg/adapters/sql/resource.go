@@ -0,0 +1,143 @@
+package sql
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/Danceiny/sentinel-golang/core/hotspot/cache"
+)
+
+// resourceNameCacheSize is the max number of the queries whose resource names are cached.
+const resourceNameCacheSize = 1024
+
+var (
+	placeholderListRegex = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	tableRegex           = regexp.MustCompile("(?i)\\b(?:from|into|update|join|table)\\s+([`\"\\[]?[\\w.]+[`\"\\]]?)")
+)
+
+// Fingerprint returns the normalized statement of the query: comments are removed,
+// literals and placeholders are replaced with "?", the list of placeholders like "(?, ?, ?)"
+// is collapsed into "(?)", whitespaces are collapsed and the statement is lower cased,
+// so that the queries of the same statement share the same fingerprint.
+func Fingerprint(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	rs := []rune(query)
+	pendingSpace := false
+	writeRune := func(r rune) {
+		if pendingSpace && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		pendingSpace = false
+		b.WriteRune(r)
+	}
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == '-' && i+1 < len(rs) && rs[i+1] == '-':
+			// line comment
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+			pendingSpace = true
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			// block comment
+			i += 2
+			for i+1 < len(rs) && !(rs[i] == '*' && rs[i+1] == '/') {
+				i++
+			}
+			i++
+			pendingSpace = true
+		case r == '\'':
+			// string literal, the quote is escaped by doubling it or by backslash
+			for i++; i < len(rs); i++ {
+				if rs[i] == '\\' {
+					i++
+					continue
+				}
+				if rs[i] == '\'' {
+					if i+1 < len(rs) && rs[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			writeRune('?')
+		case unicode.IsDigit(r) && !isIdentifierTail(rs, i):
+			for i+1 < len(rs) && (unicode.IsDigit(rs[i+1]) || rs[i+1] == '.') {
+				i++
+			}
+			writeRune('?')
+		case (r == '$' || r == ':' || r == '@') && i+1 < len(rs) && (unicode.IsDigit(rs[i+1]) || unicode.IsLetter(rs[i+1])) &&
+			!(r == ':' && i > 0 && rs[i-1] == ':'):
+			// numbered or named placeholder, e.g. $1, :name and @name
+			for i+1 < len(rs) && (unicode.IsDigit(rs[i+1]) || unicode.IsLetter(rs[i+1]) || rs[i+1] == '_') {
+				i++
+			}
+			writeRune('?')
+		case unicode.IsSpace(r):
+			pendingSpace = true
+		default:
+			writeRune(unicode.ToLower(r))
+		}
+	}
+	return placeholderListRegex.ReplaceAllString(b.String(), "(?)")
+}
+
+// isIdentifierTail indicates whether the rune at i is a part of an identifier like "t1".
+func isIdentifierTail(rs []rune, i int) bool {
+	if i == 0 {
+		return false
+	}
+	prev := rs[i-1]
+	return unicode.IsLetter(prev) || unicode.IsDigit(prev) || prev == '_'
+}
+
+// Operation returns the upper cased operation (the first keyword) of the query, e.g. "SELECT".
+func Operation(query string) string {
+	fields := strings.Fields(strings.TrimLeft(query, "( \t\r\n"))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// Table returns the first table of the query, empty if absent.
+func Table(query string) string {
+	m := tableRegex.FindStringSubmatch(query)
+	if len(m) < 2 {
+		return ""
+	}
+	return strings.Trim(m[1], "`\"[]")
+}
+
+// resourceNameCache caches the resource names of the queries, so that the queries executed repeatedly,
+// e.g. the prepared statements, are not scanned on every execution.
+type resourceNameCache struct {
+	mux sync.Mutex
+	lru *cache.LRU
+}
+
+func newResourceNameCache(size int) *resourceNameCache {
+	lru, _ := cache.NewLRU(size, nil)
+	return &resourceNameCache{lru: lru}
+}
+
+func (c *resourceNameCache) get(query string) (string, bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	name, ok := c.lru.Get(query)
+	if !ok {
+		return "", false
+	}
+	return name.(string), true
+}
+
+func (c *resourceNameCache) add(query, name string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.lru.Add(query, name)
+}
@@ -0,0 +1,67 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT * FROM users WHERE id = 1", "select * from users where id = ?"},
+		{"select *  from users\n\twhere name = 'it''s' and age > 18.5", "select * from users where name = ? and age > ?"},
+		{"SELECT * FROM t1 WHERE id IN (1, 2, 3)", "select * from t1 where id in (?)"},
+		{"SELECT * FROM users WHERE id = $1 AND name = :name OR nick = @nick", "select * from users where id = ? and name = ? or nick = ?"},
+		{"INSERT INTO users (id, name) VALUES (?, ?)", "insert into users (id, name) values (?)"},
+		{"/* comment */ SELECT id FROM users -- trailing\n WHERE id = ?", "select id from users where id = ?"},
+		{"SELECT '\\'' FROM users", "select ? from users"},
+		{"SELECT id::text FROM users", "select id::text from users"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Fingerprint(tt.query), tt.query)
+	}
+}
+
+func TestOperationAndTable(t *testing.T) {
+	assert.Equal(t, "SELECT", Operation("  select * from users"))
+	assert.Equal(t, "", Operation(""))
+	assert.Equal(t, "users", Table("select * from `users` where id = ?"))
+	assert.Equal(t, "db.users", Table("INSERT INTO db.users VALUES (?)"))
+	assert.Equal(t, "users", Table("update users set name = ?"))
+	assert.Equal(t, "", Table("select 1"))
+}
+
+func TestResourceNameOf(t *testing.T) {
+	ctx := context.Background()
+	query := "SELECT * FROM users WHERE id = 1"
+	assert.Equal(t, "select * from users where id = ?", evaluateOptions(nil).resourceNameOf(ctx, query))
+	assert.Equal(t, "SELECT:users", evaluateOptions([]Option{WithResourceNaming(ByTable)}).resourceNameOf(ctx, query))
+	assert.Equal(t, "SELECT", evaluateOptions([]Option{WithResourceNaming(ByTable)}).resourceNameOf(ctx, "SELECT 1"))
+	assert.Equal(t, "SELECT", evaluateOptions([]Option{WithResourceNaming(ByOperation)}).resourceNameOf(ctx, query))
+	assert.Equal(t, "db", evaluateOptions([]Option{
+		WithResourceNaming(ByOperation),
+		WithResourceExtractor(func(ctx context.Context, query string) string {
+			return "db"
+		}),
+	}).resourceNameOf(ctx, query))
+}
+
+func TestResourceNameCache(t *testing.T) {
+	ctx := context.Background()
+	opts := evaluateOptions(nil)
+	opts.resourceNames = newResourceNameCache(1)
+	query := "SELECT * FROM users WHERE id = 1"
+	assert.Equal(t, "select * from users where id = ?", opts.resourceNameOf(ctx, query))
+	name, ok := opts.resourceNames.get(query)
+	assert.True(t, ok)
+	assert.Equal(t, "select * from users where id = ?", name)
+
+	// the least recently used query is evicted
+	assert.Equal(t, "select ?", opts.resourceNameOf(ctx, "SELECT 1"))
+	_, ok = opts.resourceNames.get(query)
+	assert.False(t, ok)
+}
@@ -0,0 +1,63 @@
+package sql
+
+import (
+	stdsql "database/sql"
+	"testing"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	Register("sentinel-sqlite3", &sqlite3.SQLiteDriver{}, WithResourceNaming(ByTable))
+}
+
+func TestWrapSQLite(t *testing.T) {
+	initSentinel(t)
+	_, err := flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               "DELETE:articles",
+			Threshold:              0.0,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			StatIntervalInMs:       1000,
+		},
+	})
+	assert.NoError(t, err)
+	defer func() {
+		_ = flow.ClearRules()
+	}()
+
+	db, err := stdsql.Open("sentinel-sqlite3", ":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+	// the in-memory database is per connection
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE articles (id INTEGER PRIMARY KEY, title TEXT, score REAL)")
+	assert.NoError(t, err)
+
+	stmt, err := db.Prepare("INSERT INTO articles (id, title, score) VALUES (?, ?, ?)")
+	assert.NoError(t, err)
+	defer stmt.Close()
+	// the args are converted by database/sql through the wrapped statement
+	_, err = stmt.Exec(uint8(1), "sentinel", float32(1.5))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), stat.GetResourceNode("INSERT:articles").GetSum(base.MetricEventComplete))
+
+	var title string
+	var score float64
+	assert.NoError(t, db.QueryRow("SELECT title, score FROM articles WHERE id = ?", 1).Scan(&title, &score))
+	assert.Equal(t, "sentinel", title)
+	assert.Equal(t, 1.5, score)
+
+	_, err = db.Exec("SELECT * FROM absent")
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), stat.GetResourceNode("SELECT:absent").GetSum(base.MetricEventError))
+
+	_, err = db.Exec("DELETE FROM articles WHERE id = ?", 1)
+	assert.IsType(t, &base.BlockError{}, err)
+}
@@ -0,0 +1,115 @@
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+type wrappedStmt struct {
+	driver.Stmt
+	// conn is the underlying connection which prepares the statement.
+	conn    driver.Conn
+	query   string
+	options *options
+}
+
+func (s *wrappedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	entry, err := s.options.entry(ctx, s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer entry.Exit()
+
+	var res driver.Result
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = execer.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			res, err = s.Stmt.Exec(values)
+		}
+	}
+	traceError(entry, err)
+	return res, err
+}
+
+// QueryContext guards the query until the rows are returned, the iteration of the rows is not included.
+func (s *wrappedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	entry, err := s.options.entry(ctx, s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer entry.Exit()
+
+	var rows driver.Rows
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	traceError(entry, err)
+	return rows, err
+}
+
+// CheckNamedValue checks the arg in the same order as database/sql does for the underlying statement,
+// which database/sql can't see through the wrapper: the NamedValueChecker of the statement or else
+// the one of the connection, and then the ColumnConverter of the statement.
+func (s *wrappedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	err := driver.ErrSkip
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		err = checker.CheckNamedValue(nv)
+	} else if checker, ok := s.conn.(driver.NamedValueChecker); ok {
+		err = checker.CheckNamedValue(nv)
+	}
+	if err != driver.ErrSkip {
+		return err
+	}
+	if converter, ok := s.Stmt.(driver.ColumnConverter); ok {
+		return convertColumn(converter, s.Stmt.NumInput(), nv)
+	}
+	// use the default conversion of database/sql
+	return driver.ErrSkip
+}
+
+// convertColumn converts the arg with the ColumnConverter of the statement, the same as database/sql.
+func convertColumn(converter driver.ColumnConverter, numInput int, nv *driver.NamedValue) error {
+	index := nv.Ordinal - 1
+	if numInput <= index {
+		return nil
+	}
+	if valuer, ok := nv.Value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return err
+		}
+		if !driver.IsValue(v) {
+			return fmt.Errorf("non-subset type %T returned from Value", v)
+		}
+		nv.Value = v
+	}
+	v, err := converter.ColumnConverter(index).ConvertValue(nv.Value)
+	if err != nil {
+		return err
+	}
+	if !driver.IsValue(v) {
+		return fmt.Errorf("driver ColumnConverter error converted %T to unsupported type %T", nv.Value, v)
+	}
+	nv.Value = v
+	return nil
+}
+
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
@@ -0,0 +1,28 @@
+/*
+This package provides Sentinel integration for Twirp.
+
+Users may register the Sentinel server hooks to the Twirp server, like.
+
+	import (
+		sentinelPlugin "github.com/Danceiny/sentinel-golang/pkg/adapters/twirp"
+		"github.com/twitchtv/twirp"
+	)
+
+	server := haberdasher.NewHaberdasherServer(impl, twirp.WithServerHooks(sentinelPlugin.NewServerHooks()))
+
+The hooks extract "/{package}.{service}/{method}" as the resource name by default
+(e.g. /twitch.twirp.example.Haberdasher/MakeHat), which is the same as the procedure of connect-go.
+Users may provide customized resource name extractor via WithResourceExtractor option.
+
+Error tracing: the errors of Unknown, DeadlineExceeded, Internal, Unavailable and DataLoss code
+are traced as errors by default, and the error code is recorded as the entry pair of CodePairKey.
+Users may customize the codes via WithErrorCodes option.
+
+Fallback logic: the hooks will respond the twirp error of ResourceExhausted code
+if current request is blocked by Sentinel rules, with the block type (e.g. "BlockTypeFlowControl")
+in the error metadata of BlockTypeMetaKey. Users may also provide customized fallback logic
+via WithBlockFallback(handler) option. If no block fallback option is provided, the hooks consult
+the fallback handlers registered for the resource in Sentinel core fallback registry, whose returned error
+is responded instead, while the result of the handlers is discarded.
+*/
+package twirp
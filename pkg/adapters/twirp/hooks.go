@@ -0,0 +1,79 @@
+package twirp
+
+import (
+	"context"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/fallback"
+	"github.com/twitchtv/twirp"
+)
+
+// BlockTypeMetaKey is the key of the error metadata which records the block type of the blocked request.
+const BlockTypeMetaKey = "sentinel_block_type"
+
+type entryKey struct{}
+
+// NewServerHooks creates the twirp.ServerHooks which wraps the request with Sentinel entry,
+// it could be chained with other hooks via twirp.ChainHooks.
+func NewServerHooks(opts ...Option) *twirp.ServerHooks {
+	options := evaluateOptions(opts)
+	return &twirp.ServerHooks{
+		RequestRouted: func(ctx context.Context) (context.Context, error) {
+			resourceName := procedureOf(ctx)
+			if options.resourceExtract != nil {
+				resourceName = options.resourceExtract(ctx)
+			}
+			entry, blockErr := sentinel.Entry(
+				resourceName,
+				sentinel.WithResourceType(base.ResTypeRPC),
+				sentinel.WithTrafficType(base.Inbound),
+			)
+			if blockErr != nil {
+				if options.blockFallback != nil {
+					if err := options.blockFallback(ctx, blockErr); err != nil {
+						return ctx, err
+					}
+				} else if _, err := fallback.Invoke(ctx, resourceName, blockErr); err != nil && err != error(blockErr) {
+					// consult the fallback registry by default, whose handler may replace the block error
+					return ctx, err
+				}
+				return ctx, NewBlockError(blockErr)
+			}
+			return context.WithValue(ctx, entryKey{}, entry), nil
+		},
+		Error: func(ctx context.Context, err twirp.Error) context.Context {
+			entry, ok := ctx.Value(entryKey{}).(*base.SentinelEntry)
+			if !ok {
+				return ctx
+			}
+			entry.SetPair(CodePairKey, err.Code())
+			if options.isErrorCode(err.Code()) {
+				sentinel.TraceError(entry, err)
+			}
+			return ctx
+		},
+		ResponseSent: func(ctx context.Context) {
+			if entry, ok := ctx.Value(entryKey{}).(*base.SentinelEntry); ok {
+				entry.Exit()
+			}
+		},
+	}
+}
+
+// NewBlockError converts the *base.BlockError to the twirp error of ResourceExhausted,
+// with the block type recorded as the error metadata of BlockTypeMetaKey.
+func NewBlockError(blockErr *base.BlockError) twirp.Error {
+	return twirp.NewError(twirp.ResourceExhausted, blockErr.Error()).
+		WithMeta(BlockTypeMetaKey, blockErr.BlockType().String())
+}
+
+// procedureOf returns the "/{package}.{service}/{method}" of the request.
+func procedureOf(ctx context.Context) string {
+	service, _ := twirp.ServiceName(ctx)
+	method, _ := twirp.MethodName(ctx)
+	if pkg, _ := twirp.PackageName(ctx); len(pkg) > 0 {
+		service = pkg + "." + service
+	}
+	return "/" + service + "/" + method
+}
@@ -0,0 +1,102 @@
+package twirp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sentinel "github.com/Danceiny/sentinel-golang/api"
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
+	"github.com/stretchr/testify/assert"
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/ctxsetters"
+)
+
+const procedure = "/sentinel.test.v1.EchoService/Echo"
+
+func initSentinel(t *testing.T) {
+	err := sentinel.InitDefault()
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+}
+
+func loadRule(t *testing.T, resource string, threshold float64) {
+	_, err := flow.LoadRulesOfResource(resource, []*flow.Rule{
+		{
+			Resource:               resource,
+			Threshold:              threshold,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			StatIntervalInMs:       1000,
+		},
+	})
+	assert.Nil(t, err)
+}
+
+// serve calls the hooks in the same way as the twirp generated server.
+func serve(hooks *twirp.ServerHooks, handlerErr twirp.Error) error {
+	ctx := ctxsetters.WithPackageName(context.Background(), "sentinel.test.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "EchoService")
+	ctx = ctxsetters.WithMethodName(ctx, "Echo")
+	ctx, err := hooks.RequestRouted(ctx)
+	if err == nil && handlerErr != nil {
+		err = handlerErr
+	}
+	if err != nil {
+		var twerr twirp.Error
+		if !errors.As(err, &twerr) {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		ctx = hooks.Error(ctx, twerr)
+	}
+	hooks.ResponseSent(ctx)
+	return err
+}
+
+func TestServerHooks(t *testing.T) {
+	initSentinel(t)
+	defer func() {
+		_ = flow.ClearRules()
+	}()
+
+	t.Run("Default", func(t *testing.T) {
+		hooks := NewServerHooks()
+		loadRule(t, procedure, 3)
+		assert.Nil(t, serve(hooks, nil))
+		assert.NotNil(t, serve(hooks, twirp.InvalidArgumentError("foo", "invalid")))
+		assert.Equal(t, int64(0), stat.GetResourceNode(procedure).GetSum(base.MetricEventError))
+		assert.NotNil(t, serve(hooks, twirp.InternalError("oops")))
+		assert.Equal(t, int64(1), stat.GetResourceNode(procedure).GetSum(base.MetricEventError))
+		assert.Equal(t, int32(0), stat.GetResourceNode(procedure).CurrentConcurrency())
+
+		err := serve(hooks, nil)
+		var twerr twirp.Error
+		assert.True(t, errors.As(err, &twerr))
+		assert.Equal(t, twirp.ResourceExhausted, twerr.Code())
+		assert.Equal(t, base.BlockTypeFlow.String(), twerr.Meta(BlockTypeMetaKey))
+	})
+
+	t.Run("Customized", func(t *testing.T) {
+		const resource = "customized"
+		hooks := NewServerHooks(
+			WithResourceExtractor(func(ctx context.Context) string {
+				return resource
+			}),
+			WithBlockFallback(func(ctx context.Context, blockErr *base.BlockError) twirp.Error {
+				return twirp.NewError(twirp.Unavailable, "busy")
+			}),
+			WithErrorCodes(twirp.InvalidArgument),
+		)
+		loadRule(t, resource, 1)
+		assert.NotNil(t, serve(hooks, twirp.InvalidArgumentError("foo", "invalid")))
+		assert.Equal(t, int64(1), stat.GetResourceNode(resource).GetSum(base.MetricEventError))
+
+		err := serve(hooks, nil)
+		var twerr twirp.Error
+		assert.True(t, errors.As(err, &twerr))
+		assert.Equal(t, twirp.Unavailable, twerr.Code())
+	})
+}
@@ -0,0 +1,72 @@
+package twirp
+
+import (
+	"context"
+
+	"github.com/Danceiny/sentinel-golang/core/base"
+	"github.com/twitchtv/twirp"
+)
+
+// CodePairKey is the key of the entry pair which records the twirp error code of the failed request.
+const CodePairKey = "sentinel.twirp.code"
+
+type (
+	Option  func(*options)
+	options struct {
+		resourceExtract func(context.Context) string
+		blockFallback   func(context.Context, *base.BlockError) twirp.Error
+		isErrorCode     func(twirp.ErrorCode) bool
+	}
+)
+
+func evaluateOptions(opts []Option) *options {
+	optCopy := &options{
+		isErrorCode: isServerErrorCode,
+	}
+	for _, opt := range opts {
+		opt(optCopy)
+	}
+
+	return optCopy
+}
+
+// WithResourceExtractor sets the resource extractor of the request,
+// the "/{package}.{service}/{method}" (e.g. "/acme.foo.v1.FooService/Bar") is the resource name by default.
+func WithResourceExtractor(fn func(context.Context) string) Option {
+	return func(opts *options) {
+		opts.resourceExtract = fn
+	}
+}
+
+// WithBlockFallback sets the block fallback handler of the request,
+// the returned error is responded instead, while the default block error
+// (see NewBlockError) is responded if the handler returns nil.
+func WithBlockFallback(fn func(context.Context, *base.BlockError) twirp.Error) Option {
+	return func(opts *options) {
+		opts.blockFallback = fn
+	}
+}
+
+// WithErrorCodes sets the twirp error codes which are traced as errors,
+// Unknown, DeadlineExceeded, Internal, Unavailable and DataLoss by default.
+func WithErrorCodes(codes ...twirp.ErrorCode) Option {
+	codeSet := make(map[twirp.ErrorCode]struct{}, len(codes))
+	for _, code := range codes {
+		codeSet[code] = struct{}{}
+	}
+	return func(opts *options) {
+		opts.isErrorCode = func(code twirp.ErrorCode) bool {
+			_, ok := codeSet[code]
+			return ok
+		}
+	}
+}
+
+func isServerErrorCode(code twirp.ErrorCode) bool {
+	switch code {
+	case twirp.Unknown, twirp.DeadlineExceeded, twirp.Internal, twirp.Unavailable, twirp.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/Danceiny/sentinel-golang/core/base"
 	"github.com/Danceiny/sentinel-golang/core/config"
 	"github.com/Danceiny/sentinel-golang/core/flow"
+	"github.com/Danceiny/sentinel-golang/core/stat"
 	"github.com/Danceiny/sentinel-golang/core/system_metric"
 	"github.com/Danceiny/sentinel-golang/logging"
 	"github.com/Danceiny/sentinel-golang/util"
@@ -127,3 +128,53 @@ func TestAdaptiveFlowControl2(t *testing.T) {
 	_, blockError := api.Entry(rs, api.WithTrafficType(base.Inbound))
 	assert.Nil(t, blockError)
 }
+
+func TestCustomMetricEventFlowControl(t *testing.T) {
+	initSentinel()
+	util.SetClock(util.NewMockClock())
+
+	cacheMiss, err := base.RegisterMetricEvent("cache_miss")
+	assert.Nil(t, err)
+	rs := "custom_event_res"
+	_, err = flow.LoadRules([]*flow.Rule{
+		{
+			Resource:               rs,
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			Threshold:              3,
+			StatIntervalInMs:       1000,
+			MetricEvent:            "cache_miss",
+		},
+	})
+	assert.Nil(t, err)
+
+	for i := 0; i < 2; i++ {
+		entry, blockError := api.Entry(rs, api.WithTrafficType(base.Inbound))
+		assert.Nil(t, blockError)
+		api.RecordEvent(entry, cacheMiss, 1)
+		entry.Exit()
+	}
+	// The passed requests without cache miss are not limited.
+	for i := 0; i < 5; i++ {
+		entry, blockError := api.Entry(rs, api.WithTrafficType(base.Inbound))
+		assert.Nil(t, blockError)
+		entry.Exit()
+	}
+	entry, blockError := api.Entry(rs, api.WithTrafficType(base.Inbound))
+	assert.Nil(t, blockError)
+	api.RecordEvent(entry, cacheMiss, 1)
+	entry.Exit()
+	_, blockError = api.Entry(rs, api.WithTrafficType(base.Inbound))
+	assert.NotNil(t, blockError)
+
+	node := stat.GetResourceNode(rs)
+	assert.Equal(t, int64(3), node.GetSum(cacheMiss))
+	assert.Equal(t, int64(3), stat.InboundNode().GetSum(cacheMiss))
+
+	// The statistic of the custom MetricEvent slides like the builtin ones.
+	util.Sleep(time.Second * 2)
+	entry, blockError = api.Entry(rs, api.WithTrafficType(base.Inbound))
+	assert.Nil(t, blockError)
+	entry.Exit()
+	assert.Equal(t, int64(0), node.GetSum(cacheMiss))
+}